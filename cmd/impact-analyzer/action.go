@@ -0,0 +1,211 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// actionInput returns a GitHub Action input, passed as the INPUT_<NAME> environment variable
+func actionInput(name, defaultValue string) string {
+	if v := strings.TrimSpace(os.Getenv("INPUT_" + strings.ToUpper(name))); v != "" {
+		return v
+	}
+	return defaultValue
+}
+
+// actionOptions reads the common options from the GitHub Action inputs
+func actionOptions() (commonOptions, error) {
+	opts := commonOptions{
+		baseBranch:  actionInput("base", "main"),
+		projectRoot: actionInput("root", ""),
+		modulePath:  actionInput("module", ""),
+		cmdDir:      actionInput("cmd_dir", "cli/cmd"),
+		pathPrefix:  actionInput("path_prefix", ""),
+		configPath:  actionInput("config", ""),
+		resources:   actionInput("resources", ""),
+		lang:        actionInput("lang", ""),
+		mode:        actionInput("mode", ""),
+		concurrency: runtime.GOMAXPROCS(0),
+		// Workflow logs are not terminals, but keep them free of escape codes regardless
+		noColor: true,
+	}
+	strict, err := strconv.ParseBool(actionInput("strict", "false"))
+	if err != nil {
+		return opts, fmt.Errorf("invalid input strict %q (want true or false)", actionInput("strict", ""))
+	}
+	opts.strict = strict
+	return opts, nil
+}
+
+// runAction runs the action subcommand, the entrypoint of the GitHub Action
+// It analyzes the git diff against the base input, writes the outputs to GITHUB_OUTPUT
+// and the result to the step summary (GITHUB_STEP_SUMMARY); with the comment input, the result is also
+// kept up to date in a comment of the pull request
+func runAction(args []string) {
+	// Inputs come from the environment; flags are rejected to catch misconfigured workflows
+	fs := flag.NewFlagSet("action", flag.ExitOnError)
+	fs.Parse(args)
+
+	opts, err := actionOptions()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	fileCfg := opts.loadConfig()
+	failOn := analyzer.Severity(actionInput("fail_on", string(fileCfg.FailOn)))
+	if failOn != "" && !failOn.IsValid() {
+		fmt.Fprintf(os.Stderr, "Error: invalid input fail_on %q\n", failOn)
+		os.Exit(1)
+	}
+	comment, err := strconv.ParseBool(actionInput("comment", "false"))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: invalid input comment %q (want true or false)\n", actionInput("comment", ""))
+		os.Exit(1)
+	}
+
+	targets, err := parseOutputs("text=-", opts.outputOptions())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Re-runs of the workflow on the same commits (e.g., re-requested checks) reuse the cached result
+	cache, err := newResultCache(actionInput("result_cache", fileCfg.ResultCache), actionInput("result_cache_ttl", fileCfg.ResultCacheTTL))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	cacheKey, cacheable := "", false
+	if cache != nil {
+		cacheKey, cacheable = cache.gitResultKey(&opts, actionSettings()...)
+	}
+	var result *output.AnalysisResult
+	if cacheable {
+		var ok bool
+		if result, ok = cache.get(cacheKey); ok {
+			fmt.Fprintf(os.Stderr, "Using the cached result of the same commits (%s)\n", cache.dir)
+		}
+	}
+	if result == nil {
+		a := opts.analyze(fileCfg)
+		if err := checkResourceNames(a, opts.resourceList()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+		changedFiles, allChangedFiles, err := gitChangedFiles(&opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		result = analyzeChangedFiles(a, changedFiles, a.GetIaCChanges(allChangedFiles))
+		if cacheable {
+			if err := cache.put(cacheKey, result); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+	}
+	result = forVersion(result, outputV1)
+	printResult(result, targets, false)
+
+	if err := writeActionOutputs(os.Getenv("GITHUB_OUTPUT"), result); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write action outputs: %v\n", err)
+		os.Exit(1)
+	}
+	if err := writeStepSummary(os.Getenv("GITHUB_STEP_SUMMARY"), result, opts.outputOptions()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to write step summary: %v\n", err)
+		os.Exit(1)
+	}
+	if comment {
+		if err := commentOnPullRequest(result, opts.outputOptions()); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to comment on the pull request: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	exitOnDegradation(result, opts.strict)
+	exitOnSeverity(result, failOn)
+}
+
+// actionSettings returns the inputs of the action, which the cached results depend on
+// The token is left out, as GitHub issues a new one for each job
+func actionSettings() []string {
+	var inputs []string
+	for _, env := range os.Environ() {
+		if strings.HasPrefix(env, "INPUT_") && !strings.HasPrefix(env, "INPUT_GITHUB_TOKEN=") {
+			inputs = append(inputs, env)
+		}
+	}
+	sort.Strings(inputs)
+	return inputs
+}
+
+// writeActionOutputs appends the outputs of the action to the GITHUB_OUTPUT file
+// Outputs are skipped when the file is not set (e.g., when run outside of GitHub Actions)
+func writeActionOutputs(path string, result *output.AnalysisResult) error {
+	if path == "" {
+		return nil
+	}
+
+	names := make([]string, 0, len(result.AffectedResources))
+	hasCritical := false
+	for _, r := range result.AffectedResources {
+		names = append(names, r.Name)
+		if r.Severity == analyzer.SeverityCritical {
+			hasCritical = true
+		}
+	}
+	// Compact JSON stays on one line, as required by the name=value format
+	affected, err := json.Marshal(names)
+	if err != nil {
+		return err
+	}
+
+	applications, err := json.Marshal(append([]string{}, result.Applications...))
+	if err != nil {
+		return err
+	}
+
+	return appendToFile(path, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "affected_resources=%s\ncount=%d\nhas_critical=%t\napplications=%s\n", affected, len(names), hasCritical, applications)
+		return err
+	})
+}
+
+// writeStepSummary appends the result in Markdown format to the GITHUB_STEP_SUMMARY file, if set
+func writeStepSummary(path string, result *output.AnalysisResult, opts output.Options) error {
+	if path == "" {
+		return nil
+	}
+	writer, err := output.New("markdown", opts)
+	if err != nil {
+		return err
+	}
+	return appendToFile(path, func(w io.Writer) error {
+		return writer.WriteAnalysisResult(w, result)
+	})
+}
+
+// appendToFile appends the output of write to a file, creating it if needed
+func appendToFile(path string, write func(w io.Writer) error) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// runAPIUsage runs the api-usage subcommand
+func runAPIUsage(args []string) {
+	var (
+		opts    commonOptions
+		pkgPath string
+	)
+
+	fs := flag.NewFlagSet("api-usage", flag.ExitOnError)
+	opts.register(fs)
+	opts.registerOutputs(fs)
+	fs.StringVar(&pkgPath, "package", "", "Package to report on (full or module-relative path)")
+	fs.Parse(args)
+
+	if pkgPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -package is required")
+		fmt.Fprintln(os.Stderr, "Usage: impact-analyzer api-usage -package=pkg/foo")
+		os.Exit(1)
+	}
+
+	fileCfg := opts.loadConfig()
+	targets, err := opts.reportTargets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	a := opts.analyze(fileCfg)
+
+	report, err := a.GetAPIUsage(a.ResolvePackagePath(pkgPath))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	printReport(apiUsageReport{report}, targets, false)
+}
+
+// apiUsageReport is the report of the usages of a package's exported symbols
+type apiUsageReport struct {
+	*analyzer.APIUsageReport
+}
+
+// WriteText outputs the API usage report in text format
+func (r apiUsageReport) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "=== API Usage: %s ===\n", r.Package)
+	fmt.Fprintln(&b)
+
+	if len(r.Symbols) == 0 {
+		fmt.Fprintln(&b, "No exported symbols")
+	}
+
+	for _, sym := range r.Symbols {
+		fmt.Fprintf(&b, "%s (%d usages)\n", sym.Symbol, sym.Count)
+		if len(sym.Packages) > 0 {
+			fmt.Fprintf(&b, "  Packages: %s\n", strings.Join(sym.Packages, ", "))
+		}
+		if len(sym.Resources) > 0 {
+			fmt.Fprintf(&b, "  Resources: %s\n", strings.Join(sym.Resources, ", "))
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// analyzeBases analyzes the changes relative to each base branch and combines the results
+// The combined result lists the changed files and affected resources of all bases; per-base impacts are in Bases
+func analyzeBases(a *analyzer.Analyzer, opts *commonOptions, bases []string) (*output.AnalysisResult, error) {
+	result := &output.AnalysisResult{
+		ImportViolations:  a.GetImportViolations(),
+		AffectedResources: []analyzer.AffectedResource{},
+		TotalResources:    len(a.GetResources()),
+	}
+
+	// The summary counts the changed symbols of each package relative to the first base changing it
+	changedSymbols := make(map[string][]string)
+	var allChangedFiles []string
+	// Timings of the analyses of each base add up
+	timings := []*analyzer.Timings{a.GetTimings()}
+	for _, base := range bases {
+		base = strings.TrimSpace(base)
+		if base == "" {
+			continue
+		}
+		baseOpts := *opts
+		baseOpts.baseBranch = base
+		changedFiles, baseAllFiles, err := gitChangedFiles(&baseOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		ba := a.WithBaseBranch(base)
+		impact := ba.GetImpact(changedFiles)
+		timings = append(timings, impact.Timings)
+		for pkgPath, symbols := range impact.ChangedSymbols {
+			if _, ok := changedSymbols[pkgPath]; !ok {
+				changedSymbols[pkgPath] = symbols
+			}
+		}
+		r := impactResult(ba, changedFiles, ba.GetIaCChanges(baseAllFiles), impact)
+		if changedFiles == nil {
+			changedFiles = []string{}
+		}
+		result.Bases = append(result.Bases, output.BaseImpact{
+			Base:              base,
+			ChangedFiles:      changedFiles,
+			AffectedResources: r.AffectedResources,
+		})
+
+		result.ChangedFiles = append(result.ChangedFiles, changedFiles...)
+		result.InfraChanges = append(result.InfraChanges, r.InfraChanges...)
+		result.AffectedResources = append(result.AffectedResources, r.AffectedResources...)
+		result.Warnings = append(result.Warnings, r.Warnings...)
+		allChangedFiles = append(allChangedFiles, changedFiles...)
+	}
+
+	// A resource affected relative to several bases is reported once, with the reason of the first base
+	result.ChangedFiles = uniqueStrings(result.ChangedFiles)
+	result.InfraChanges = uniqueInfraChanges(result.InfraChanges)
+	result.AffectedResources = uniqueAffectedResources(result.AffectedResources)
+	result.Warnings = uniqueWarnings(result.Warnings)
+	analyzer.SortAffectedResources(result.AffectedResources)
+	result.Images = analyzer.ImagesToRebuild(result.AffectedResources)
+	result.Applications = analyzer.ApplicationsToSync(result.AffectedResources)
+	result.Summary = analyzer.Summarize(len(uniqueStrings(allChangedFiles)), changedSymbols, result.AffectedResources, result.TotalResources)
+	result.Timings = analyzer.MergeTimings(timings...)
+	return result, nil
+}
+
+// uniqueStrings removes duplicates, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// uniqueInfraChanges removes changes of the same file
+func uniqueInfraChanges(changes []analyzer.IaCChange) []analyzer.IaCChange {
+	seen := make(map[string]bool)
+	var result []analyzer.IaCChange
+	for _, c := range changes {
+		if !seen[c.File] {
+			seen[c.File] = true
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// uniqueWarnings removes warnings with the same message (e.g., those of Analyze, reported for each base)
+func uniqueWarnings(warnings []*analyzer.AnalysisError) []*analyzer.AnalysisError {
+	seen := make(map[string]bool)
+	var result []*analyzer.AnalysisError
+	for _, w := range warnings {
+		if !seen[w.Error()] {
+			seen[w.Error()] = true
+			result = append(result, w)
+		}
+	}
+	return result
+}
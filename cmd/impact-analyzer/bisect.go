@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// bisectResult is the first commit of a range affecting a resource
+type bisectResult struct {
+	Resource string `json:"resource"`
+	Range    string `json:"range"`
+	// Commits is the number of commits analyzed until the first affecting one
+	Commits int `json:"commits"`
+	// Commit is nil if no commit of the range affects the resource
+	Commit *rangeCommit               `json:"commit"`
+	Impact *analyzer.AffectedResource `json:"impact,omitempty"`
+}
+
+// bisectResource analyzes each first-parent commit between the base branch and HEAD against its
+// parent, oldest first, and returns the first one affecting the resource
+// Results are read from and kept in the result cache, if any
+func bisectResource(a *analyzer.Analyzer, opts *commonOptions, fileCfg *FileConfig, cache *resultCache, name string) (*bisectResult, error) {
+	known := false
+	for _, r := range a.GetResources() {
+		known = known || r.Name == name
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown resource %q in -bisect", name)
+	}
+
+	result := &bisectResult{Resource: name, Range: opts.baseBranch + "..HEAD"}
+	commits, err := rangeCommits(opts.projectRoot, opts.baseBranch, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	history := newCommitHistory(*opts, fileCfg, cache, commitSettings(os.Args[1:]))
+	defer history.close()
+	for i, c := range commits {
+		fmt.Fprintf(os.Stderr, "Analyzing commit %d/%d %s\n", i+1, len(commits), shortHash(c.Hash))
+		affected, err := history.analyze(&c)
+		if err != nil {
+			return nil, err
+		}
+		result.Commits = i + 1
+		for _, r := range affected {
+			if r.Name == name {
+				result.Commit = &c
+				result.Impact = &r
+				return result, nil
+			}
+		}
+	}
+	return result, nil
+}
+
+// WriteText outputs the bisect result in text format
+func (result *bisectResult) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "=== Bisect %s (%s) ===\n", result.Resource, result.Range)
+	fmt.Fprintln(&b)
+
+	if result.Commit == nil {
+		fmt.Fprintf(&b, "No commit affects %s (%d commits analyzed)\n", result.Resource, result.Commits)
+	} else {
+		fmt.Fprintf(&b, "First commit affecting %s (%d of the range):\n", result.Resource, result.Commits)
+		fmt.Fprintf(&b, "  %s\n", strings.TrimPrefix(releaseNoteLine(*result.Commit), "- "))
+		fmt.Fprintf(&b, "    Reason: %s\n", result.Impact.Reason)
+		if len(result.Impact.DependencyChain) > 0 {
+			fmt.Fprintf(&b, "    Chain: %s\n", strings.Join(result.Impact.DependencyChain, " -> "))
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// commitSettings returns the arguments the results of the commits depend on: those of the run without -bisect
+// and the output flags, so that bisections of different resources and outputs share the results of their commits
+func commitSettings(args []string) string {
+	var settings []string
+	for i := 0; i < len(args); i++ {
+		name, _, hasValue := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		if (name == "bisect" || name == "format" || name == "o" || name == "output") && strings.HasPrefix(args[i], "-") {
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		settings = append(settings, args[i])
+	}
+	return strings.Join(settings, "\x00")
+}
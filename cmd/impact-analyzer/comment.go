@@ -0,0 +1,221 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// commentMarker identifies the pull request comment of the analyzer among the comments of other bots
+const commentMarker = "<!-- go-impact-analyzer"
+
+// issueComment is the part of a GitHub issue comment used by the bot mode
+type issueComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	URL  string `json:"html_url"`
+}
+
+// prCommenter posts the result as a comment of the pull request of the workflow run
+type prCommenter struct {
+	client *http.Client
+	apiURL string
+	repo   string
+	number int
+	token  string
+}
+
+// newPRCommenter creates a commenter from the GitHub Actions environment
+// Returns nil if the workflow was not triggered by a pull request
+func newPRCommenter(token string) (*prCommenter, error) {
+	if token == "" {
+		return nil, fmt.Errorf("the comment input needs a github_token")
+	}
+	eventPath := os.Getenv("GITHUB_EVENT_PATH")
+	if eventPath == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(eventPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the workflow event: %w", err)
+	}
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return nil, fmt.Errorf("failed to parse the workflow event: %w", err)
+	}
+	if event.PullRequest.Number == 0 {
+		return nil, nil
+	}
+
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = "https://api.github.com"
+	}
+	return &prCommenter{
+		client: &http.Client{Timeout: time.Minute},
+		apiURL: strings.TrimSuffix(apiURL, "/"),
+		repo:   os.Getenv("GITHUB_REPOSITORY"),
+		number: event.PullRequest.Number,
+		token:  token,
+	}, nil
+}
+
+// commentOnPullRequest keeps the result in a comment of the pull request of the workflow run
+// Runs not triggered by a pull request are skipped
+func commentOnPullRequest(result *output.AnalysisResult, opts output.Options) error {
+	c, err := newPRCommenter(actionInput("github_token", os.Getenv("GITHUB_TOKEN")))
+	if err != nil {
+		return err
+	}
+	if c == nil {
+		fmt.Fprintln(os.Stderr, "Skipping the pull request comment: the workflow was not triggered by a pull request")
+		return nil
+	}
+	body, hash, err := commentBody(result, opts)
+	if err != nil {
+		return err
+	}
+	status, err := c.upsert(body, hash)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Pull request comment %s\n", status)
+	return nil
+}
+
+// commentBody renders the result as the Markdown comment body, ending with the marker and the hash
+// of the result so that an unchanged analysis can be recognized
+func commentBody(result *output.AnalysisResult, opts output.Options) (string, string, error) {
+	writer, err := output.New("markdown", opts)
+	if err != nil {
+		return "", "", err
+	}
+	var b bytes.Buffer
+	if err := writer.WriteAnalysisResult(&b, result); err != nil {
+		return "", "", err
+	}
+	hash, err := resultHash(result)
+	if err != nil {
+		return "", "", err
+	}
+	fmt.Fprintf(&b, "\n%s hash=%s -->\n", commentMarker, hash)
+	return b.String(), hash, nil
+}
+
+// resultHash returns the hash of the canonical JSON of the result without the fields that change from run
+// to run on the same changes: the next runs of scheduled jobs, the timings and the build information
+func resultHash(result *output.AnalysisResult) (string, error) {
+	r := *result
+	r.BuildInfo, r.Timings = nil, nil
+	r.AffectedResources = withoutNextRun(r.AffectedResources)
+	r.Bases = make([]output.BaseImpact, len(result.Bases))
+	for i, b := range result.Bases {
+		b.AffectedResources = withoutNextRun(b.AffectedResources)
+		r.Bases[i] = b
+	}
+	r.Patches = make([]output.PatchImpact, len(result.Patches))
+	for i, p := range result.Patches {
+		p.AffectedResources = withoutNextRun(p.AffectedResources)
+		r.Patches[i] = p
+	}
+	data, err := json.Marshal(&r)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8]), nil
+}
+
+// withoutNextRun returns a copy of the affected resources with the next run of scheduled jobs cleared
+func withoutNextRun(resources []analyzer.AffectedResource) []analyzer.AffectedResource {
+	result := make([]analyzer.AffectedResource, len(resources))
+	for i, ar := range resources {
+		ar.NextRun = ""
+		result[i] = ar
+	}
+	return result
+}
+
+// upsert updates the comment of the analyzer on the pull request, or posts it if there is none
+// The comment is left alone when the hash of the result did not change, so reviewers are not re-notified
+func (c *prCommenter) upsert(body, hash string) (string, error) {
+	existing, err := c.findComment()
+	if err != nil {
+		return "", err
+	}
+	if existing == nil {
+		var created issueComment
+		err := c.do(http.MethodPost, fmt.Sprintf("/repos/%s/issues/%d/comments", c.repo, c.number), body, &created)
+		return "posted " + created.URL, err
+	}
+	if strings.Contains(existing.Body, commentMarker+" hash="+hash+" ") {
+		return "unchanged " + existing.URL, nil
+	}
+	var updated issueComment
+	err = c.do(http.MethodPatch, fmt.Sprintf("/repos/%s/issues/comments/%d", c.repo, existing.ID), body, &updated)
+	return "updated " + updated.URL, err
+}
+
+// findComment returns the first comment of the pull request holding the marker, or nil
+func (c *prCommenter) findComment() (*issueComment, error) {
+	for page := 1; ; page++ {
+		var comments []issueComment
+		path := fmt.Sprintf("/repos/%s/issues/%d/comments?per_page=100&page=%d", c.repo, c.number, page)
+		if err := c.do(http.MethodGet, path, "", &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, commentMarker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < 100 {
+			return nil, nil
+		}
+	}
+}
+
+// do sends a request to the GitHub API, with the body as {"body": ...} if set, and decodes the response
+func (c *prCommenter) do(method, path, body string, v any) error {
+	var reqBody io.Reader
+	if body != "" {
+		data, err := json.Marshal(map[string]string{"body": body})
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, c.apiURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// commonOptions holds the flags shared by the default command and subcommands
+type commonOptions struct {
+	jsonOutput  bool
+	baseBranch  string
+	projectRoot string
+	modulePath  string
+	cmdDir      string
+	pathPrefix  string
+	configPath  string
+	strict      bool
+	reflection  string
+	mode        string
+	graphCache  string
+	rulePlugins string
+	noColor     bool
+	lang        string
+	concurrency int
+	// externalAnalyzers are executables analyzing non-Go code (-external-analyzers)
+	externalAnalyzers string
+	// diffExclude are comma-separated glob patterns of paths left out of the git diff (-diff-exclude);
+	// loadConfig sets them from the configuration file when the flag is not given
+	diffExclude string
+	// resources limits the impact check to the named resources (-resources, default command only)
+	resources string
+	// messages are the text output templates, set by loadConfig
+	messages output.Messages
+	// severityGroups groups the text output by severity, set by loadConfig when severities are configured
+	severityGroups bool
+	// format, outputs and outputPath select the outputs (-format, -output, -o), defined by registerOutputs
+	format     string
+	outputs    string
+	outputPath string
+}
+
+// register defines the common flags on the given FlagSet
+func (o *commonOptions) register(fs *flag.FlagSet) {
+	fs.BoolVar(&o.jsonOutput, "json", false, "Output in JSON format")
+	fs.StringVar(&o.baseBranch, "base", "main", "Base branch for git diff comparison")
+	fs.StringVar(&o.projectRoot, "root", "", "Project root directory (default: auto-detect)")
+	fs.StringVar(&o.modulePath, "module", "", "Go module path (default: auto-detect from go.mod)")
+	fs.StringVar(&o.cmdDir, "cmd-dir", "cli/cmd", "Directory containing CLI command definitions")
+	fs.StringVar(&o.pathPrefix, "path-prefix", "", "Path prefix to strip from file paths (e.g., 'go/' for monorepo)")
+	fs.StringVar(&o.configPath, "config", "", "Path to a JSON configuration file")
+	fs.BoolVar(&o.strict, "strict", false, "Fail on analysis degradation instead of falling back to best-effort results")
+	fs.StringVar(&o.lang, "lang", "", "Language of the text output (en, ja, or auto to detect it from LANG)")
+	fs.BoolVar(&o.noColor, "no-color", false, "Disable colors in text output (default: enabled when stdout is a terminal and NO_COLOR is unset)")
+	fs.StringVar(&o.rulePlugins, "rule-plugins", "", "Comma-separated rule plugins (Go plugin .so files or executables) adding or removing affected resources")
+	fs.StringVar(&o.externalAnalyzers, "external-analyzers", "", "Comma-separated executables reporting affected resources of non-Go code, merged into the result")
+	fs.IntVar(&o.concurrency, "concurrency", runtime.GOMAXPROCS(0), "Maximum number of changed packages analyzed in parallel")
+	fs.StringVar(&o.reflection, "reflection", "", "Reflection heuristic: conservative widens impact to symbols reached via reflection, off disables it (default: off, conservative with -mode thorough)")
+	fs.StringVar(&o.graphCache, "graph-cache", "", "Directory keeping an index of the package listing and parsed symbols between runs; only directories changed since the indexed commit are listed again")
+	fs.StringVar(&o.diffExclude, "diff-exclude", "", "Comma-separated glob patterns of paths git leaves out of the changed files (e.g., 'vendor,gen/**/*.pb.go')")
+	fs.StringVar(&o.mode, "mode", "", "Analysis preset trading precision for speed: fast, balanced or thorough (default: balanced)")
+}
+
+// registerOutputs defines the output flags on the given FlagSet
+func (o *commonOptions) registerOutputs(fs *flag.FlagSet) {
+	fs.StringVar(&o.format, "format", "", "Output format ("+strings.Join(output.Formats(), ", ")+"; default: text, or json with -json)")
+	fs.StringVar(&o.outputs, "output", "", "Comma-separated outputs as format=path, e.g. json=result.json,text=- (\"-\" is stdout; overrides -format)")
+	fs.StringVar(&o.outputPath, "o", "", "Write the output to a file instead of stdout (written atomically; parent directories are created)")
+}
+
+// outputFormat returns the format of -format, defaulting to text, or json with -json
+func (o *commonOptions) outputFormat() string {
+	if o.format != "" {
+		return o.format
+	}
+	if o.jsonOutput {
+		return "json"
+	}
+	return "text"
+}
+
+// outputTargets returns the outputs of -output, or the output of -format written to -o or stdout
+func (o *commonOptions) outputTargets() ([]outputTarget, error) {
+	spec := o.outputs
+	if o.outputPath != "" {
+		if spec != "" {
+			return nil, fmt.Errorf("-o cannot be combined with -output")
+		}
+		spec = o.outputFormat() + "=" + o.outputPath
+	}
+	if spec == "" {
+		spec = o.outputFormat() + "=-"
+	}
+	return parseOutputs(spec, o.outputOptions())
+}
+
+// reportTargets returns the outputs of outputTargets, checking that they can write reports
+func (o *commonOptions) reportTargets() ([]outputTarget, error) {
+	targets, err := o.outputTargets()
+	if err != nil {
+		return nil, err
+	}
+	return targets, checkReportTargets(targets, false)
+}
+
+// resourceList returns the resource names given with -resources
+func (o *commonOptions) resourceList() []string {
+	var names []string
+	for _, name := range strings.Split(o.resources, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// diffExcludes returns the patterns given with -diff-exclude or the diff_exclude configuration
+func (o *commonOptions) diffExcludes() []string {
+	var patterns []string
+	for _, pattern := range strings.Split(o.diffExclude, ",") {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
+}
+
+// outputOptions returns the options of the output writers
+func (o *commonOptions) outputOptions() output.Options {
+	return output.Options{NoColor: o.noColor, Messages: o.messages, SeverityGroups: o.severityGroups}
+}
+
+// loadConfig loads the configuration file if specified, and the text output messages
+func (o *commonOptions) loadConfig() *FileConfig {
+	fileCfg := &FileConfig{}
+	if o.configPath != "" {
+		var err error
+		fileCfg, err = loadConfig(o.configPath)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if o.diffExclude == "" {
+		o.diffExclude = strings.Join(fileCfg.DiffExclude, ",")
+	}
+
+	msgs, err := output.NewMessages(o.lang, fileCfg.Messages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	o.messages = msgs
+	o.severityGroups = len(fileCfg.Severities) > 0 || fileCfg.BreakingSeverity != ""
+	return fileCfg
+}
+
+// analyze detects the project settings, then creates and runs the Analyzer
+// It exits on errors; use build to handle them
+func (o *commonOptions) analyze(fileCfg *FileConfig) *analyzer.Analyzer {
+	a, err := o.build(fileCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if o.modulePath == "" {
+			fmt.Fprintf(os.Stderr, "Please specify -module flag\n")
+		}
+		os.Exit(1)
+	}
+	return a
+}
+
+// build detects the project settings, then creates and runs the Analyzer
+func (o *commonOptions) build(fileCfg *FileConfig) (*analyzer.Analyzer, error) {
+	a, err := o.newAnalyzer(fileCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run analysis
+	fmt.Fprintf(os.Stderr, "Analyzing project at %s...\n", o.projectRoot)
+	if err := a.Analyze(); err != nil {
+		return nil, fmt.Errorf("failed to analyze: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Found %d resources\n", len(a.GetResources()))
+
+	return a, nil
+}
+
+// newAnalyzer detects the project settings and creates the Analyzer without running it
+func (o *commonOptions) newAnalyzer(fileCfg *FileConfig) (*analyzer.Analyzer, error) {
+	// Detect project root
+	if o.projectRoot == "" {
+		var err error
+		o.projectRoot, err = detectProjectRoot()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect project root: %w", err)
+		}
+	}
+
+	// Detect module path from go.mod if not specified
+	if o.modulePath == "" {
+		modulePath, err := detectModulePath(o.projectRoot)
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect module path: %w", err)
+		}
+		o.modulePath = modulePath
+	}
+
+	reflection := analyzer.ReflectionMode(o.reflection)
+	if reflection != "" && !reflection.IsValid() {
+		return nil, fmt.Errorf("invalid -reflection %q (want conservative or off)", o.reflection)
+	}
+
+	graphCache := o.graphCache
+	if graphCache == "" {
+		graphCache = fileCfg.GraphCache
+	}
+
+	mode := analyzer.AnalysisMode(o.mode)
+	if mode == "" {
+		mode = fileCfg.Mode
+	}
+	if mode != "" && !mode.IsValid() {
+		return nil, fmt.Errorf("invalid -mode %q (want fast, balanced or thorough)", mode)
+	}
+
+	if o.concurrency < 1 {
+		return nil, fmt.Errorf("invalid -concurrency %d (want 1 or more)", o.concurrency)
+	}
+
+	var rulePlugins []analyzer.RulePlugin
+	if o.rulePlugins != "" {
+		for _, path := range strings.Split(o.rulePlugins, ",") {
+			p, err := analyzer.LoadRulePlugin(strings.TrimSpace(path))
+			if err != nil {
+				return nil, err
+			}
+			rulePlugins = append(rulePlugins, p)
+		}
+	}
+
+	var externalAnalyzers []analyzer.ExternalAnalyzer
+	if o.externalAnalyzers != "" {
+		for _, path := range strings.Split(o.externalAnalyzers, ",") {
+			externalAnalyzers = append(externalAnalyzers, analyzer.NewExecExternalAnalyzer(strings.TrimSpace(path)))
+		}
+	}
+
+	var extractorOptions []analyzer.ExtractorOption
+	if len(fileCfg.ResourceFiles) > 0 {
+		extractorOptions = append(extractorOptions, analyzer.WithResourceFilePatterns(fileCfg.ResourceFiles))
+	}
+	if fileCfg.CommandPaths {
+		extractorOptions = append(extractorOptions, analyzer.WithCommandPaths(true))
+	}
+
+	// Create Analyzer
+	cfg := analyzer.Config{
+		ModulePath:  o.modulePath,
+		ProjectRoot: o.projectRoot,
+		CmdDir:      o.cmdDir,
+		PathPrefix:  o.pathPrefix,
+		BaseBranch:  o.baseBranch,
+
+		ExtractorOptions: extractorOptions,
+
+		Strict:      o.strict,
+		Reflection:  reflection,
+		Mode:        mode,
+		RulePlugins: rulePlugins,
+		Resources:   o.resourceList(),
+		Concurrency: o.concurrency,
+
+		GraphCacheDir: graphCache,
+
+		ExternalAnalyzers: externalAnalyzers,
+
+		SeverityRules:    fileCfg.Severities,
+		BreakingSeverity: fileCfg.BreakingSeverity,
+		FileMappings:     fileCfg.FileMappings,
+		ForceInclude:     fileCfg.ForceInclude,
+		IaCPatterns:      fileCfg.IaCPatterns,
+
+		InfrastructureFiles:          fileCfg.InfrastructureFiles,
+		InfrastructureDirs:           fileCfg.InfrastructureDirs,
+		InfrastructurePatterns:       fileCfg.InfrastructurePatterns,
+		InfrastructureGeneratedFiles: fileCfg.InfrastructureGenerated,
+		SqlcConfig:                   fileCfg.SqlcConfig,
+		RegistrationTables:           fileCfg.RegistrationTables,
+		OpenAPISpecs:                 fileCfg.OpenAPI,
+		Services:                     fileCfg.Services,
+		Topics:                       fileCfg.Topics,
+		FeatureFlags:                 fileCfg.FeatureFlags,
+		Aliases:                      fileCfg.Aliases,
+		Images:                       fileCfg.Images,
+		ImageTemplate:                fileCfg.ImageTemplate,
+		Applications:                 fileCfg.Applications,
+		TestPackages:                 fileCfg.TestPackages,
+		IgnoreSymbols:                fileCfg.IgnoreSymbols,
+		LabelRules:                   fileCfg.Labels,
+		ImportRules:                  fileCfg.ImportRules,
+		CollapsePrefixes:             fileCfg.Collapse,
+		FallbackPolicy:               fileCfg.FallbackPolicy,
+		WireFormatOnly:               fileCfg.WireFormatOnly,
+		ConfidenceDecayHops:          fileCfg.ConfidenceDecayHops,
+	}
+	return analyzer.NewAnalyzerWithOptions(cfg)
+}
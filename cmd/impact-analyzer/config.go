@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// FileConfig represents the JSON configuration file passed via -config
+type FileConfig struct {
+	// Severities assign severity tiers to resources by name or glob pattern (first match wins)
+	Severities []analyzer.SeverityRule `json:"severities"`
+	// FailOn is the minimum severity of an affected resource that causes a non-zero exit code
+	FailOn analyzer.Severity `json:"fail_on"`
+	// FailOnIncompatible causes a non-zero exit code on incompatible public-API changes
+	FailOnIncompatible bool `json:"fail_on_incompatible"`
+	// BreakingSeverity is the minimum severity of resources using incompatibly changed symbols
+	BreakingSeverity analyzer.Severity `json:"breaking_severity"`
+	// ResourceFiles map command files matching glob patterns to resource types, besides api.go, job.go and worker.go
+	ResourceFiles []analyzer.ResourceFilePattern `json:"resource_files"`
+	// CommandPaths names subcommands by their command path (e.g., "job update-price") instead of their own name
+	CommandPaths bool `json:"command_paths"`
+	// RegistrationTables describe resources registered as elements of slice or map literals
+	RegistrationTables []analyzer.RegistrationTable `json:"registration_tables"`
+	// FileMappings map non-Go files (e.g., runtime config files) to resource names
+	FileMappings []analyzer.FileMapping `json:"file_mappings"`
+	// ForceInclude mark resources as affected whenever files matching their paths change
+	ForceInclude []analyzer.ForceIncludeRule `json:"force_include"`
+	// IaCPatterns are glob patterns identifying infrastructure-as-code files
+	IaCPatterns []string `json:"iac_patterns"`
+	// InfrastructureFiles, InfrastructureDirs and InfrastructurePatterns select files that only affect
+	// resources using the exact symbols they define
+	InfrastructureFiles    []string `json:"infrastructure_files"`
+	InfrastructureDirs     []string `json:"infrastructure_dirs"`
+	InfrastructurePatterns []string `json:"infrastructure_patterns"`
+	// InfrastructureGenerated treats files with a "// Code generated ... DO NOT EDIT." header as infrastructure files
+	InfrastructureGenerated bool `json:"infrastructure_generated"`
+	// SqlcConfig is the path of the sqlc configuration relative to the project root (default: auto-detect)
+	SqlcConfig string `json:"sqlc_config"`
+	// OpenAPI link OpenAPI specs to the packages generated from them
+	OpenAPI []analyzer.OpenAPISpec `json:"openapi"`
+	// Services declare the gRPC methods each resource serves and consumes
+	Services []analyzer.ServiceDefinition `json:"services"`
+	// Topics declare the publishers and consumers of message-queue topics
+	Topics []analyzer.TopicDefinition `json:"topics"`
+	// FeatureFlags declare feature flags read through Go string constants and the files defining them
+	FeatureFlags []analyzer.FeatureFlagDefinition `json:"feature_flags"`
+	// Aliases map extracted command names to canonical resource names
+	Aliases map[string]string `json:"aliases"`
+	// Images map resource names to container images
+	Images map[string]string `json:"images"`
+	// Applications map resource names to the CD applications deploying them (e.g., Argo CD Applications)
+	Applications map[string][]string `json:"applications"`
+	// IgnoreSymbols are symbols and packages whose changes never affect resources
+	IgnoreSymbols []string `json:"ignore_symbols"`
+	// TestPackages map resource names to the packages of their tests
+	TestPackages map[string][]string `json:"test_packages"`
+	// ImageTemplate derives images of unmapped resources ({name} and {type} are replaced)
+	ImageTemplate string `json:"image_template"`
+	// Labels attach arbitrary metadata to resources by name or glob pattern
+	Labels []analyzer.LabelRule `json:"labels"`
+	// ImportRules are architecture rules restricting imports between packages
+	ImportRules []analyzer.ImportRule `json:"import_rules"`
+	// Collapse are package prefixes shown as one node in dependency chains and graph exports
+	Collapse []string `json:"collapse"`
+	// DiffExclude are glob patterns of paths left out of the git diff (e.g., ["vendor", "gen/**"]); -diff-exclude takes precedence
+	DiffExclude []string `json:"diff_exclude"`
+	// GraphCache is the directory keeping the index of the package listing and parsed symbols between runs; -graph-cache takes precedence
+	GraphCache string `json:"graph_cache"`
+	// ResultCache is the directory keeping the results of analyses by base and head commits; -result-cache takes precedence
+	ResultCache string `json:"result_cache"`
+	// ResultCacheTTL is how long cached results are reused (e.g., 24h); -result-cache-ttl takes precedence
+	ResultCacheTTL string `json:"result_cache_ttl"`
+	// Mode is the analysis preset (fast, balanced, thorough); -mode takes precedence
+	Mode analyzer.AnalysisMode `json:"mode"`
+	// FallbackPolicy handles files without line-level diff information (all-exported, whole-package, none)
+	FallbackPolicy analyzer.FallbackPolicy `json:"fallback_policy"`
+	// ConfidenceDecayHops lowers the confidence one level for every that many unverified links of a dependency chain
+	ConfidenceDecayHops int `json:"confidence_decay_hops"`
+	// WireFormatOnly restricts struct tag changes to resources serializing values
+	WireFormatOnly bool `json:"wire_format_only"`
+	// Messages override the templates of the text output by message ID
+	Messages map[string]string `json:"messages"`
+}
+
+// loadConfig reads and validates the configuration file
+func loadConfig(configPath string) (*FileConfig, error) {
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	var cfg FileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config: %w", err)
+	}
+
+	for _, rule := range cfg.Severities {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("severity rule has an empty pattern")
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid severity pattern %q: %w", rule.Pattern, err)
+		}
+		if !rule.Severity.IsValid() {
+			return nil, fmt.Errorf("invalid severity %q for pattern %q", rule.Severity, rule.Pattern)
+		}
+	}
+	for _, p := range cfg.ResourceFiles {
+		if _, err := filepath.Match(p.Pattern, ""); err != nil || p.Pattern == "" {
+			return nil, fmt.Errorf("invalid resource file pattern %q", p.Pattern)
+		}
+		if !p.Type.IsValid() {
+			return nil, fmt.Errorf("invalid resource type %q for resource file pattern %q", p.Type, p.Pattern)
+		}
+	}
+	for _, mapping := range cfg.FileMappings {
+		if mapping.Pattern == "" {
+			return nil, fmt.Errorf("file mapping has an empty pattern")
+		}
+		if len(mapping.Resources) == 0 {
+			return nil, fmt.Errorf("file mapping %q has no resources", mapping.Pattern)
+		}
+	}
+	for _, rule := range cfg.ForceInclude {
+		if len(rule.Paths) == 0 {
+			return nil, fmt.Errorf("force-include rule needs paths")
+		}
+		for _, t := range rule.Types {
+			if !t.IsValid() {
+				return nil, fmt.Errorf("invalid resource type %q in force-include rule", t)
+			}
+		}
+	}
+	for _, spec := range cfg.OpenAPI {
+		if spec.Spec == "" || spec.Package == "" {
+			return nil, fmt.Errorf("openapi entry needs a spec and a package")
+		}
+	}
+	for _, svc := range cfg.Services {
+		if svc.Resource == "" {
+			return nil, fmt.Errorf("service definition has an empty resource")
+		}
+	}
+	for _, topic := range cfg.Topics {
+		if topic.Topic == "" && topic.Constant == "" {
+			return nil, fmt.Errorf("topic definition needs a topic or a constant")
+		}
+	}
+	for _, flag := range cfg.FeatureFlags {
+		if flag.Constant == "" || len(flag.Files) == 0 {
+			return nil, fmt.Errorf("feature flag definition needs a constant and files")
+		}
+	}
+	for _, rule := range cfg.Labels {
+		if rule.Pattern == "" {
+			return nil, fmt.Errorf("label rule has an empty pattern")
+		}
+	}
+	for _, rule := range cfg.ImportRules {
+		if rule.From == "" || len(rule.Deny) == 0 {
+			return nil, fmt.Errorf("import rule needs a from pattern and deny patterns")
+		}
+	}
+	if cfg.Mode != "" && !cfg.Mode.IsValid() {
+		return nil, fmt.Errorf("invalid mode %q (want fast, balanced or thorough)", cfg.Mode)
+	}
+	if cfg.FallbackPolicy != "" && !cfg.FallbackPolicy.IsValid() {
+		return nil, fmt.Errorf("invalid fallback_policy %q", cfg.FallbackPolicy)
+	}
+	if cfg.ConfidenceDecayHops < 0 {
+		return nil, fmt.Errorf("invalid confidence_decay_hops %d (want 0 or more)", cfg.ConfidenceDecayHops)
+	}
+	if cfg.FailOn != "" && !cfg.FailOn.IsValid() {
+		return nil, fmt.Errorf("invalid fail_on severity %q", cfg.FailOn)
+	}
+	if cfg.BreakingSeverity != "" && !cfg.BreakingSeverity.IsValid() {
+		return nil, fmt.Errorf("invalid breaking_severity %q", cfg.BreakingSeverity)
+	}
+
+	return &cfg, nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// configDiffResult is the delta between the impact analyses of the same changes with two configurations
+type configDiffResult struct {
+	Config       string   `json:"config"`
+	Against      string   `json:"against"`
+	ChangedFiles []string `json:"changed_files"`
+	// OnlyConfig and OnlyAgainst are the resources affected with only one of the configurations
+	OnlyConfig  []analyzer.AffectedResource `json:"only_config"`
+	OnlyAgainst []analyzer.AffectedResource `json:"only_against"`
+	// Changed are the resources affected with both configurations, for a different reason or severity
+	Changed []configDiffChange `json:"changed"`
+}
+
+// configDiffChange is a resource affected with both configurations that is reported differently
+type configDiffChange struct {
+	Name    string                    `json:"name"`
+	Config  analyzer.AffectedResource `json:"config"`
+	Against analyzer.AffectedResource `json:"against"`
+}
+
+// runConfigDiff runs the config-diff subcommand, a debugging aid for tuning the configuration file
+// It analyzes the same changes with -config and with -against, and reports the resources whose impact differs
+func runConfigDiff(args []string) {
+	var (
+		opts    commonOptions
+		against string
+		files   string
+	)
+
+	fs := flag.NewFlagSet("config-diff", flag.ExitOnError)
+	opts.register(fs)
+	opts.registerOutputs(fs)
+	fs.StringVar(&against, "against", "", "Configuration file to compare -config with (default: no configuration file)")
+	fs.StringVar(&files, "files", "", "Comma-separated list of changed files (default: git diff against -base)")
+	fs.Parse(args)
+
+	if opts.configPath == "" && against == "" {
+		fmt.Fprintln(os.Stderr, "Error: -config or -against is required")
+		fmt.Fprintln(os.Stderr, "Usage: impact-analyzer config-diff -config=tuned.json [-against=current.json] [-files=a.go,b.go]")
+		os.Exit(1)
+	}
+
+	fileCfg := opts.loadConfig()
+	targets, err := opts.reportTargets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	againstCfg := &FileConfig{}
+	if against != "" {
+		againstCfg, err = loadConfig(against)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	a := opts.analyze(fileCfg)
+	againstOpts := opts
+	b, err := againstOpts.build(againstCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: -against: %v\n", err)
+		os.Exit(1)
+	}
+
+	var changedFiles []string
+	if files != "" {
+		for _, f := range strings.Split(files, ",") {
+			changedFiles = append(changedFiles, strings.TrimSpace(f))
+		}
+	} else {
+		changedFiles, _, err = gitChangedFiles(&opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	result := diffImpact(a.GetAffectedResources(changedFiles), b.GetAffectedResources(changedFiles))
+	result.Config = configName(opts.configPath)
+	result.Against = configName(against)
+	result.ChangedFiles = changedFiles
+	if result.ChangedFiles == nil {
+		result.ChangedFiles = []string{}
+	}
+
+	printReport(result, targets, false)
+}
+
+// configName describes a configuration file path for the report
+func configName(path string) string {
+	if path == "" {
+		return "(none)"
+	}
+	return path
+}
+
+// diffImpact compares the affected resources of the two configurations by resource name
+func diffImpact(config, against []analyzer.AffectedResource) *configDiffResult {
+	result := &configDiffResult{
+		OnlyConfig:  []analyzer.AffectedResource{},
+		OnlyAgainst: []analyzer.AffectedResource{},
+		Changed:     []configDiffChange{},
+	}
+
+	againstByName := make(map[string]analyzer.AffectedResource)
+	for _, r := range against {
+		againstByName[r.Name] = r
+	}
+	seen := make(map[string]bool)
+	for _, r := range config {
+		seen[r.Name] = true
+		other, ok := againstByName[r.Name]
+		if !ok {
+			result.OnlyConfig = append(result.OnlyConfig, r)
+			continue
+		}
+		if r.Reason != other.Reason || r.Severity != other.Severity {
+			result.Changed = append(result.Changed, configDiffChange{Name: r.Name, Config: r, Against: other})
+		}
+	}
+	for _, r := range against {
+		if !seen[r.Name] {
+			result.OnlyAgainst = append(result.OnlyAgainst, r)
+		}
+	}
+
+	sort.Slice(result.Changed, func(i, j int) bool {
+		return result.Changed[i].Name < result.Changed[j].Name
+	})
+	return result
+}
+
+// WriteText outputs the configuration impact diff in text format
+func (result *configDiffResult) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "=== Impact Diff: %s vs %s ===\n", result.Config, result.Against)
+	fmt.Fprintln(&b)
+
+	if len(result.OnlyConfig) == 0 && len(result.OnlyAgainst) == 0 && len(result.Changed) == 0 {
+		fmt.Fprintf(&b, "Same impact for %d changed files\n", len(result.ChangedFiles))
+	}
+
+	if len(result.OnlyConfig) > 0 {
+		fmt.Fprintf(&b, "Only with %s (%d):\n", result.Config, len(result.OnlyConfig))
+		for _, r := range result.OnlyConfig {
+			fmt.Fprintf(&b, "  + %s: %s\n", r.Name, r.Reason)
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(result.OnlyAgainst) > 0 {
+		fmt.Fprintf(&b, "Only with %s (%d):\n", result.Against, len(result.OnlyAgainst))
+		for _, r := range result.OnlyAgainst {
+			fmt.Fprintf(&b, "  - %s: %s\n", r.Name, r.Reason)
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(result.Changed) > 0 {
+		fmt.Fprintf(&b, "Reported differently (%d):\n", len(result.Changed))
+		for _, c := range result.Changed {
+			fmt.Fprintf(&b, "  ~ %s\n", c.Name)
+			fmt.Fprintf(&b, "      %s: [%s] %s\n", result.Config, c.Config.Severity, c.Config.Reason)
+			fmt.Fprintf(&b, "      %s: [%s] %s\n", result.Against, c.Against.Severity, c.Against.Reason)
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// deprecationReport is the report of deprecated symbol usages
+type deprecationReport struct {
+	DeprecatedSymbols []analyzer.DeprecatedSymbolReport `json:"deprecated_symbols"`
+	Total             int                               `json:"total"`
+}
+
+// newDeprecationReport creates the report of deprecated symbol usages
+func newDeprecationReport(reports []analyzer.DeprecatedSymbolReport) deprecationReport {
+	if reports == nil {
+		reports = []analyzer.DeprecatedSymbolReport{}
+	}
+	return deprecationReport{DeprecatedSymbols: reports, Total: len(reports)}
+}
+
+// WriteText outputs deprecated symbol usages in text format
+func (r deprecationReport) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "=== Deprecated Symbol Usage ===")
+	fmt.Fprintln(&b)
+
+	if len(r.DeprecatedSymbols) == 0 {
+		fmt.Fprintln(&b, "No deprecated symbols found")
+	}
+
+	for _, s := range r.DeprecatedSymbols {
+		fmt.Fprintf(&b, "%s (%d usages)\n", s.Symbol, len(s.Usages))
+		if len(s.Resources) > 0 {
+			fmt.Fprintf(&b, "  Resources: %s\n", strings.Join(s.Resources, ", "))
+		}
+		for _, u := range s.Usages {
+			fmt.Fprintf(&b, "  - %s:%d\n", u.File, u.Line)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
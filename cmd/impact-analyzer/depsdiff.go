@@ -0,0 +1,82 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// runDepsDiff runs the deps-diff subcommand
+func runDepsDiff(args []string) {
+	var opts commonOptions
+
+	fs := flag.NewFlagSet("deps-diff", flag.ExitOnError)
+	opts.register(fs)
+	opts.registerOutputs(fs)
+	fs.Parse(args)
+
+	fileCfg := opts.loadConfig()
+	targets, err := opts.reportTargets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	head := opts.analyze(fileCfg)
+
+	// Analyze the base branch in a temporary worktree with the same settings
+	baseRoot, cleanup, err := analyzer.CheckoutBaseWorktree(opts.projectRoot, opts.baseBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	baseOpts := opts
+	baseOpts.projectRoot = baseRoot
+	base, err := baseOpts.build(fileCfg)
+	cleanup()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: base branch %s: %v\n", opts.baseBranch, err)
+		os.Exit(1)
+	}
+
+	diffs := analyzer.DiffResourceDependencies(base, head)
+
+	result := depsDiffResult{Base: opts.baseBranch, Diffs: diffs}
+	if result.Diffs == nil {
+		result.Diffs = []analyzer.ResourceDependencyDiff{}
+	}
+	printReport(result, targets, false)
+}
+
+// depsDiffResult is the change of the resource dependencies from the base branch
+type depsDiffResult struct {
+	Base  string                            `json:"base"`
+	Diffs []analyzer.ResourceDependencyDiff `json:"resources"`
+}
+
+// WriteText outputs the resource dependency diff in text format
+func (r depsDiffResult) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "=== Resource Dependency Changes (vs %s) ===\n", r.Base)
+	fmt.Fprintln(&b)
+
+	if len(r.Diffs) == 0 {
+		fmt.Fprintln(&b, "No resource dependencies changed")
+	}
+
+	for _, d := range r.Diffs {
+		fmt.Fprintf(&b, "%s:\n", d.Resource)
+		for _, pkg := range d.Added {
+			fmt.Fprintf(&b, "  + %s\n", pkg)
+		}
+		for _, pkg := range d.Removed {
+			fmt.Fprintf(&b, "  - %s\n", pkg)
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
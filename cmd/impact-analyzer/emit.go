@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// defaultEmitCommand builds the entry package of the resource
+const defaultEmitCommand = "go build {{.Package}}"
+
+// emitters maps the pipeline formats of -emit to their constructors, taking the step command template
+var emitters = map[string]func(command *template.Template) output.Writer{
+	"argo":      newArgoEmitter,
+	"buildkite": newBuildkiteEmitter,
+	"tekton":    newTektonEmitter,
+}
+
+// emitterNames returns the names of the pipeline formats of -emit
+func emitterNames() []string {
+	names := make([]string, 0, len(emitters))
+	for name := range emitters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newEmitter creates the writer of a pipeline format with a step command template (text/template
+// executed on each resource, e.g., "make deploy-{{.Name}}")
+func newEmitter(name, command string) (output.Writer, error) {
+	newWriter, ok := emitters[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown -emit pipeline %q (want %s)", name, strings.Join(emitterNames(), ", "))
+	}
+	tmpl, err := template.New("command").Option("missingkey=error").Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -emit-command: %w", err)
+	}
+	return newWriter(tmpl), nil
+}
+
+// buildkiteEmitter writes a Buildkite pipeline with one command step per resource, uploaded by a
+// dynamic pipeline step (buildkite-agent pipeline upload) so that only the affected resources are built
+type buildkiteEmitter struct {
+	command *template.Template
+}
+
+// newBuildkiteEmitter creates the Buildkite pipeline writer
+func newBuildkiteEmitter(command *template.Template) output.Writer {
+	return buildkiteEmitter{command: command}
+}
+
+// WriteAnalysisResult writes the pipeline of the affected resources
+func (e buildkiteEmitter) WriteAnalysisResult(w io.Writer, result *output.AnalysisResult) error {
+	resources := make([]any, len(result.AffectedResources))
+	for i, r := range result.AffectedResources {
+		resources[i] = r
+	}
+	return e.write(w, result.AffectedResources, resources)
+}
+
+// WriteResourceList writes the pipeline of all resources (-list)
+func (e buildkiteEmitter) WriteResourceList(w io.Writer, list *output.ResourceListResult) error {
+	affected := make([]analyzer.AffectedResource, len(list.Resources))
+	resources := make([]any, len(list.Resources))
+	for i, r := range list.Resources {
+		affected[i] = analyzer.AffectedResource{Resource: r}
+		resources[i] = r
+	}
+	return e.write(w, affected, resources)
+}
+
+// write writes the steps of the resources, with their commands executed on the template data
+func (e buildkiteEmitter) write(w io.Writer, resources []analyzer.AffectedResource, data []any) error {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "steps:")
+	// A pipeline without steps fails to upload
+	if len(resources) == 0 {
+		fmt.Fprintln(&b, `  - label: "No affected resources"`)
+		fmt.Fprintln(&b, `    command: "true"`)
+	}
+	for i, r := range resources {
+		var command strings.Builder
+		if err := e.command.Execute(&command, data[i]); err != nil {
+			return fmt.Errorf("failed to render the command of %s: %w", r.Name, err)
+		}
+		fmt.Fprintf(&b, "  - label: %s\n", yamlString(fmt.Sprintf(":go: %s", r.Name)))
+		fmt.Fprintf(&b, "    key: %s\n", yamlString(stepKey(r.Name)))
+		fmt.Fprintf(&b, "    command: %s\n", yamlString(command.String()))
+		fmt.Fprintln(&b, "    env:")
+		fmt.Fprintf(&b, "      IMPACT_RESOURCE: %s\n", yamlString(r.Name))
+		fmt.Fprintf(&b, "      IMPACT_RESOURCE_TYPE: %s\n", yamlString(string(r.Type)))
+		fmt.Fprintf(&b, "      IMPACT_RESOURCE_PACKAGE: %s\n", yamlString(r.Package))
+		if r.Severity == analyzer.SeverityCritical {
+			// Critical resources are scheduled before the others
+			fmt.Fprintln(&b, "    priority: 1")
+		}
+	}
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// pipelineResource is a resource as listed in the parameters of argo and tekton
+type pipelineResource struct {
+	Name     string                `json:"name"`
+	Type     analyzer.ResourceType `json:"type"`
+	Package  string                `json:"package"`
+	Severity analyzer.Severity     `json:"severity"`
+}
+
+// pipelineResources returns the parameters of resources
+func pipelineResources(resources []analyzer.Resource) []pipelineResource {
+	result := make([]pipelineResource, 0, len(resources))
+	for _, r := range resources {
+		result = append(result, pipelineResource{Name: r.Name, Type: r.Type, Package: r.Package, Severity: r.Severity})
+	}
+	return result
+}
+
+// affectedPipelineResources returns the parameters of the affected resources of a result
+func affectedPipelineResources(result *output.AnalysisResult) []pipelineResource {
+	resources := make([]analyzer.Resource, len(result.AffectedResources))
+	for i, r := range result.AffectedResources {
+		resources[i] = r.Resource
+	}
+	return pipelineResources(resources)
+}
+
+// argoEmitter writes an Argo Workflows parameter file (argo submit --parameter-file), with the resources
+// as a JSON list for withParam, so that a workflow fans out one task per resource
+type argoEmitter struct{}
+
+// newArgoEmitter creates the Argo parameter file writer; the step command template is not used
+func newArgoEmitter(*template.Template) output.Writer {
+	return argoEmitter{}
+}
+
+// WriteAnalysisResult writes the parameters of the affected resources
+func (argoEmitter) WriteAnalysisResult(w io.Writer, result *output.AnalysisResult) error {
+	return writeArgoParameters(w, affectedPipelineResources(result))
+}
+
+// WriteResourceList writes the parameters of all resources (-list)
+func (argoEmitter) WriteResourceList(w io.Writer, list *output.ResourceListResult) error {
+	return writeArgoParameters(w, pipelineResources(list.Resources))
+}
+
+// writeArgoParameters writes the parameters as JSON; Argo parameters are strings, so the list is JSON-encoded
+func writeArgoParameters(w io.Writer, resources []pipelineResource) error {
+	list, err := json.Marshal(resources)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string]string{
+		"affected-resources": string(list),
+		"count":              fmt.Sprint(len(resources)),
+	})
+}
+
+// tektonEmitter writes the params of a Tekton PipelineRun or TriggerTemplate: the names and the types
+// of the resources as array params in the same order, for a matrix fanning out per resource
+type tektonEmitter struct{}
+
+// newTektonEmitter creates the Tekton params writer; the step command template is not used
+func newTektonEmitter(*template.Template) output.Writer {
+	return tektonEmitter{}
+}
+
+// WriteAnalysisResult writes the params of the affected resources
+func (tektonEmitter) WriteAnalysisResult(w io.Writer, result *output.AnalysisResult) error {
+	return writeTektonParams(w, affectedPipelineResources(result))
+}
+
+// WriteResourceList writes the params of all resources (-list)
+func (tektonEmitter) WriteResourceList(w io.Writer, list *output.ResourceListResult) error {
+	return writeTektonParams(w, pipelineResources(list.Resources))
+}
+
+// writeTektonParams writes the params as a YAML list of name-value pairs
+func writeTektonParams(w io.Writer, resources []pipelineResource) error {
+	var b bytes.Buffer
+	writeArray := func(name string, value func(r pipelineResource) string) {
+		fmt.Fprintf(&b, "- name: %s\n", name)
+		if len(resources) == 0 {
+			fmt.Fprintln(&b, "  value: []")
+			return
+		}
+		fmt.Fprintln(&b, "  value:")
+		for _, r := range resources {
+			fmt.Fprintf(&b, "    - %s\n", yamlString(value(r)))
+		}
+	}
+	writeArray("affected-resources", func(r pipelineResource) string { return r.Name })
+	writeArray("affected-resource-types", func(r pipelineResource) string { return string(r.Type) })
+	fmt.Fprintf(&b, "- name: count\n  value: %s\n", yamlString(fmt.Sprint(len(resources))))
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// invalidKeyChars matches the characters not allowed in Buildkite step keys
+var invalidKeyChars = regexp.MustCompile(`[^A-Za-z0-9_:-]+`)
+
+// stepKey returns the step key of a resource name, with invalid characters replaced by dashes
+func stepKey(name string) string {
+	return "impact-" + invalidKeyChars.ReplaceAllString(name, "-")
+}
+
+// yamlString quotes a string as a JSON string, which is a valid double-quoted YAML scalar
+func yamlString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
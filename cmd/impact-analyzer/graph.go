@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// runGraph runs the graph subcommand
+func runGraph(args []string) {
+	var opts commonOptions
+
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	opts.register(fs)
+	opts.registerOutputs(fs)
+	fs.Parse(args)
+
+	fileCfg := opts.loadConfig()
+	targets, err := opts.reportTargets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	a := opts.analyze(fileCfg)
+
+	printReport(graphReport{a.ExportGraph()}, targets, false)
+}
+
+// graphReport is the dependency graph of the packages and resources
+type graphReport struct {
+	*analyzer.GraphExport
+}
+
+// WriteText outputs the dependency graph in text format
+func (r graphReport) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "=== Dependency Graph: %s ===\n", r.Module)
+	fmt.Fprintf(&b, "%d packages, %d imports (%d test-only, %d tool imports excluded)\n", len(r.Nodes), len(r.Edges), len(r.TestEdges), len(r.ToolEdges))
+	fmt.Fprintln(&b)
+
+	deps := make(map[string][]string)
+	for _, edge := range r.Edges {
+		deps[edge.From] = append(deps[edge.From], edge.To)
+	}
+	testDeps := make(map[string][]string)
+	for _, edge := range r.TestEdges {
+		testDeps[edge.From] = append(testDeps[edge.From], edge.To)
+	}
+	toolDeps := make(map[string][]string)
+	for _, edge := range r.ToolEdges {
+		toolDeps[edge.From] = append(toolDeps[edge.From], edge.To)
+	}
+
+	for _, node := range r.Nodes {
+		if len(node.Resources) > 0 {
+			fmt.Fprintf(&b, "%s [%s]\n", node.Package, strings.Join(node.Resources, ", "))
+		} else {
+			fmt.Fprintln(&b, node.Package)
+		}
+		for _, dep := range deps[node.Package] {
+			fmt.Fprintf(&b, "  -> %s\n", dep)
+		}
+		for _, dep := range testDeps[node.Package] {
+			fmt.Fprintf(&b, "  -> %s (test)\n", dep)
+		}
+		for _, dep := range toolDeps[node.Package] {
+			fmt.Fprintf(&b, "  -> %s (tool)\n", dep)
+		}
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
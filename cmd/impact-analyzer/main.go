@@ -2,121 +2,367 @@ package main
 
 import (
 	"bufio"
-	"encoding/json"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
-	"github.com/laut0104/go-impact-analyzer/internal/analyzer"
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
 )
 
-// AnalysisResult represents the analysis result
-type AnalysisResult struct {
-	ChangedPackages   []string                    `json:"changed_packages,omitempty"`
-	ChangedFiles      []string                    `json:"changed_files,omitempty"`
-	AffectedResources []analyzer.AffectedResource `json:"affected_resources"`
-	TotalResources    int                         `json:"total_resources"`
+// subcommands maps subcommand names to their entry points
+var subcommands = map[string]func(args []string){
+	"action":          runAction,
+	"api-usage":       runAPIUsage,
+	"config-diff":     runConfigDiff,
+	"deps-diff":       runDepsDiff,
+	"graph":           runGraph,
+	"protoc-plugin":   runProtocPlugin,
+	"release-notes":   runReleaseNotes,
+	"schema":          runSchema,
+	"sparse-checkout": runSparseCheckout,
+	"update":          runUpdate,
+	"version":         runVersion,
 }
 
 func main() {
+	// Installed as protoc-gen-impact (e.g., a symlink), the binary runs as a protoc/buf plugin
+	if strings.TrimSuffix(filepath.Base(os.Args[0]), ".exe") == protocPluginName {
+		runProtocPlugin(os.Args[1:])
+		return
+	}
+
+	if len(os.Args) > 1 {
+		if run, ok := subcommands[os.Args[1]]; ok {
+			run(os.Args[2:])
+			return
+		}
+	}
+
 	// Flag definitions
 	var (
+		opts          commonOptions
 		listResources bool
-		jsonOutput    bool
 		gitDiff       bool
-		baseBranch    string
 		files         string
 		packages      string
-		projectRoot   string
-		modulePath    string
-		cmdDir        string
-		pathPrefix    string
+		failOn        string
+		simulate      string
+		deprecated    bool
+		deprecatedSym string
+		unreachable   bool
+		apiStability  bool
+		apiDiff       bool
+		failOnCompat  bool
+		policy        string
+		validate      bool
+		outputVersion string
+		showSymbols   bool
+		timings       bool
+		bases         string
+		submodules    bool
+		emit          string
+		emitCommand   string
+		bisect        string
+		patches       string
+		reviewURL     string
+		resultCache   string
+		resultTTL     string
 	)
 
+	opts.register(flag.CommandLine)
+	opts.registerOutputs(flag.CommandLine)
 	flag.BoolVar(&listResources, "list", false, "List all resources")
-	flag.BoolVar(&jsonOutput, "json", false, "Output in JSON format")
 	flag.BoolVar(&gitDiff, "git-diff", false, "Analyze changes from git diff")
-	flag.StringVar(&baseBranch, "base", "main", "Base branch for git diff comparison")
 	flag.StringVar(&files, "files", "", "Comma-separated list of changed files")
 	flag.StringVar(&packages, "packages", "", "Comma-separated list of changed packages")
-	flag.StringVar(&projectRoot, "root", "", "Project root directory (default: auto-detect)")
-	flag.StringVar(&modulePath, "module", "", "Go module path (default: auto-detect from go.mod)")
-	flag.StringVar(&cmdDir, "cmd-dir", "cli/cmd", "Directory containing CLI command definitions")
-	flag.StringVar(&pathPrefix, "path-prefix", "", "Path prefix to strip from file paths (e.g., 'go/' for monorepo)")
+	flag.StringVar(&failOn, "fail-on", "", "Exit with status 2 if a resource of this severity or higher is affected (critical, normal, low)")
+	flag.StringVar(&simulate, "simulate-change", "", "Comma-separated list of symbols (pkg.Symbol or pkg.Type.Method) to treat as changed")
+	flag.BoolVar(&deprecated, "deprecated", false, "Report resources that still use symbols marked with a Deprecated: comment")
+	flag.StringVar(&deprecatedSym, "deprecated-symbols", "", "Comma-separated list of symbols (pkg.Symbol or pkg.Type.Method) to track as deprecated (implies -deprecated)")
+	flag.BoolVar(&unreachable, "unreachable", false, "Report packages no resource depends on and resources whose entry package is missing")
+	flag.BoolVar(&apiStability, "api-stability", false, "Detect breaking public-API changes between base and head and flag the resources using them")
+	flag.BoolVar(&failOnCompat, "fail-on-incompatible", false, "Exit with status 2 on incompatible public-API changes between base and head (implies -api-stability)")
+	flag.BoolVar(&apiDiff, "api-diff", false, "List the exported declarations added, removed or changed in each changed package between base and head")
+	flag.StringVar(&policy, "policy", "", "Rego policy file evaluated against the result with opa (deny and warn rules of package impact)")
+	flag.BoolVar(&validate, "validate", false, "Validate the result against the embedded JSON Schema of the JSON output before writing it")
+	flag.StringVar(&outputVersion, "output-version", outputV1, "Version of the JSON output format (v1, v2); v2 adds the confidence of affected resources")
+	flag.BoolVar(&showSymbols, "show-symbols", false, "List the changed symbols detected in each changed file")
+	flag.StringVar(&opts.resources, "resources", "", "Comma-separated resource names to limit the impact check to (default: all resources)")
+	flag.BoolVar(&timings, "timings", false, "Report how long each phase, changed package and resource check took")
+	flag.BoolVar(&submodules, "recurse-submodules", false, "With -git-diff, also analyze changed submodules holding their own Go module as separate projects")
+	flag.StringVar(&emit, "emit", "", "Write a CI pipeline with one step per affected resource ("+strings.Join(emitterNames(), ", ")+") to stdout or -o")
+	flag.StringVar(&emitCommand, "emit-command", defaultEmitCommand, "Command of the -emit pipeline steps, a text/template executed on each resource")
+	flag.StringVar(&bisect, "bisect", "", "Analyze each commit between -base and HEAD on its own and report the first one affecting the named resource")
+	flag.StringVar(&patches, "patches", "", "Comma-separated patch files (git diff output, e.g., the pull requests of a merge-queue batch) analyzed each on its own and combined in one report")
+	flag.StringVar(&reviewURL, "review-url", "", "Read the changed lines from the API of a GitHub pull request or GitLab merge request URL instead of git diff (GITHUB_TOKEN, GITLAB_TOKEN)")
+	flag.StringVar(&resultCache, "result-cache", "", "Directory keeping the results of -git-diff and -review-url analyses by base and head commits, reused by runs with the same arguments")
+	flag.StringVar(&resultTTL, "result-cache-ttl", "", "How long results of -result-cache are reused (default: 24h)")
+	flag.StringVar(&bases, "bases", "", "Comma-separated base branches to analyze the git diff against, combined in one report (e.g., main,release/1.2)")
 	flag.Parse()
 
-	// Detect project root
-	if projectRoot == "" {
-		var err error
-		projectRoot, err = detectProjectRoot()
+	fileCfg := opts.loadConfig()
+	failOnCompat = failOnCompat || fileCfg.FailOnIncompatible
+	apiStability = apiStability || failOnCompat
+	if failOn == "" {
+		failOn = string(fileCfg.FailOn)
+	}
+	if failOn != "" && !analyzer.Severity(failOn).IsValid() {
+		fmt.Fprintf(os.Stderr, "Error: invalid -fail-on severity %q\n", failOn)
+		os.Exit(1)
+	}
+
+	if outputVersion != outputV1 && outputVersion != outputV2 {
+		fmt.Fprintf(os.Stderr, "Error: invalid -output-version %q (want v1 or v2)\n", outputVersion)
+		os.Exit(1)
+	}
+
+	// The pipeline of -emit takes the place of the result, which can still be written with -output
+	var targets []outputTarget
+	var pipeline *outputTarget
+	if emit != "" {
+		writer, err := newEmitter(emit, emitCommand)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to detect project root: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		pipeline = &outputTarget{writer: writer, format: emit, path: "-"}
+		if opts.outputPath != "" {
+			pipeline.path = opts.outputPath
+		}
+		if opts.outputs != "" {
+			if targets, err = parseOutputs(opts.outputs, opts.outputOptions()); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	} else {
+		var err error
+		if targets, err = opts.outputTargets(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if pipeline != nil {
+		for _, t := range targets {
+			if t.path == "-" && pipeline.path == "-" {
+				fmt.Fprintf(os.Stderr, "Error: -emit writes the pipeline to stdout: write -output to files or the pipeline to -o\n")
+				os.Exit(1)
+			}
+		}
+		targets = append(targets, *pipeline)
+	}
+	if bisect != "" || unreachable || deprecated || deprecatedSym != "" {
+		if err := checkReportTargets(targets, validate); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
 	}
 
-	// Detect module path from go.mod if not specified
-	if modulePath == "" {
+	// The changes of a review are read first, as they identify its commits for the result cache
+	var reviewClient analyzer.GitClient
+	var reviewProvider analyzer.ReviewProvider
+	if reviewURL != "" && !gitDiff {
 		var err error
-		modulePath, err = detectModulePath(projectRoot)
+		if reviewProvider, err = newReviewProvider(reviewURL); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		reviewClient, err = analyzer.NewReviewGitClient(opts.projectRoot, opts.baseBranch, opts.diffExcludes(), reviewProvider)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to detect module path: %v\n", err)
-			fmt.Fprintf(os.Stderr, "Please specify -module flag\n")
+			fmt.Fprintf(os.Stderr, "Error: failed to get the changes of %s: %v\n", reviewURL, err)
 			os.Exit(1)
 		}
 	}
 
-	// Create Analyzer
-	cfg := analyzer.Config{
-		ModulePath:  modulePath,
-		ProjectRoot: projectRoot,
-		CmdDir:      cmdDir,
-		PathPrefix:  pathPrefix,
-		BaseBranch:  baseBranch,
+	// Result cache: analyses of the same commits with the same arguments reuse the result
+	if resultCache == "" {
+		resultCache = fileCfg.ResultCache
+	}
+	if resultTTL == "" {
+		resultTTL = fileCfg.ResultCacheTTL
+	}
+	cache, err := newResultCache(resultCache, resultTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	otherMode := listResources || bisect != "" || unreachable || deprecated || deprecatedSym != "" || simulate != "" || patches != "" || bases != ""
+	cacheKey, cacheable := "", false
+	if cache != nil && !otherMode {
+		settings := strings.Join(os.Args[1:], "\x00")
+		if gitDiff {
+			cacheKey, cacheable = cache.gitResultKey(&opts, settings)
+		} else if reviewProvider != nil {
+			cacheKey, cacheable = cache.reviewResultKey(&opts, reviewProvider, settings)
+		}
+	}
+	if cacheable {
+		if result, ok := cache.get(cacheKey); ok {
+			fmt.Fprintf(os.Stderr, "Using the cached result of the same commits (%s)\n", resultCache)
+			result = forVersion(result, outputVersion)
+			applyPolicy(result, policy)
+			printResult(result, targets, validate)
+			exitOnDegradation(result, opts.strict)
+			exitOnPolicy(result)
+			exitOnIncompatible(result, failOnCompat)
+			exitOnSeverity(result, analyzer.Severity(failOn))
+			return
+		}
 	}
-	a := analyzer.NewAnalyzer(cfg)
 
-	// Run analysis
-	fmt.Fprintf(os.Stderr, "Analyzing project at %s...\n", projectRoot)
-	if err := a.Analyze(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: failed to analyze: %v\n", err)
+	a := opts.analyze(fileCfg)
+	if err := checkResourceNames(a, opts.resourceList()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
-	fmt.Fprintf(os.Stderr, "Found %d resources\n", len(a.GetResources()))
 
 	// Resource list mode
 	if listResources {
-		if jsonOutput {
-			printResourceListJSON(a.GetResources())
-		} else {
-			printResourceListText(a.GetResources())
+		printResourceList(a.GetResources(), targets, validate)
+		return
+	}
+
+	// Bisect mode: the first commit of the branch affecting a resource
+	if bisect != "" {
+		result, err := bisectResource(a, &opts, fileCfg, cache, bisect)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		printReport(result, targets, validate)
+		return
+	}
+
+	// Unreachable code mode
+	if unreachable {
+		printReport(unreachableReport{a.FindUnreachable()}, targets, validate)
+		return
+	}
+
+	// Deprecation tracking mode
+	if deprecated || deprecatedSym != "" {
+		var refs []analyzer.SymbolRef
+		if deprecatedSym != "" {
+			for _, sym := range strings.Split(deprecatedSym, ",") {
+				ref, err := a.ParseSymbolRef(sym)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				refs = append(refs, ref)
+			}
+		}
+
+		printReport(newDeprecationReport(a.FindDeprecatedUsages(refs)), targets, validate)
+		return
+	}
+
+	// Simulation mode: pretend the given symbols changed
+	if simulate != "" {
+		var refs []analyzer.SymbolRef
+		for _, sym := range strings.Split(simulate, ",") {
+			ref, err := a.ParseSymbolRef(sym)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			refs = append(refs, ref)
+		}
+
+		impact, err := a.SimulateChange(refs)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: failed to simulate change: %v\n", err)
+			os.Exit(1)
+		}
+
+		result := &output.AnalysisResult{
+			AffectedResources: impact.Affected,
+			Images:            analyzer.ImagesToRebuild(impact.Affected),
+			Applications:      analyzer.ApplicationsToSync(impact.Affected),
+			ImportViolations:  a.GetImportViolations(),
+			Warnings:          impact.Warnings,
+			TotalResources:    len(a.GetResources()),
+		}
+		simulatedPackages := make(map[string]bool)
+		for _, ref := range refs {
+			result.SimulatedSymbols = append(result.SimulatedSymbols, ref.String())
+			simulatedPackages[ref.Package] = true
+		}
+		result.Summary = impact.Summary
+		result.Summary.ChangedPackages = len(simulatedPackages)
+		result.Summary.ChangedSymbols = len(refs)
+
+		if timings {
+			result.Timings = analyzer.MergeTimings(a.GetTimings(), impact.Timings)
+		}
+		result = forVersion(result, outputVersion)
+		applyPolicy(result, policy)
+		printResult(result, targets, validate)
+		exitOnDegradation(result, opts.strict)
+		exitOnPolicy(result)
+		exitOnSeverity(result, analyzer.Severity(failOn))
+		return
+	}
+
+	// Batch mode: impact of each patch of a merge-queue batch, combined
+	if patches != "" {
+		result, err := analyzePatches(a, &opts, strings.Split(patches, ","))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !timings {
+			result.Timings = nil
+		}
+		result = forVersion(result, outputVersion)
+		applyPolicy(result, policy)
+		printResult(result, targets, validate)
+		exitOnDegradation(result, opts.strict)
+		exitOnPolicy(result)
+		exitOnSeverity(result, analyzer.Severity(failOn))
+		return
+	}
+
+	// Multi-base mode: impact relative to each base branch, combined
+	if bases != "" {
+		result, err := analyzeBases(a, &opts, strings.Split(bases, ","))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if !timings {
+			result.Timings = nil
 		}
+		result = forVersion(result, outputVersion)
+		applyPolicy(result, policy)
+		printResult(result, targets, validate)
+		exitOnDegradation(result, opts.strict)
+		exitOnPolicy(result)
+		exitOnSeverity(result, analyzer.Severity(failOn))
 		return
 	}
 
 	// Get changed files
 	var changedFiles []string
+	// allChangedFiles includes files outside the path prefix (e.g., Terraform at the repository root)
+	var allChangedFiles []string
 
 	if gitDiff {
-		// Use GitClient for git operations
-		gitClient := analyzer.NewGitClient(projectRoot, baseBranch)
-		allFiles, err := gitClient.GetChangedFiles(baseBranch)
+		var err error
+		changedFiles, allChangedFiles, err = gitChangedFiles(&opts)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: failed to get git diff: %v\n", err)
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 			os.Exit(1)
 		}
-		// Filter files by path prefix and .go extension
-		for _, file := range allFiles {
-			if !strings.HasSuffix(file, ".go") {
-				continue
-			}
-			if pathPrefix != "" && !strings.HasPrefix(file, pathPrefix) {
-				continue
-			}
-			changedFiles = append(changedFiles, file)
-		}
+	} else if reviewClient != nil {
+		// Only the packages parsed by the analysis are read from the checkout, which may be at another revision
+		allChangedFiles, _ = reviewClient.GetChangedFiles(opts.baseBranch)
+		changedFiles = filterPathPrefix(allChangedFiles, opts.pathPrefix)
+		a = a.WithGitClient(opts.baseBranch, reviewClient)
 	} else if files != "" {
 		changedFiles = strings.Split(files, ",")
 		for i, f := range changedFiles {
@@ -125,9 +371,11 @@ func main() {
 	} else if packages != "" {
 		// Package specification mode
 		pkgList := strings.Split(packages, ",")
-		result := &AnalysisResult{
+		result := &output.AnalysisResult{
 			ChangedPackages:   pkgList,
 			AffectedResources: make([]analyzer.AffectedResource, 0),
+			ImportViolations:  a.GetImportViolations(),
+			Warnings:          a.GetWarnings(),
 			TotalResources:    len(a.GetResources()),
 		}
 
@@ -139,8 +387,21 @@ func main() {
 
 		// Remove duplicates
 		result.AffectedResources = uniqueAffectedResources(result.AffectedResources)
-
-		printResult(result, jsonOutput)
+		analyzer.SortAffectedResources(result.AffectedResources)
+		result.Images = analyzer.ImagesToRebuild(result.AffectedResources)
+		result.Applications = analyzer.ApplicationsToSync(result.AffectedResources)
+		result.Summary = analyzer.SummarizeAffected(result.AffectedResources, result.TotalResources)
+		result.Summary.ChangedPackages = len(pkgList)
+
+		if timings {
+			result.Timings = a.GetTimings()
+		}
+		result = forVersion(result, outputVersion)
+		applyPolicy(result, policy)
+		printResult(result, targets, validate)
+		exitOnDegradation(result, opts.strict)
+		exitOnPolicy(result)
+		exitOnSeverity(result, analyzer.Severity(failOn))
 		return
 	} else {
 		// Read from stdin
@@ -157,145 +418,271 @@ func main() {
 		}
 	}
 
-	if len(changedFiles) == 0 {
+	if allChangedFiles == nil {
+		allChangedFiles = changedFiles
+	}
+	infraChanges := a.GetIaCChanges(allChangedFiles)
+
+	if len(changedFiles) == 0 && len(infraChanges) == 0 {
 		fmt.Fprintln(os.Stderr, "No changed files specified")
 		fmt.Fprintln(os.Stderr, "Usage:")
 		fmt.Fprintln(os.Stderr, "  impact-analyzer -git-diff              # Analyze git changes")
 		fmt.Fprintln(os.Stderr, "  impact-analyzer -files=file1.go,file2.go")
 		fmt.Fprintln(os.Stderr, "  impact-analyzer -packages=pkg1,pkg2")
+		fmt.Fprintln(os.Stderr, "  impact-analyzer -simulate-change=pkg.Symbol")
 		fmt.Fprintln(os.Stderr, "  echo 'file.go' | impact-analyzer")
 		fmt.Fprintln(os.Stderr, "  impact-analyzer -list                  # List all resources")
 		os.Exit(0)
 	}
 
 	// Impact analysis
-	affected := a.GetAffectedResources(changedFiles)
+	impact := a.GetImpact(changedFiles)
+	result := impactResult(a, changedFiles, infraChanges, impact)
+	if gitDiff && submodules {
+		if err := analyzeSubmodules(result, &opts); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if showSymbols {
+		result.ChangedSymbols = a.GetChangedSymbolsByFile(changedFiles)
+	}
 
-	result := &AnalysisResult{
-		ChangedFiles:      changedFiles,
-		AffectedResources: affected,
-		TotalResources:    len(a.GetResources()),
+	// API stability: flag resources using symbols removed or changed incompatibly
+	if apiStability || apiDiff {
+		apiChanges := a.GetAPIChanges(changedFiles)
+		if apiDiff {
+			result.APIChanges = apiChanges
+		}
+		if apiStability {
+			result.BreakingChanges = analyzer.BreakingChanges(apiChanges)
+			a.MarkBreakingResources(result.AffectedResources, result.BreakingChanges)
+		}
 	}
 
-	printResult(result, jsonOutput)
+	if cacheable {
+		if err := cache.put(cacheKey, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if timings {
+		result.Timings = analyzer.MergeTimings(a.GetTimings(), impact.Timings)
+	}
+	result = forVersion(result, outputVersion)
+	applyPolicy(result, policy)
+	printResult(result, targets, validate)
+	exitOnDegradation(result, opts.strict)
+	exitOnPolicy(result)
+	exitOnIncompatible(result, failOnCompat)
+	exitOnSeverity(result, analyzer.Severity(failOn))
 }
 
-// printResult outputs analysis result
-func printResult(result *AnalysisResult, jsonOutput bool) {
-	if jsonOutput {
-		encoder := json.NewEncoder(os.Stdout)
-		encoder.SetIndent("", "  ")
-		if err := encoder.Encode(result); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+// gitChangedFiles returns the files changed from the base branch under the path prefix, and all changed files
+// Files outside the path prefix (e.g., Terraform at the repository root) are still needed for infrastructure changes
+func gitChangedFiles(opts *commonOptions) (changedFiles, allChangedFiles []string, err error) {
+	gitClient := analyzer.NewGitClientWithExcludes(opts.projectRoot, opts.baseBranch, opts.diffExcludes())
+	allChangedFiles, err = gitClient.GetChangedFiles(opts.baseBranch)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get git diff: %w", err)
+	}
+	return filterPathPrefix(allChangedFiles, opts.pathPrefix), allChangedFiles, nil
+}
+
+// filterPathPrefix returns the files under the path prefix, slash-separated like the paths git lists
+// Non-Go files are kept since they may be embedded via //go:embed
+func filterPathPrefix(files []string, pathPrefix string) []string {
+	pathPrefix = filepath.ToSlash(pathPrefix)
+	var filtered []string
+	for _, file := range files {
+		if pathPrefix != "" && !strings.HasPrefix(file, pathPrefix) {
+			continue
 		}
-		return
+		filtered = append(filtered, file)
 	}
+	return filtered
+}
 
-	fmt.Println("=== Impact Analysis Result ===")
-	fmt.Println()
+// analyzeChangedFiles runs the impact analysis of changed files and builds the result
+func analyzeChangedFiles(a *analyzer.Analyzer, changedFiles []string, infraChanges []analyzer.IaCChange) *output.AnalysisResult {
+	return impactResult(a, changedFiles, infraChanges, a.GetImpact(changedFiles))
+}
 
-	if len(result.ChangedFiles) > 0 {
-		fmt.Println("Changed Files:")
-		for _, f := range result.ChangedFiles {
-			fmt.Printf("  - %s\n", f)
-		}
-		fmt.Println()
+// impactResult builds the result of the impact analysis of changed files
+func impactResult(a *analyzer.Analyzer, changedFiles []string, infraChanges []analyzer.IaCChange, impact *analyzer.Impact) *output.AnalysisResult {
+	return &output.AnalysisResult{
+		ChangedFiles:      changedFiles,
+		InfraChanges:      infraChanges,
+		AffectedResources: impact.Affected,
+		Images:            analyzer.ImagesToRebuild(impact.Affected),
+		Applications:      analyzer.ApplicationsToSync(impact.Affected),
+		ImportViolations:  a.GetImportViolations(),
+		Warnings:          impact.Warnings,
+		Summary:           impact.Summary,
+		TotalResources:    len(a.GetResources()),
 	}
+}
 
-	if len(result.ChangedPackages) > 0 {
-		fmt.Println("Changed Packages:")
-		for _, p := range result.ChangedPackages {
-			fmt.Printf("  - %s\n", p)
+// forVersion returns the result in the structure of an output version
+// Fields introduced by later versions are cleared so that older consumers see an unchanged format
+func forVersion(r *output.AnalysisResult, outputVersion string) *output.AnalysisResult {
+	result := *r
+	result.SchemaVersion = schemaVersionOf(outputVersion)
+	if outputVersion == outputV1 {
+		result.AffectedResources = withoutConfidence(r.AffectedResources)
+		result.Bases = make([]output.BaseImpact, len(r.Bases))
+		for i, b := range r.Bases {
+			b.AffectedResources = withoutConfidence(b.AffectedResources)
+			result.Bases[i] = b
+		}
+		result.Patches = make([]output.PatchImpact, len(r.Patches))
+		for i, p := range r.Patches {
+			p.AffectedResources = withoutConfidence(p.AffectedResources)
+			result.Patches[i] = p
 		}
-		fmt.Println()
 	}
+	return &result
+}
 
-	fmt.Printf("Affected Resources (%d):\n", len(result.AffectedResources))
-	if len(result.AffectedResources) == 0 {
-		fmt.Println("  (none)")
-	} else {
-		for _, r := range result.AffectedResources {
-			fmt.Printf("  [%s] %s\n", r.Type, r.Name)
-			fmt.Printf("    Reason: %s\n", r.Reason)
-			if len(r.DependencyChain) > 0 {
-				fmt.Printf("    Chain: %s\n", strings.Join(r.DependencyChain, " -> "))
-			}
+// withoutConfidence returns a copy of the affected resources with the confidence cleared
+func withoutConfidence(resources []analyzer.AffectedResource) []analyzer.AffectedResource {
+	result := make([]analyzer.AffectedResource, len(resources))
+	for i, ar := range resources {
+		ar.Confidence = ""
+		result[i] = ar
+	}
+	return result
+}
+
+// checkResourceNames reports names that do not match a resource, which would otherwise never be affected
+func checkResourceNames(a *analyzer.Analyzer, names []string) error {
+	known := make(map[string]bool)
+	for _, r := range a.GetResources() {
+		known[r.Name] = true
+	}
+	for _, name := range names {
+		if !known[name] {
+			return fmt.Errorf("unknown resource %q in -resources", name)
 		}
 	}
+	return nil
 }
 
-// printResourceListJSON outputs resource list in JSON format
-func printResourceListJSON(resources []analyzer.Resource) {
-	result := struct {
-		Resources []analyzer.Resource `json:"resources"`
-		Total     int                 `json:"total"`
-	}{
-		Resources: resources,
-		Total:     len(resources),
+// printResult validates the analysis result if requested and writes it to the outputs
+func printResult(result *output.AnalysisResult, targets []outputTarget, validate bool) {
+	result.BuildInfo = currentBuildInfo()
+	if validate {
+		if err := validateOutput(analysisResultSchema, result.SchemaVersion, result); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
+	for _, t := range targets {
+		err := t.writeTo(func(w io.Writer) error {
+			return t.writer.WriteAnalysisResult(w, result)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
 
-	encoder := json.NewEncoder(os.Stdout)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(result); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+// exitOnDegradation exits with status 1 in strict mode if the result is partial or has an unknown impact
+func exitOnDegradation(result *output.AnalysisResult, strict bool) {
+	if !strict {
+		return
+	}
+	unknown := 0
+	for _, r := range result.AffectedResources {
+		if r.UnknownImpact {
+			unknown++
+		}
+	}
+	if len(result.Warnings) > 0 || unknown > 0 {
+		fmt.Fprintf(os.Stderr, "Error: strict mode: analysis degraded (%d warnings, %d resources with unknown impact)\n", len(result.Warnings), unknown)
 		os.Exit(1)
 	}
 }
 
-// printResourceListText outputs resource list in text format
-func printResourceListText(resources []analyzer.Resource) {
-	fmt.Println("=== Resources ===")
-	fmt.Println()
-
-	// Classify by type
-	var apiResources, jobResources, workerResources []analyzer.Resource
+// exitOnIncompatible exits with status 2 if the public API changed incompatibly
+func exitOnIncompatible(result *output.AnalysisResult, failOnIncompatible bool) {
+	if !failOnIncompatible || len(result.BreakingChanges) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "%d incompatible API changes (fail-on-incompatible)\n", len(result.BreakingChanges))
+	os.Exit(2)
+}
 
-	for _, r := range resources {
-		switch r.Type {
-		case analyzer.ResourceTypeAPI:
-			apiResources = append(apiResources, r)
-		case analyzer.ResourceTypeJob:
-			jobResources = append(jobResources, r)
-		case analyzer.ResourceTypeWorker:
-			workerResources = append(workerResources, r)
-		}
-	}
-
-	if len(apiResources) > 0 {
-		fmt.Printf("API Services (%d):\n", len(apiResources))
-		for _, r := range apiResources {
-			fmt.Printf("  - %s: %s\n", r.Name, r.Description)
-			if r.Package != "" {
-				fmt.Printf("    Package: %s\n", r.Package)
-			}
+// exitOnSeverity exits with status 2 if an affected resource meets the fail-on severity
+func exitOnSeverity(result *output.AnalysisResult, failOn analyzer.Severity) {
+	if failOn == "" {
+		return
+	}
+	for _, r := range result.AffectedResources {
+		if r.Severity.Rank() >= failOn.Rank() {
+			fmt.Fprintf(os.Stderr, "Resource %s with severity %s is affected (fail-on: %s)\n", r.Name, r.Severity, failOn)
+			os.Exit(2)
 		}
-		fmt.Println()
 	}
+}
 
-	if len(jobResources) > 0 {
-		fmt.Printf("Jobs (%d):\n", len(jobResources))
-		for _, r := range jobResources {
-			fmt.Printf("  - %s: %s\n", r.Name, r.Description)
-			if r.Package != "" {
-				fmt.Printf("    Package: %s\n", r.Package)
-			}
+// printResourceList validates the resource list if requested and writes it to the outputs
+func printResourceList(resources []analyzer.Resource, targets []outputTarget, validate bool) {
+	if resources == nil {
+		resources = []analyzer.Resource{}
+	}
+	list := &output.ResourceListResult{
+		SchemaVersion: resourceListVersion,
+		Resources:     resources,
+		Total:         len(resources),
+	}
+	if validate {
+		if err := validateOutput(resourceListSchema, resourceListVersion, list); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
 		}
-		fmt.Println()
 	}
+	for _, t := range targets {
+		err := t.writeTo(func(w io.Writer) error {
+			return t.writer.WriteResourceList(w, list)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
 
-	if len(workerResources) > 0 {
-		fmt.Printf("Workers (%d):\n", len(workerResources))
-		for _, r := range workerResources {
-			fmt.Printf("  - %s: %s\n", r.Name, r.Description)
-			if r.Package != "" {
-				fmt.Printf("    Package: %s\n", r.Package)
-			}
+// checkReportTargets checks that the outputs can write reports, before a report is made
+func checkReportTargets(targets []outputTarget, validate bool) error {
+	if validate {
+		return fmt.Errorf("-validate only applies to the analysis result and the resource list")
+	}
+	for _, t := range targets {
+		if _, ok := t.writer.(output.ReportWriter); !ok {
+			return fmt.Errorf("the %s output cannot write this report (want json, markdown or text)", t.format)
 		}
-		fmt.Println()
 	}
+	return nil
+}
 
-	fmt.Printf("Total: %d resources\n", len(resources))
+// printReport writes a report of another mode or subcommand to the outputs
+// Reports have no JSON Schema, and only the formats implementing output.ReportWriter can write them
+func printReport(report output.Report, targets []outputTarget, validate bool) {
+	if err := checkReportTargets(targets, validate); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	for _, t := range targets {
+		writer := t.writer.(output.ReportWriter)
+		err := t.writeTo(func(w io.Writer) error {
+			return writer.WriteReport(w, report)
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
 
 // detectProjectRoot detects the project root
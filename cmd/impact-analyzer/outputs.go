@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+)
+
+// outputTarget is a writer and the destination of its output
+type outputTarget struct {
+	writer output.Writer
+	// format is the name of the writer's format
+	format string
+	// path is the output file ("-" for stdout)
+	path string
+}
+
+// parseOutputs parses a comma-separated list of format=path pairs ("-" writes to stdout)
+func parseOutputs(spec string, opts output.Options) ([]outputTarget, error) {
+	var targets []outputTarget
+	stdout := false
+	for _, entry := range strings.Split(spec, ",") {
+		format, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || format == "" || path == "" {
+			return nil, fmt.Errorf("invalid output %q (want format=path)", entry)
+		}
+		if path == "-" {
+			if stdout {
+				return nil, fmt.Errorf("only one output can be written to stdout")
+			}
+			stdout = true
+		}
+		writer, err := output.New(format, opts)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, outputTarget{writer: writer, format: format, path: path})
+	}
+	return targets, nil
+}
+
+// writeTo writes an output to the target's destination
+// Files are written atomically: an interrupted run leaves the previous file intact instead of a truncated one
+func (t outputTarget) writeTo(write func(w io.Writer) error) error {
+	if t.path == "-" {
+		return write(os.Stdout)
+	}
+	if err := writeFileAtomic(t.path, 0o644, write); err != nil {
+		return fmt.Errorf("failed to write %s: %w", t.path, err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes a file through a temporary file in the same directory renamed over the path
+// Missing parent directories are created
+func writeFileAtomic(path string, perm os.FileMode, write func(w io.Writer) error) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	tmpPath := f.Name()
+	// The temporary file is removed unless it was renamed
+	defer os.Remove(tmpPath)
+
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// analyzePatches analyzes each patch of a batch on its own (e.g., the pull requests of a merge-queue batch)
+// and combines the results: the affected resources of all patches decide which test suites the batch
+// runs, and per-patch impacts in Patches attribute them to the pull requests
+func analyzePatches(a *analyzer.Analyzer, opts *commonOptions, paths []string) (*output.AnalysisResult, error) {
+	result := &output.AnalysisResult{
+		ImportViolations:  a.GetImportViolations(),
+		AffectedResources: []analyzer.AffectedResource{},
+		TotalResources:    len(a.GetResources()),
+	}
+
+	// The summary counts the changed symbols of each package in any patch
+	changedSymbols := make(map[string][]string)
+	var allChangedFiles []string
+	// Timings of the analyses of each patch add up
+	timings := []*analyzer.Timings{a.GetTimings()}
+	for _, path := range paths {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		files, err := analyzer.ParsePatch(string(data))
+		if err != nil {
+			return nil, fmt.Errorf("patch %s: %w", path, err)
+		}
+
+		client := analyzer.NewPatchGitClient(opts.projectRoot, opts.baseBranch, opts.diffExcludes(), files)
+		patchFiles, _ := client.GetChangedFiles(opts.baseBranch)
+		changedFiles := filterPathPrefix(patchFiles, opts.pathPrefix)
+
+		pa := a.WithGitClient(opts.baseBranch, client)
+		impact := pa.GetImpact(changedFiles)
+		timings = append(timings, impact.Timings)
+		for pkgPath, symbols := range impact.ChangedSymbols {
+			changedSymbols[pkgPath] = append(changedSymbols[pkgPath], symbols...)
+		}
+		r := impactResult(pa, changedFiles, pa.GetIaCChanges(patchFiles), impact)
+		if changedFiles == nil {
+			changedFiles = []string{}
+		}
+		// Patches are named after their file, e.g., pr-123.diff is pr-123
+		result.Patches = append(result.Patches, output.PatchImpact{
+			Patch:             strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+			ChangedFiles:      changedFiles,
+			AffectedResources: r.AffectedResources,
+		})
+
+		result.ChangedFiles = append(result.ChangedFiles, changedFiles...)
+		result.InfraChanges = append(result.InfraChanges, r.InfraChanges...)
+		result.AffectedResources = append(result.AffectedResources, r.AffectedResources...)
+		result.Warnings = append(result.Warnings, r.Warnings...)
+		allChangedFiles = append(allChangedFiles, changedFiles...)
+	}
+
+	// A resource affected by several patches is reported once, with the reason of the first patch
+	result.ChangedFiles = uniqueStrings(result.ChangedFiles)
+	result.InfraChanges = uniqueInfraChanges(result.InfraChanges)
+	result.AffectedResources = uniqueAffectedResources(result.AffectedResources)
+	result.Warnings = uniqueWarnings(result.Warnings)
+	analyzer.SortAffectedResources(result.AffectedResources)
+	result.Images = analyzer.ImagesToRebuild(result.AffectedResources)
+	result.Applications = analyzer.ApplicationsToSync(result.AffectedResources)
+	result.Summary = analyzer.Summarize(len(uniqueStrings(allChangedFiles)), changedSymbols, result.AffectedResources, result.TotalResources)
+	result.Timings = analyzer.MergeTimings(timings...)
+	return result, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+)
+
+// policyQuery is the Rego package evaluated against the analysis result
+// Policies define "deny" and "warn" sets of messages in package impact
+const policyQuery = "data.impact"
+
+// evaluatePolicy evaluates a Rego policy against the analysis result with the opa CLI
+func evaluatePolicy(policyPath string, result *output.AnalysisResult) ([]output.PolicyViolation, error) {
+	input, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("opa", "eval", "--format", "json", "--stdin-input", "--data", policyPath, policyQuery)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("failed to evaluate policy: %w: %s", err, msg)
+		}
+		return nil, fmt.Errorf("failed to evaluate policy: %w", err)
+	}
+
+	var evaluation struct {
+		Result []struct {
+			Expressions []struct {
+				Value struct {
+					Deny []json.RawMessage `json:"deny"`
+					Warn []json.RawMessage `json:"warn"`
+				} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out, &evaluation); err != nil {
+		return nil, fmt.Errorf("failed to parse policy result: %w", err)
+	}
+
+	var violations []output.PolicyViolation
+	for _, r := range evaluation.Result {
+		for _, expr := range r.Expressions {
+			for _, msg := range expr.Value.Deny {
+				violations = append(violations, output.PolicyViolation{Level: "deny", Message: policyMessage(msg)})
+			}
+			for _, msg := range expr.Value.Warn {
+				violations = append(violations, output.PolicyViolation{Level: "warn", Message: policyMessage(msg)})
+			}
+		}
+	}
+	return violations, nil
+}
+
+// policyMessage returns a rule message; non-string messages are kept as JSON
+func policyMessage(raw json.RawMessage) string {
+	var msg string
+	if err := json.Unmarshal(raw, &msg); err == nil {
+		return msg
+	}
+	return string(raw)
+}
+
+// applyPolicy evaluates the policy, if any, and records its violations in the result
+func applyPolicy(result *output.AnalysisResult, policyPath string) {
+	if policyPath == "" {
+		return
+	}
+	violations, err := evaluatePolicy(policyPath, result)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	result.PolicyViolations = violations
+}
+
+// exitOnPolicy exits with status 3 if a deny rule of the policy matched
+func exitOnPolicy(result *output.AnalysisResult) {
+	denied := 0
+	for _, v := range result.PolicyViolations {
+		if v.Level == "deny" {
+			denied++
+		}
+	}
+	if denied > 0 {
+		fmt.Fprintf(os.Stderr, "Policy denied the change (%d violations)\n", denied)
+		os.Exit(3)
+	}
+}
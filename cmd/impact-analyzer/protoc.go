@@ -0,0 +1,261 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+)
+
+// protocPluginName is the executable name protoc and buf look up for --impact_out (a symlink to impact-analyzer)
+const protocPluginName = "protoc-gen-impact"
+
+// Field numbers of the messages of google/protobuf/compiler/plugin.proto and descriptor.proto
+const (
+	requestFileToGenerate = 1
+	requestParameter      = 2
+	requestProtoFile      = 15
+	fileDescriptorName    = 1
+	fileDescriptorOptions = 8
+	fileOptionsGoPackage  = 11
+	responseError         = 1
+	responseFeatures      = 2
+	responseFile          = 15
+	responseFileName      = 1
+	responseFileContent   = 15
+	// featureProto3Optional declares support of proto3 optional fields, which only matter to code generators
+	featureProto3Optional = 1
+)
+
+// runProtocPlugin runs the protoc-plugin subcommand, a protoc/buf plugin reporting the Go resources
+// impacted by the proto files being generated
+// The CodeGeneratorRequest is read on stdin; the report is returned as a generated file (default: impact.json)
+func runProtocPlugin(args []string) {
+	request, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Errors are reported in the response, so that protoc and buf print them with the plugin name
+	name, content, err := protocPluginReport(request)
+	var response []byte
+	if err != nil {
+		response = appendProtoString(response, responseError, err.Error())
+	} else {
+		response = binary.AppendUvarint(appendProtoTag(response, responseFeatures, 0), featureProto3Optional)
+		var file []byte
+		file = appendProtoString(file, responseFileName, name)
+		file = appendProtoString(file, responseFileContent, content)
+		response = appendProtoBytes(response, responseFile, file)
+	}
+	if _, err := os.Stdout.Write(response); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// protocPluginReport analyzes the Go files generated from the requested proto files
+// and returns the name and content of the report
+func protocPluginReport(request []byte) (string, string, error) {
+	files, parameter, goPackages, err := parseCodeGeneratorRequest(request)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid CodeGeneratorRequest: %w", err)
+	}
+
+	// Options are passed as the plugin parameter: --impact_opt=root=.,format=markdown
+	opts := commonOptions{baseBranch: "main", cmdDir: "cli/cmd", concurrency: runtime.GOMAXPROCS(0), noColor: true}
+	format, out := "json", ""
+	for _, param := range strings.Split(parameter, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch key {
+		case "":
+		case "root":
+			opts.projectRoot = value
+		case "module":
+			opts.modulePath = value
+		case "cmd_dir":
+			opts.cmdDir = value
+		case "config":
+			opts.configPath = value
+		case "base":
+			opts.baseBranch = value
+		case "format":
+			format = value
+		case "out":
+			out = value
+		default:
+			return "", "", fmt.Errorf("unknown parameter %q (want root, module, cmd_dir, config, base, format or out)", key)
+		}
+	}
+	if out == "" {
+		ext, ok := map[string]string{"markdown": "md", "text": "txt"}[format]
+		if !ok {
+			ext = format
+		}
+		out = "impact." + ext
+	}
+
+	fileCfg := &FileConfig{}
+	if opts.configPath != "" {
+		if fileCfg, err = loadConfig(opts.configPath); err != nil {
+			return "", "", err
+		}
+	}
+	writer, err := output.New(format, opts.outputOptions())
+	if err != nil {
+		return "", "", err
+	}
+	a, err := opts.build(fileCfg)
+	if err != nil {
+		return "", "", err
+	}
+
+	changedFiles := generatedGoFiles(opts.projectRoot, opts.modulePath, files, goPackages)
+	result := forVersion(analyzeChangedFiles(a, changedFiles, nil), outputV1)
+
+	var b bytes.Buffer
+	if err := writer.WriteAnalysisResult(&b, result); err != nil {
+		return "", "", err
+	}
+	return out, b.String(), nil
+}
+
+// generatedGoFiles returns the existing Go files generated from the proto files, relative to the project root
+// Files are looked up in the directory of the go_package: <name>.pb.go, <name>_grpc.pb.go and
+// <name>.connect.go in the <package>connect subdirectory used by connect-go
+func generatedGoFiles(projectRoot, modulePath string, protoFiles []string, goPackages map[string]string) []string {
+	var files []string
+	for _, protoFile := range protoFiles {
+		importPath, _, _ := strings.Cut(goPackages[protoFile], ";")
+		if importPath != modulePath && !strings.HasPrefix(importPath, modulePath+"/") {
+			fmt.Fprintf(os.Stderr, "Warning: %s: go_package %q is not in module %s\n", protoFile, importPath, modulePath)
+			continue
+		}
+		dir := strings.TrimPrefix(strings.TrimPrefix(importPath, modulePath), "/")
+		base := strings.TrimSuffix(path.Base(protoFile), ".proto")
+		candidates := []string{
+			path.Join(dir, base+".pb.go"),
+			path.Join(dir, base+"_grpc.pb.go"),
+			path.Join(dir, path.Base(importPath)+"connect", base+".connect.go"),
+		}
+		for _, candidate := range candidates {
+			if _, err := os.Stat(filepath.Join(projectRoot, filepath.FromSlash(candidate))); err == nil {
+				files = append(files, candidate)
+			}
+		}
+	}
+	return files
+}
+
+// parseCodeGeneratorRequest decodes the files to generate, the parameter and the go_package option of each proto file
+func parseCodeGeneratorRequest(b []byte) (files []string, parameter string, goPackages map[string]string, err error) {
+	fields, err := parseProtoFields(b)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	goPackages = make(map[string]string)
+	for _, f := range fields {
+		switch f.num {
+		case requestFileToGenerate:
+			files = append(files, string(f.bytes))
+		case requestParameter:
+			parameter = string(f.bytes)
+		case requestProtoFile:
+			var name, goPackage string
+			fileFields, err := parseProtoFields(f.bytes)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			for _, ff := range fileFields {
+				switch ff.num {
+				case fileDescriptorName:
+					name = string(ff.bytes)
+				case fileDescriptorOptions:
+					options, err := parseProtoFields(ff.bytes)
+					if err != nil {
+						return nil, "", nil, err
+					}
+					for _, opt := range options {
+						if opt.num == fileOptionsGoPackage {
+							goPackage = string(opt.bytes)
+						}
+					}
+				}
+			}
+			goPackages[name] = goPackage
+		}
+	}
+	return files, parameter, goPackages, nil
+}
+
+// protoField is a field of a protobuf message in wire format
+// Only length-delimited values are kept; scalars are skipped
+type protoField struct {
+	num   int
+	bytes []byte
+}
+
+// parseProtoFields decodes the fields of a protobuf message
+func parseProtoFields(b []byte) ([]protoField, error) {
+	var fields []protoField
+	for len(b) > 0 {
+		tag, n := binary.Uvarint(b)
+		if n <= 0 {
+			return nil, errors.New("invalid field tag")
+		}
+		b = b[n:]
+		num, wireType := int(tag>>3), tag&7
+		switch wireType {
+		case 0: // varint
+			if _, n = binary.Uvarint(b); n <= 0 {
+				return nil, fmt.Errorf("invalid varint in field %d", num)
+			}
+			b = b[n:]
+		case 1: // fixed64
+			if len(b) < 8 {
+				return nil, fmt.Errorf("truncated field %d", num)
+			}
+			b = b[8:]
+		case 2: // length-delimited
+			length, n := binary.Uvarint(b)
+			if n <= 0 || uint64(len(b)-n) < length {
+				return nil, fmt.Errorf("truncated field %d", num)
+			}
+			fields = append(fields, protoField{num: num, bytes: b[n : n+int(length)]})
+			b = b[n+int(length):]
+		case 5: // fixed32
+			if len(b) < 4 {
+				return nil, fmt.Errorf("truncated field %d", num)
+			}
+			b = b[4:]
+		default:
+			return nil, fmt.Errorf("unsupported wire type %d in field %d", wireType, num)
+		}
+	}
+	return fields, nil
+}
+
+// appendProtoTag appends the tag of a field
+func appendProtoTag(b []byte, num int, wireType uint64) []byte {
+	return binary.AppendUvarint(b, uint64(num)<<3|wireType)
+}
+
+// appendProtoBytes appends a length-delimited field
+func appendProtoBytes(b []byte, num int, value []byte) []byte {
+	b = binary.AppendUvarint(appendProtoTag(b, num, 2), uint64(len(value)))
+	return append(b, value...)
+}
+
+// appendProtoString appends a string field
+func appendProtoString(b []byte, num int, value string) []byte {
+	return appendProtoBytes(b, num, []byte(value))
+}
@@ -0,0 +1,320 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// rangeCommit is a commit of an analyzed range
+type rangeCommit struct {
+	Hash    string `json:"hash"`
+	Subject string `json:"subject"`
+	// PullRequest is the number of the pull request merging the commit, read from its subject
+	PullRequest int `json:"pull_request,omitempty"`
+	// Warnings are the non-fatal errors of the analysis of the commit, which may make its resources partial
+	Warnings []*analyzer.AnalysisError `json:"warnings,omitempty"`
+	// parent is the commit the changes are compared to (the first parent)
+	parent string
+}
+
+// releaseResource lists the commits of the release affecting a resource
+type releaseResource struct {
+	Name    string                `json:"name"`
+	Type    analyzer.ResourceType `json:"type"`
+	Commits []rangeCommit         `json:"commits"`
+}
+
+// releaseNotes are the commits of a range grouped by the resources they affected
+type releaseNotes struct {
+	Range     string            `json:"range"`
+	Resources []releaseResource `json:"resources"`
+	// OtherCommits affected no resource (e.g., documentation or CI changes)
+	OtherCommits []rangeCommit `json:"other_commits"`
+}
+
+// runReleaseNotes runs the release-notes subcommand
+// Each first-parent commit of the range (a merged or squashed pull request) is analyzed on its own,
+// against its first parent, and the commits are listed under the resources they affected
+func runReleaseNotes(args []string) {
+	var (
+		opts        commonOptions
+		resultCache string
+		resultTTL   string
+	)
+
+	fs := flag.NewFlagSet("release-notes", flag.ExitOnError)
+	opts.register(fs)
+	opts.registerOutputs(fs)
+	fs.StringVar(&resultCache, "result-cache", "", "Directory keeping the results of the commits by commit and flags, reused by later release notes and -bisect runs")
+	fs.StringVar(&resultTTL, "result-cache-ttl", "", "How long cached results are reused (default: 24h)")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: impact-analyzer release-notes [flags] <from>..[<to>]\n")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		fs.Usage()
+		os.Exit(1)
+	}
+	from, to, _ := strings.Cut(fs.Arg(0), "..")
+	if from == "" {
+		fmt.Fprintf(os.Stderr, "Error: missing the start of the range %q (e.g., v1.2.0..v1.3.0)\n", fs.Arg(0))
+		os.Exit(1)
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+
+	fileCfg := opts.loadConfig()
+	targets, err := opts.reportTargets()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if resultCache == "" {
+		resultCache = fileCfg.ResultCache
+	}
+	if resultTTL == "" {
+		resultTTL = fileCfg.ResultCacheTTL
+	}
+	cache, err := newResultCache(resultCache, resultTTL)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	// The project is analyzed once for its settings (root, module) and to validate the configuration
+	opts.analyze(fileCfg)
+
+	commits, err := rangeCommits(opts.projectRoot, from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The flags, without the range, select the results of the commits shared with other ranges
+	history := newCommitHistory(opts, fileCfg, cache, strings.Join(args[:len(args)-fs.NArg()], "\x00"))
+	defer history.close()
+
+	notes := releaseNotes{Range: from + ".." + to, Resources: []releaseResource{}, OtherCommits: []rangeCommit{}}
+	byName := make(map[string]int)
+	for i, c := range commits {
+		fmt.Fprintf(os.Stderr, "Analyzing commit %d/%d %s\n", i+1, len(commits), shortHash(c.Hash))
+		affected, err := history.analyze(&c)
+		if err != nil {
+			history.close()
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(affected) == 0 {
+			notes.OtherCommits = append(notes.OtherCommits, c)
+			continue
+		}
+		for _, r := range affected {
+			i, ok := byName[r.Name]
+			if !ok {
+				i = len(notes.Resources)
+				byName[r.Name] = i
+				notes.Resources = append(notes.Resources, releaseResource{Name: r.Name, Type: r.Type})
+			}
+			notes.Resources[i].Commits = append(notes.Resources[i].Commits, c)
+		}
+	}
+	sort.Slice(notes.Resources, func(i, j int) bool {
+		return notes.Resources[i].Name < notes.Resources[j].Name
+	})
+
+	printReport(notes, targets, false)
+}
+
+// pullRequestSubject matches the pull request number of squash merges ("Fix timeout (#12)")
+// and merge commits ("Merge pull request #12 from org/branch")
+var pullRequestSubject = regexp.MustCompile(`\(#(\d+)\)$|^Merge pull request #(\d+) `)
+
+// rangeCommits returns the first-parent commits of a range, oldest first
+// The subject of a merge commit is replaced with the title of its pull request (the first line of its body)
+func rangeCommits(dir, from, to string) ([]rangeCommit, error) {
+	history, err := analyzer.FirstParentCommits(dir, from, to)
+	if err != nil {
+		return nil, err
+	}
+	commits := make([]rangeCommit, 0, len(history))
+	for _, hc := range history {
+		c := rangeCommit{Hash: hc.Hash, Subject: hc.Subject, parent: hc.Parent}
+		if m := pullRequestSubject.FindStringSubmatch(c.Subject); m != nil {
+			c.PullRequest, _ = strconv.Atoi(m[1] + m[2])
+			if title, _, _ := strings.Cut(hc.Body, "\n"); m[2] != "" && title != "" {
+				c.Subject = title
+			}
+		}
+		commits = append(commits, c)
+	}
+	return commits, nil
+}
+
+// commitHistory analyzes commits against their first parent
+// The commits are checked out one after the other in a single temporary worktree, so that symbols and
+// dependencies are those of each commit rather than of the checked out tree, where later commits may have
+// changed the same lines. Results are kept in the result cache, if any, by parent and commit, so that
+// the release notes of overlapping ranges and bisections reuse the analyses of the commits they share
+type commitHistory struct {
+	opts    commonOptions
+	fileCfg *FileConfig
+	cache   *resultCache
+	// settings are the flags the results depend on
+	settings string
+
+	// root is the project directory in the worktree, created on first use
+	root    string
+	cleanup func()
+}
+
+// newCommitHistory creates the history of a project; cache may be nil
+func newCommitHistory(opts commonOptions, fileCfg *FileConfig, cache *resultCache, settings string) *commitHistory {
+	return &commitHistory{opts: opts, fileCfg: fileCfg, cache: cache, settings: settings}
+}
+
+// analyze returns the resources affected by the changes of a commit from its first parent,
+// and records the warnings of the analysis in the commit
+func (h *commitHistory) analyze(c *rangeCommit) ([]analyzer.AffectedResource, error) {
+	changedFiles, err := commitChangedFiles(&h.opts, *c)
+	if err != nil || len(changedFiles) == 0 {
+		return nil, err
+	}
+
+	var key string
+	if h.cache != nil {
+		key = h.cache.key(c.parent, c.Hash, "commit", h.settings, configSetting(h.opts.configPath))
+		if result, ok := h.cache.get(key); ok {
+			c.Warnings = result.Warnings
+			return result.AffectedResources, nil
+		}
+	}
+
+	if h.root == "" {
+		root, cleanup, err := analyzer.CheckoutBaseWorktree(h.opts.projectRoot, c.Hash)
+		if err != nil {
+			return nil, err
+		}
+		h.root, h.cleanup = root, cleanup
+	} else if err := analyzer.CheckoutCommit(h.root, c.Hash); err != nil {
+		return nil, err
+	}
+
+	opts := h.opts
+	opts.projectRoot = h.root
+	opts.baseBranch = c.parent
+	a, err := opts.build(h.fileCfg)
+	if err != nil {
+		return nil, fmt.Errorf("commit %s: %w", shortHash(c.Hash), err)
+	}
+	impact := a.GetImpact(changedFiles)
+	for _, w := range impact.Warnings {
+		fmt.Fprintf(os.Stderr, "Warning: commit %s: %v\n", shortHash(c.Hash), w)
+	}
+	c.Warnings = impact.Warnings
+
+	if h.cache != nil {
+		if err := h.cache.put(key, impactResult(a, changedFiles, nil, impact)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	return impact.Affected, nil
+}
+
+// close removes the worktree of the history
+func (h *commitHistory) close() {
+	if h.cleanup != nil {
+		h.cleanup()
+		h.cleanup = nil
+	}
+}
+
+// commitChangedFiles returns the files a commit changed from its first parent under the path prefix,
+// relative to the repository root like the files of gitChangedFiles
+func commitChangedFiles(opts *commonOptions, c rangeCommit) ([]string, error) {
+	files, err := analyzer.ChangedFilesBetween(opts.projectRoot, c.parent, c.Hash, opts.diffExcludes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the changes of %s: %w", shortHash(c.Hash), err)
+	}
+	return filterPathPrefix(files, opts.pathPrefix), nil
+}
+
+// WriteText outputs the release notes, which are Markdown in the text format as well
+func (notes releaseNotes) WriteText(w io.Writer) error {
+	return notes.WriteMarkdown(w)
+}
+
+// WriteMarkdown outputs the release notes in Markdown format, a section per resource
+func (notes releaseNotes) WriteMarkdown(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# Release Notes (%s)\n\n", notes.Range)
+	if len(notes.Resources) == 0 && len(notes.OtherCommits) == 0 {
+		fmt.Fprintln(&b, "No changes")
+	}
+	for _, r := range notes.Resources {
+		fmt.Fprintf(&b, "## %s (%s)\n\n", r.Name, r.Type)
+		for _, c := range r.Commits {
+			fmt.Fprintln(&b, releaseNoteLine(c))
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(notes.OtherCommits) > 0 {
+		fmt.Fprintf(&b, "## Other Changes\n\n")
+		for _, c := range notes.OtherCommits {
+			fmt.Fprintln(&b, releaseNoteLine(c))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	// Commits are listed under each resource they affected, their warnings once
+	var warnings []string
+	seen := make(map[string]bool)
+	for _, r := range append(notes.Resources, releaseResource{Commits: notes.OtherCommits}) {
+		for _, c := range r.Commits {
+			if seen[c.Hash] {
+				continue
+			}
+			seen[c.Hash] = true
+			for _, warning := range c.Warnings {
+				warnings = append(warnings, fmt.Sprintf("- %s: %v", shortHash(c.Hash), warning))
+			}
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintf(&b, "## Warnings (notes may be partial)\n\n")
+		for _, warning := range warnings {
+			fmt.Fprintln(&b, warning)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// releaseNoteLine returns the list item of a commit, with the pull request number of merges
+// whose subject does not mention it already
+func releaseNoteLine(c rangeCommit) string {
+	line := "- " + c.Subject
+	if c.PullRequest != 0 && !strings.Contains(c.Subject, fmt.Sprintf("#%d", c.PullRequest)) {
+		line += fmt.Sprintf(" (#%d)", c.PullRequest)
+	}
+	return line + " (" + shortHash(c.Hash) + ")"
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
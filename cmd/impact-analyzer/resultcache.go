@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// defaultResultCacheTTL is how long cached results are reused when no TTL is configured
+const defaultResultCacheTTL = 24 * time.Hour
+
+// resultCache keeps the results of completed analyses in a directory for a time to live, keyed by the
+// base and head commits and the settings of the run, so that the bot modes (the action commenting on pull
+// requests, -review-url) do not recompute identical results for repeated webhook deliveries or re-renders
+type resultCache struct {
+	dir string
+	ttl time.Duration
+}
+
+// newResultCache creates the result cache of a directory, or returns nil if dir is empty
+// ttl is a duration (e.g., 30m, 24h); it defaults to defaultResultCacheTTL
+func newResultCache(dir, ttl string) (*resultCache, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	c := &resultCache{dir: dir, ttl: defaultResultCacheTTL}
+	if ttl != "" {
+		d, err := time.ParseDuration(ttl)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid result cache TTL %q (want a positive duration, e.g., 24h)", ttl)
+		}
+		c.ttl = d
+	}
+	return c, nil
+}
+
+// key returns the cache key of the commits compared by an analysis and the settings of the run
+// (arguments, configuration file), with the analyzer build so that upgrades do not reuse stale results
+func (c *resultCache) key(base, head string, settings ...string) string {
+	info := currentBuildInfo()
+	h := sha256.New()
+	for _, s := range append([]string{info.Version, info.Commit, base, head}, settings...) {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file of a cached result
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".json")
+}
+
+// get returns the result cached for a key, unless it is missing, expired or unreadable
+func (c *resultCache) get(key string) (*output.AnalysisResult, bool) {
+	info, err := os.Stat(c.path(key))
+	if err != nil || time.Since(info.ModTime()) > c.ttl {
+		return nil, false
+	}
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var result output.AnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, false
+	}
+	return &result, true
+}
+
+// put caches the result of a key, and removes the expired results
+func (c *resultCache) put(key string, result *output.AnalysisResult) error {
+	err := writeFileAtomic(c.path(key), 0o644, func(w io.Writer) error {
+		return json.NewEncoder(w).Encode(result)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cache the result: %w", err)
+	}
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err == nil && strings.HasSuffix(e.Name(), ".json") && time.Since(info.ModTime()) > c.ttl {
+			os.Remove(filepath.Join(c.dir, e.Name()))
+		}
+	}
+	return nil
+}
+
+// configSetting returns the content of the configuration file, which the cached results depend on
+func configSetting(configPath string) string {
+	if configPath == "" {
+		return ""
+	}
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// gitResultKey returns the cache key of the git diff of a project against its base branch
+// Returns false when the work tree has uncommitted changes, which the commits do not identify
+func (c *resultCache) gitResultKey(opts *commonOptions, settings ...string) (string, bool) {
+	root := opts.projectRoot
+	if root == "" {
+		root = "."
+	}
+	base, head, clean, err := analyzer.DiffCommits(root, opts.baseBranch)
+	if err != nil || !clean {
+		return "", false
+	}
+	return c.key(base, head, append(settings, configSetting(opts.configPath))...), true
+}
+
+// reviewResultKey returns the cache key of a pull request or merge request, whose commits are known once
+// its changes were read; the checkout, read for the packages the review does not change, is part of the key
+func (c *resultCache) reviewResultKey(opts *commonOptions, provider analyzer.ReviewProvider, settings ...string) (string, bool) {
+	review, ok := provider.(interface{ Revisions() (string, string) })
+	if !ok {
+		return "", false
+	}
+	base, head := review.Revisions()
+	if base == "" || head == "" {
+		return "", false
+	}
+	checkout, ok := c.gitResultKey(opts)
+	if !ok {
+		return "", false
+	}
+	return c.key(base, head, append(settings, checkout, configSetting(opts.configPath))...), true
+}
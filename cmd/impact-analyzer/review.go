@@ -0,0 +1,282 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// reviewPageSize is the number of changed files requested per page of the review APIs
+const reviewPageSize = 100
+
+// reviewAPI sends the requests of a review provider to the REST API of a code review system
+type reviewAPI struct {
+	client  *http.Client
+	baseURL string
+	header  http.Header
+}
+
+// get sends a GET request to the API and returns the response body
+func (r *reviewAPI) get(path string, accept string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, r.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.header {
+		req.Header[k] = v
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// getJSON sends a GET request to the API and decodes the JSON response
+func (r *reviewAPI) getJSON(path string, v any) error {
+	data, err := r.get(path, "")
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("GET %s: %w", path, err)
+	}
+	return nil
+}
+
+// newReviewProvider creates the provider of a pull request or merge request from its web URL:
+// https://github.com/<owner>/<repo>/pull/<n> (GitHub or GitHub Enterprise, authenticated with GITHUB_TOKEN)
+// or https://gitlab.com/<group>/<project>/-/merge_requests/<n> (authenticated with GITLAB_TOKEN)
+// Tokens are only sent to github.com or gitlab.com, or to the host of GITHUB_API_URL or CI_API_V4_URL
+// (the API of the server running the CI job, which issued its token)
+func newReviewProvider(rawURL string) (analyzer.ReviewProvider, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid review URL %q", rawURL)
+	}
+	path := strings.Trim(u.Path, "/")
+	client := &http.Client{Timeout: time.Minute}
+
+	if project, n, ok := strings.Cut(path, "/-/merge_requests/"); ok {
+		number, err := strconv.Atoi(n)
+		if err != nil {
+			return nil, fmt.Errorf("invalid merge request number in %q", rawURL)
+		}
+		api := &reviewAPI{client: client, baseURL: u.Scheme + "://" + u.Host + "/api/v4", header: http.Header{}}
+		if token := os.Getenv("GITLAB_TOKEN"); token != "" && tokenAllowed(api.baseURL, "gitlab.com", os.Getenv("CI_API_V4_URL")) {
+			api.header.Set("PRIVATE-TOKEN", token)
+		}
+		return &gitlabReview{api: api, project: url.PathEscape(project), number: number}, nil
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 4 || parts[2] != "pull" {
+		return nil, fmt.Errorf("unsupported review URL %q (want a GitHub pull request or a GitLab merge request)", rawURL)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid pull request number in %q", rawURL)
+	}
+	// GitHub Enterprise serves the API under /api/v3 of its host, unless GITHUB_API_URL says otherwise
+	envURL := os.Getenv("GITHUB_API_URL")
+	apiURL := envURL
+	if apiURL == "" {
+		apiURL = u.Scheme + "://" + u.Host + "/api/v3"
+		if u.Host == "github.com" {
+			apiURL = "https://api.github.com"
+		}
+	}
+	api := &reviewAPI{client: client, baseURL: strings.TrimSuffix(apiURL, "/"), header: http.Header{}}
+	api.header.Set("Accept", "application/vnd.github+json")
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && tokenAllowed(apiURL, "api.github.com", envURL) {
+		api.header.Set("Authorization", "Bearer "+token)
+	}
+	return &githubReview{api: api, repo: parts[0] + "/" + parts[1], number: number}, nil
+}
+
+// tokenAllowed reports whether a token may be sent to the API at apiURL: the API of the public
+// service (publicHost) or the API set in the environment (envURL), which the token was issued for
+func tokenAllowed(apiURL, publicHost, envURL string) bool {
+	u, err := url.Parse(apiURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := strings.ToLower(u.Host)
+	if host == publicHost {
+		return true
+	}
+	env, err := url.Parse(envURL)
+	return envURL != "" && err == nil && strings.ToLower(env.Host) == host
+}
+
+// githubReview reads the changes of a GitHub pull request
+type githubReview struct {
+	api    *reviewAPI
+	repo   string
+	number int
+	// baseSHA and headSHA are the merge base of the pull request and its head commit, the commits its diff compares
+	baseSHA, headSHA string
+	// renamed maps the paths of renamed files to their paths at the base
+	renamed map[string]string
+}
+
+// ChangedFiles returns the files of the pull request with their patches
+// GitHub leaves out the patch of binary files and of very large diffs, which are then analyzed as a whole
+func (g *githubReview) ChangedFiles() ([]analyzer.FilePatch, error) {
+	var pr struct {
+		Base struct {
+			SHA string `json:"sha"`
+		} `json:"base"`
+		Head struct {
+			SHA string `json:"sha"`
+		} `json:"head"`
+	}
+	if err := g.api.getJSON(fmt.Sprintf("/repos/%s/pulls/%d", g.repo, g.number), &pr); err != nil {
+		return nil, err
+	}
+	// The base of the pull request is the tip of the base branch, which may have moved on since the
+	// branching point: files are read at the merge base, the commit the changes are compared to
+	var compare struct {
+		MergeBaseCommit struct {
+			SHA string `json:"sha"`
+		} `json:"merge_base_commit"`
+	}
+	if err := g.api.getJSON(fmt.Sprintf("/repos/%s/compare/%s...%s", g.repo, pr.Base.SHA, pr.Head.SHA), &compare); err != nil {
+		return nil, err
+	}
+	g.baseSHA, g.headSHA = compare.MergeBaseCommit.SHA, pr.Head.SHA
+
+	var files []analyzer.FilePatch
+	g.renamed = make(map[string]string)
+	for page := 1; ; page++ {
+		var prFiles []struct {
+			Filename         string `json:"filename"`
+			PreviousFilename string `json:"previous_filename"`
+			Status           string `json:"status"`
+			Patch            string `json:"patch"`
+		}
+		path := fmt.Sprintf("/repos/%s/pulls/%d/files?per_page=%d&page=%d", g.repo, g.number, reviewPageSize, page)
+		if err := g.api.getJSON(path, &prFiles); err != nil {
+			return nil, err
+		}
+		for _, f := range prFiles {
+			if f.Status == "renamed" && f.PreviousFilename != "" {
+				g.renamed[f.Filename] = f.PreviousFilename
+			}
+			files = append(files, analyzer.NewFilePatch(f.Filename, f.Status == "added", f.Patch))
+		}
+		if len(prFiles) < reviewPageSize {
+			return files, nil
+		}
+	}
+}
+
+// Revisions returns the merge base and head commits of the pull request, known once ChangedFiles returned
+func (g *githubReview) Revisions() (string, string) {
+	return g.baseSHA, g.headSHA
+}
+
+// FileContentAtBase returns the content of a file at the merge base of the pull request,
+// at its previous path if the pull request renamed it
+func (g *githubReview) FileContentAtBase(path string) ([]byte, error) {
+	if previous, ok := g.renamed[path]; ok {
+		path = previous
+	}
+	return g.api.get(fmt.Sprintf("/repos/%s/contents/%s?ref=%s", g.repo, escapePath(path), g.baseSHA), "application/vnd.github.raw")
+}
+
+// gitlabReview reads the changes of a GitLab merge request
+type gitlabReview struct {
+	api *reviewAPI
+	// project is the URL-encoded path of the project (e.g., group%2Fproject)
+	project string
+	number  int
+	// baseSHA and headSHA are the commits of the target branch and of the merge request compared
+	baseSHA, headSHA string
+	// renamed maps the paths of renamed files to their paths at the base
+	renamed map[string]string
+}
+
+// ChangedFiles returns the files of the merge request with their diffs
+// GitLab leaves out the diff of binary files and of collapsed, very large diffs, which are then analyzed as a whole
+func (g *gitlabReview) ChangedFiles() ([]analyzer.FilePatch, error) {
+	var mr struct {
+		DiffRefs struct {
+			BaseSHA string `json:"base_sha"`
+			HeadSHA string `json:"head_sha"`
+		} `json:"diff_refs"`
+	}
+	if err := g.api.getJSON(fmt.Sprintf("/projects/%s/merge_requests/%d", g.project, g.number), &mr); err != nil {
+		return nil, err
+	}
+	g.baseSHA, g.headSHA = mr.DiffRefs.BaseSHA, mr.DiffRefs.HeadSHA
+
+	var files []analyzer.FilePatch
+	g.renamed = make(map[string]string)
+	for page := 1; ; page++ {
+		var diffs []struct {
+			OldPath     string `json:"old_path"`
+			NewPath     string `json:"new_path"`
+			NewFile     bool   `json:"new_file"`
+			RenamedFile bool   `json:"renamed_file"`
+			DeletedFile bool   `json:"deleted_file"`
+			Diff        string `json:"diff"`
+		}
+		path := fmt.Sprintf("/projects/%s/merge_requests/%d/diffs?per_page=%d&page=%d", g.project, g.number, reviewPageSize, page)
+		if err := g.api.getJSON(path, &diffs); err != nil {
+			return nil, err
+		}
+		for _, d := range diffs {
+			name := d.NewPath
+			if d.DeletedFile {
+				name = d.OldPath
+			}
+			if d.RenamedFile && d.OldPath != d.NewPath {
+				g.renamed[d.NewPath] = d.OldPath
+			}
+			files = append(files, analyzer.NewFilePatch(name, d.NewFile, d.Diff))
+		}
+		if len(diffs) < reviewPageSize {
+			return files, nil
+		}
+	}
+}
+
+// Revisions returns the base and head commits of the merge request, known once ChangedFiles returned
+func (g *gitlabReview) Revisions() (string, string) {
+	return g.baseSHA, g.headSHA
+}
+
+// FileContentAtBase returns the content of a file at the base commit of the merge request,
+// at its previous path if the merge request renamed it
+func (g *gitlabReview) FileContentAtBase(path string) ([]byte, error) {
+	if previous, ok := g.renamed[path]; ok {
+		path = previous
+	}
+	return g.api.get(fmt.Sprintf("/projects/%s/repository/files/%s/raw?ref=%s", g.project, url.PathEscape(path), g.baseSHA), "")
+}
+
+// escapePath escapes each segment of a slash-separated path for a URL
+func escapePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Output versions of the analysis result selected with -output-version
+// A new version is added on incompatible changes; new optional fields keep the version
+const (
+	outputV1 = "v1"
+	outputV2 = "v2"
+)
+
+// resourceListVersion is the schema version of the resource list
+const resourceListVersion = "1"
+
+// schemaVersionOf returns the schema_version reported for an output version
+func schemaVersionOf(outputVersion string) string {
+	return strings.TrimPrefix(outputVersion, "v")
+}
+
+// Schema names (files under schema/ without the version suffix)
+const (
+	analysisResultSchema = "analysis-result"
+	resourceListSchema   = "resource-list"
+)
+
+//go:embed schema/*.json
+var schemaFiles embed.FS
+
+// loadSchema returns the embedded JSON Schema of an output format at the given schema version
+func loadSchema(name, version string) ([]byte, error) {
+	return schemaFiles.ReadFile("schema/" + name + ".v" + version + ".json")
+}
+
+// runSchema runs the schema subcommand, printing the JSON Schema of an output format
+func runSchema(args []string) {
+	fs := flag.NewFlagSet("schema", flag.ExitOnError)
+	outputVersion := fs.String("output-version", outputV1, "Output version of the schema (v1, v2)")
+	fs.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: impact-analyzer schema [-output-version=v1|v2] [%s|%s]\n", analysisResultSchema, resourceListSchema)
+	}
+	fs.Parse(args)
+
+	name := analysisResultSchema
+	if fs.NArg() > 0 {
+		name = fs.Arg(0)
+	}
+	version := schemaVersionOf(*outputVersion)
+	if name == resourceListSchema {
+		version = resourceListVersion
+	}
+	schema, err := loadSchema(name, version)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: unknown schema %q (output version %s)\n", name, *outputVersion)
+		os.Exit(1)
+	}
+	os.Stdout.Write(schema)
+}
+
+// validateOutput checks a JSON output against its embedded schema
+func validateOutput(name, version string, output any) error {
+	raw, err := loadSchema(name, version)
+	if err != nil {
+		return err
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return fmt.Errorf("invalid schema %s: %w", name, err)
+	}
+
+	data, err := json.Marshal(output)
+	if err != nil {
+		return err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+
+	if err := validateValue(schema, schema, value, "$"); err != nil {
+		return fmt.Errorf("output does not match schema %s v%s: %w", name, version, err)
+	}
+	return nil
+}
+
+// validateValue validates a decoded JSON value against a schema
+// Only the keywords used by the embedded schemas are supported:
+// $ref (local), type, enum, required, properties, additionalProperties and items
+func validateValue(root, schema map[string]any, value any, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := resolveRef(root, ref)
+		if err != nil {
+			return err
+		}
+		return validateValue(root, resolved, value, path)
+	}
+
+	if t, ok := schema["type"]; ok && !matchesType(t, value) {
+		return fmt.Errorf("%s: expected %v, got %s", path, t, jsonType(value))
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		found := false
+		for _, e := range enum {
+			found = found || e == value
+		}
+		if !found {
+			return fmt.Errorf("%s: %v is not one of %v", path, value, enum)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				if _, ok := v[r.(string)]; !ok {
+					return fmt.Errorf("%s: missing required property %q", path, r)
+				}
+			}
+		}
+
+		properties, _ := schema["properties"].(map[string]any)
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if propSchema, ok := properties[key].(map[string]any); ok {
+				if err := validateValue(root, propSchema, v[key], path+"."+key); err != nil {
+					return err
+				}
+				continue
+			}
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					return fmt.Errorf("%s: unexpected property %q", path, key)
+				}
+			case map[string]any:
+				if err := validateValue(root, additional, v[key], path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				if err := validateValue(root, items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// resolveRef resolves a local reference such as "#/$defs/resource"
+func resolveRef(root map[string]any, ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("unsupported $ref %q", ref)
+	}
+	current := root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		next, ok := current[part].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("unresolved $ref %q", ref)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// matchesType checks a value against a type keyword (a type name or a list of names)
+func matchesType(t any, value any) bool {
+	switch t := t.(type) {
+	case string:
+		actual := jsonType(value)
+		return actual == t || (t == "number" && actual == "integer")
+	case []any:
+		for _, name := range t {
+			if matchesType(name, value) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonType returns the JSON Schema type name of a decoded JSON value
+func jsonType(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "unknown"
+}
@@ -0,0 +1,161 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// runSparseCheckout runs the sparse-checkout subcommand
+// In a clone without checkout (git clone --no-checkout, possibly with --filter=blob:none), the changes are read
+// from git objects or the review API and only the directories the analysis parses are checked out, according
+// to the graph of a previous analysis written by graph -json
+func runSparseCheckout(args []string) {
+	var (
+		opts      commonOptions
+		graphPath string
+		reviewURL string
+		printDirs bool
+	)
+
+	fs := flag.NewFlagSet("sparse-checkout", flag.ExitOnError)
+	opts.register(fs)
+	fs.StringVar(&graphPath, "graph", "", "Dependency graph of a previous analysis (the output of graph -json, e.g., of the base branch)")
+	fs.StringVar(&reviewURL, "review-url", "", "Read the changed files from the API of a GitHub pull request or GitLab merge request URL instead of git diff")
+	fs.BoolVar(&printDirs, "print", false, "Print the directories to check out instead of checking them out")
+	fs.Parse(args)
+	if graphPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: -graph is required (write it with impact-analyzer graph -json)")
+		os.Exit(1)
+	}
+
+	// The module is not checked out yet, so go.mod cannot tell the project root
+	if opts.projectRoot == "" {
+		opts.projectRoot = "."
+	}
+	graph, err := loadGraph(graphPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if opts.modulePath == "" {
+		opts.modulePath = graph.Module
+	}
+
+	gitRoot, err := analyzer.NewGitClient(opts.projectRoot, opts.baseBranch).GetRootDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The root holds go.mod and the configuration, and the command directory the resource definitions
+	// They are checked out first, as the configuration tells the non-Go inputs of the analysis
+	prefix := repoDir(opts.pathPrefix)
+	dirs := []string{"", prefix, repoDir(prefix, opts.cmdDir)}
+	if opts.configPath != "" {
+		if abs, err := filepath.Abs(opts.configPath); err == nil {
+			if rel, err := filepath.Rel(gitRoot, filepath.Dir(abs)); err == nil {
+				dirs = append(dirs, repoDir(filepath.ToSlash(rel)))
+			}
+		}
+	}
+	checkout := func() {
+		if printDirs {
+			return
+		}
+		if err := analyzer.SparseCheckout(gitRoot, uniqueStrings(dirs)); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: sparse checkout failed: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	checkout()
+	fileCfg := opts.loadConfig()
+	if fileCfg.SqlcConfig != "" {
+		dirs = append(dirs, repoDir(prefix, path.Dir(filepath.ToSlash(fileCfg.SqlcConfig))))
+		checkout()
+	}
+
+	var client analyzer.GitClient
+	if reviewURL != "" {
+		provider, err := newReviewProvider(reviewURL)
+		if err == nil {
+			client, err = analyzer.NewReviewGitClient(opts.projectRoot, opts.baseBranch, opts.diffExcludes(), provider)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		client = analyzer.NewGitClientWithExcludes(opts.projectRoot, opts.baseBranch, opts.diffExcludes())
+	}
+	changedFiles, err := client.GetChangedFiles(opts.baseBranch)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: failed to get changed files: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The analyzer only reads the configured non-Go inputs at this point
+	opts.projectRoot = filepath.Join(gitRoot, filepath.FromSlash(prefix))
+	a, err := opts.newAnalyzer(fileCfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	needed, ok := a.SparseCheckoutDirs(graph, changedFiles)
+	if !ok {
+		fmt.Fprintln(os.Stderr, "Module files changed: every package is affected, checking out the whole tree")
+		dirs = nil
+	} else {
+		dirs = uniqueStrings(append(dirs, needed...))
+		sort.Strings(dirs)
+	}
+
+	if printDirs {
+		if !ok {
+			fmt.Println("/")
+		}
+		for _, dir := range dirs {
+			fmt.Println("/" + dir)
+		}
+		return
+	}
+	if err := analyzer.SparseCheckout(gitRoot, dirs); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: sparse checkout failed: %v\n", err)
+		os.Exit(1)
+	}
+	if ok {
+		fmt.Fprintf(os.Stderr, "Checked out %d directories for %d changed files\n", len(dirs), len(changedFiles))
+	}
+}
+
+// repoDir joins slash-separated directory parts relative to the git root, the root itself being ""
+func repoDir(parts ...string) string {
+	dir := strings.Trim(path.Join(parts...), "/")
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// loadGraph reads a dependency graph written by graph -json
+func loadGraph(path string) (*analyzer.GraphExport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var graph analyzer.GraphExport
+	if err := json.Unmarshal(data, &graph); err != nil {
+		return nil, fmt.Errorf("failed to parse graph %s: %w", path, err)
+	}
+	if graph.Module == "" {
+		return nil, fmt.Errorf("graph %s has no module (want the output of graph -json)", path)
+	}
+	return &graph, nil
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// analyzeSubmodules analyzes the changed submodules holding their own Go module as separate projects,
+// from the old to the new pointer commit, and adds their affected resources to the result
+// The submodules must be checked out at the new commit, and the excludes apply to their files
+// Submodules without a go.mod are part of the project's module and already analyzed with its changed files
+func analyzeSubmodules(result *output.AnalysisResult, opts *commonOptions) error {
+	changes, err := analyzer.ChangedSubmodules(opts.projectRoot, opts.baseBranch)
+	if err != nil {
+		return fmt.Errorf("failed to list changed submodules: %w", err)
+	}
+
+	for _, change := range changes {
+		if change.OldCommit == "" || change.NewCommit == "" {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(change.Dir, "go.mod")); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(change.Dir, opts.cmdDir)); err != nil {
+			continue
+		}
+
+		// The settings of the configuration file belong to the superproject
+		subOpts := *opts
+		subOpts.projectRoot = change.Dir
+		subOpts.modulePath = ""
+		subOpts.pathPrefix = ""
+		subOpts.baseBranch = change.OldCommit
+		subOpts.resources = ""
+		subOpts.diffExclude = ""
+		a, err := subOpts.build(&FileConfig{})
+		if err != nil {
+			return fmt.Errorf("submodule %s: %w", change.Path, err)
+		}
+		changedFiles, err := analyzer.SubmoduleChangedFiles(opts.projectRoot, opts.diffExcludes(), change)
+		if err != nil {
+			return fmt.Errorf("submodule %s: %w", change.Path, err)
+		}
+
+		affected, warnings := a.GetAffectedResourcesWithWarnings(changedFiles)
+		for _, r := range affected {
+			r.Reason = fmt.Sprintf("submodule %s: %s", change.Path, r.Reason)
+			result.AffectedResources = append(result.AffectedResources, r)
+		}
+		result.Warnings = append(result.Warnings, warnings...)
+		result.TotalResources += len(a.GetResources())
+	}
+
+	result.AffectedResources = uniqueAffectedResources(result.AffectedResources)
+	result.Warnings = uniqueWarnings(result.Warnings)
+	analyzer.SortAffectedResources(result.AffectedResources)
+	result.Images = analyzer.ImagesToRebuild(result.AffectedResources)
+	result.Applications = analyzer.ApplicationsToSync(result.AffectedResources)
+	if result.Summary != nil {
+		summary := analyzer.SummarizeAffected(result.AffectedResources, result.TotalResources)
+		summary.ChangedFiles = result.Summary.ChangedFiles
+		summary.ChangedPackages = result.Summary.ChangedPackages
+		summary.ChangedSymbols = result.Summary.ChangedSymbols
+		result.Summary = summary
+	}
+	return nil
+}
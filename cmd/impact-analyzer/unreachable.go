@@ -0,0 +1,42 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// unreachableReport is the report of unreachable packages and orphaned resources
+type unreachableReport struct {
+	*analyzer.UnreachableReport
+}
+
+// WriteText outputs the unreachable report in text format
+func (r unreachableReport) WriteText(w io.Writer) error {
+	var b bytes.Buffer
+	fmt.Fprintln(&b, "=== Unreachable Code ===")
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "Packages not used by any resource (%d):\n", len(r.UnreachablePackages))
+	if len(r.UnreachablePackages) == 0 {
+		fmt.Fprintln(&b, "  (none)")
+	}
+	for _, p := range r.UnreachablePackages {
+		fmt.Fprintf(&b, "  - %s\n", p)
+	}
+	fmt.Fprintln(&b)
+
+	fmt.Fprintf(&b, "Orphaned Resources (%d):\n", len(r.OrphanedResources))
+	if len(r.OrphanedResources) == 0 {
+		fmt.Fprintln(&b, "  (none)")
+	}
+	for _, o := range r.OrphanedResources {
+		fmt.Fprintf(&b, "  [%s] %s\n", o.Type, o.Name)
+		fmt.Fprintf(&b, "    Reason: %s\n", o.Reason)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// releasesURL is the GitHub API endpoint of the analyzer releases
+const releasesURL = "https://api.github.com/repos/laut0104/go-impact-analyzer/releases"
+
+// checksumsAsset is the release asset listing the SHA-256 checksums of the binaries
+const checksumsAsset = "checksums.txt"
+
+// release is the part of a GitHub release used by the update subcommand
+type release struct {
+	TagName string `json:"tag_name"`
+	Assets  []struct {
+		Name string `json:"name"`
+		URL  string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// assetURL returns the download URL of a release asset
+func (r *release) assetURL(name string) (string, bool) {
+	for _, asset := range r.Assets {
+		if asset.Name == name {
+			return asset.URL, true
+		}
+	}
+	return "", false
+}
+
+// binaryAssetName returns the release asset name of the binary for the current platform
+func binaryAssetName() string {
+	name := fmt.Sprintf("impact-analyzer_%s_%s", runtime.GOOS, runtime.GOARCH)
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// runUpdate runs the update subcommand, replacing the running binary with a GitHub release
+func runUpdate(args []string) {
+	fs := flag.NewFlagSet("update", flag.ExitOnError)
+	check := fs.Bool("check", false, "Only report whether a newer release is available")
+	tag := fs.String("version", "", "Release tag to install (default: latest)")
+	fs.Parse(args)
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+
+	rel, err := fetchRelease(client, *tag)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	// The latest release only replaces older versions; an explicit -version may downgrade
+	current := currentBuildInfo().Version
+	if rel.TagName == current || (*tag == "" && !isNewerVersion(rel.TagName, current)) {
+		fmt.Printf("impact-analyzer %s is up to date\n", current)
+		return
+	}
+	if *check {
+		fmt.Printf("impact-analyzer %s is available (current: %s)\n", rel.TagName, current)
+		return
+	}
+
+	if err := installRelease(client, rel); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("Updated impact-analyzer %s -> %s\n", current, rel.TagName)
+}
+
+// isNewerVersion checks if a release tag is a newer semantic version than the current version
+// Development builds (not a semantic version, e.g., "dev") are older than any release
+func isNewerVersion(tag, current string) bool {
+	if !semver.IsValid(current) {
+		return semver.IsValid(tag)
+	}
+	return semver.Compare(tag, current) > 0
+}
+
+// fetchRelease returns the release with the given tag, or the latest release
+func fetchRelease(client *http.Client, tag string) (*release, error) {
+	url := releasesURL + "/latest"
+	if tag != "" {
+		url = releasesURL + "/tags/" + tag
+	}
+	data, err := download(client, url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch release: %w", err)
+	}
+	var rel release
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return nil, fmt.Errorf("failed to parse release: %w", err)
+	}
+	return &rel, nil
+}
+
+// installRelease downloads the binary of a release, verifies its checksum and replaces the running binary
+func installRelease(client *http.Client, rel *release) error {
+	name := binaryAssetName()
+	binaryURL, ok := rel.assetURL(name)
+	if !ok {
+		return fmt.Errorf("release %s has no binary for %s/%s (%s)", rel.TagName, runtime.GOOS, runtime.GOARCH, name)
+	}
+	checksumsURL, ok := rel.assetURL(checksumsAsset)
+	if !ok {
+		return fmt.Errorf("release %s has no %s", rel.TagName, checksumsAsset)
+	}
+
+	checksums, err := download(client, checksumsURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", checksumsAsset, err)
+	}
+	want, err := findChecksum(checksums, name)
+	if err != nil {
+		return err
+	}
+
+	binary, err := download(client, binaryURL)
+	if err != nil {
+		return fmt.Errorf("failed to download %s: %w", name, err)
+	}
+	sum := sha256.Sum256(binary)
+	if got := hex.EncodeToString(sum[:]); got != want {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return fmt.Errorf("failed to locate the running binary: %w", err)
+	}
+	return replaceExecutable(exe, binary)
+}
+
+// replaceExecutable atomically replaces the running binary
+// Windows does not allow replacing a running executable but allows renaming it, so the old binary is
+// moved aside to <exe>.old first (and restored if the new one cannot be written); the next update removes it
+func replaceExecutable(exe string, binary []byte) error {
+	write := func() error {
+		return writeFileAtomic(exe, 0o755, func(w io.Writer) error {
+			_, err := w.Write(binary)
+			return err
+		})
+	}
+	if runtime.GOOS != "windows" {
+		return write()
+	}
+
+	old := exe + ".old"
+	os.Remove(old)
+	if err := os.Rename(exe, old); err != nil {
+		return fmt.Errorf("failed to move the running binary aside: %w", err)
+	}
+	if err := write(); err != nil {
+		os.Rename(old, exe)
+		return err
+	}
+	return nil
+}
+
+// findChecksum returns the SHA-256 checksum of a file from a "<sha256>  <file>" list
+func findChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("no checksum for %s in %s", name, checksumsAsset)
+}
+
+// download returns the body of a GET request; GITHUB_TOKEN is used if set to avoid rate limits
+func download(client *http.Client, url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token := os.Getenv("GITHUB_TOKEN"); token != "" && strings.HasPrefix(url, "https://api.github.com/") {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
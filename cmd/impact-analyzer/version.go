@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/internal/output"
+)
+
+// version is set by release builds (-ldflags "-X main.version=v1.2.3"); otherwise the module version is used
+var version = ""
+
+// currentBuildInfo returns the build information embedded in the binary
+func currentBuildInfo() *output.BuildInfo {
+	info := &output.BuildInfo{
+		Version:   version,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+		Features:  []string{},
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		if info.Version == "" {
+			info.Version = "(devel)"
+		}
+		return info
+	}
+	if info.Version == "" {
+		info.Version = bi.Main.Version
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			info.Commit = s.Value
+		case "vcs.time":
+			info.CommitTime = s.Value
+		case "vcs.modified":
+			info.Modified = s.Value == "true"
+		case "CGO_ENABLED":
+			// Go plugin rule plugins (.so) need cgo
+			if s.Value == "1" {
+				info.Features = append(info.Features, "cgo")
+			}
+		case "-tags":
+			for _, tag := range strings.Split(s.Value, ",") {
+				if tag != "" {
+					info.Features = append(info.Features, "tag:"+tag)
+				}
+			}
+		}
+	}
+	if info.Version == "" {
+		info.Version = "(devel)"
+	}
+	return info
+}
+
+// runVersion runs the version subcommand, printing the build information
+func runVersion(args []string) {
+	fs := flag.NewFlagSet("version", flag.ExitOnError)
+	jsonOutput := fs.Bool("json", false, "Output in JSON format")
+	fs.Parse(args)
+
+	info := currentBuildInfo()
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(info); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	fmt.Printf("impact-analyzer %s\n", info.Version)
+	if info.Commit != "" {
+		commit := info.Commit
+		if info.Modified {
+			commit += " (modified)"
+		}
+		fmt.Printf("  commit:   %s\n", commit)
+	}
+	if info.CommitTime != "" {
+		fmt.Printf("  time:     %s\n", info.CommitTime)
+	}
+	fmt.Printf("  go:       %s\n", info.GoVersion)
+	fmt.Printf("  platform: %s\n", info.Platform)
+	if len(info.Features) > 0 {
+		fmt.Printf("  features: %s\n", strings.Join(info.Features, ", "))
+	}
+}
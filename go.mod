@@ -1,3 +1,11 @@
 module github.com/laut0104/go-impact-analyzer
 
-go 1.23
+go 1.23.0
+
+require (
+	golang.org/x/exp v0.0.0-20250620022241-b7579e27df2b
+	golang.org/x/mod v0.25.0
+	golang.org/x/tools v0.34.0
+)
+
+require golang.org/x/sync v0.15.0 // indirect
@@ -0,0 +1,176 @@
+package output
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// ANSI escape sequences used by the text output
+const (
+	ansiReset   = "\033[0m"
+	ansiBold    = "\033[1m"
+	ansiDim     = "\033[2m"
+	ansiRed     = "\033[31m"
+	ansiGreen   = "\033[32m"
+	ansiYellow  = "\033[33m"
+	ansiBlue    = "\033[34m"
+	ansiMagenta = "\033[35m"
+	ansiCyan    = "\033[36m"
+)
+
+// textStyle controls colors and line width of the text output
+type textStyle struct {
+	color bool
+	// width is the terminal width used to wrap long lines (0: no wrapping)
+	width int
+	msg   Messages
+}
+
+// newTextStyle detects whether the output is a terminal supporting colors
+// Colors are disabled with -no-color, the NO_COLOR environment variable, TERM=dumb or when the output is not a TTY
+// Lines are only wrapped on a TTY, at the width of the terminal or else of the COLUMNS environment variable;
+// files and pipes get unwrapped lines
+func newTextStyle(out io.Writer, noColor bool, msg Messages) textStyle {
+	style := textStyle{msg: msg}
+	f, ok := out.(*os.File)
+	if !ok {
+		return style
+	}
+	if info, err := f.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return style
+	}
+	style.color = !noColor && os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb"
+	style.width = terminalWidth(f)
+	if width, err := strconv.Atoi(os.Getenv("COLUMNS")); style.width <= 0 && err == nil && width > 0 {
+		style.width = width
+	}
+	return style
+}
+
+// paint wraps text in an ANSI color if colors are enabled
+func (s textStyle) paint(code, text string) string {
+	if !s.color || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// header formats a section header
+func (s textStyle) header(text string) string {
+	return s.paint(ansiBold, text)
+}
+
+// severity formats a severity group header
+func (s textStyle) severity(sev analyzer.Severity) string {
+	text := strings.ToUpper(string(sev))
+	switch sev {
+	case analyzer.SeverityCritical:
+		return s.paint(ansiBold+ansiRed, text)
+	case analyzer.SeverityLow:
+		return s.paint(ansiDim, text)
+	default:
+		return s.paint(ansiYellow, text)
+	}
+}
+
+// resourceType formats the type tag of a resource, padded to width characters
+func (s textStyle) resourceType(t analyzer.ResourceType, width int) string {
+	tag := pad("["+string(t)+"]", width)
+	switch t {
+	case analyzer.ResourceTypeAPI:
+		return s.paint(ansiCyan, tag)
+	case analyzer.ResourceTypeJob:
+		return s.paint(ansiMagenta, tag)
+	case analyzer.ResourceTypeWorker:
+		return s.paint(ansiBlue, tag)
+	case analyzer.ResourceTypeFunction:
+		return s.paint(ansiGreen, tag)
+	}
+	return tag
+}
+
+// dim formats secondary information
+func (s textStyle) dim(text string) string {
+	return s.paint(ansiDim, text)
+}
+
+// warn formats a warning marker
+func (s textStyle) warn(text string) string {
+	return s.paint(ansiYellow, text)
+}
+
+// alert formats an error marker
+func (s textStyle) alert(text string) string {
+	return s.paint(ansiRed, text)
+}
+
+// ok formats a success marker
+func (s textStyle) ok(text string) string {
+	return s.paint(ansiGreen, text)
+}
+
+// wrap joins items with sep, starting a new line with the given indent when the line would exceed the width
+func (s textStyle) wrap(prefix string, items []string, sep, indent string) string {
+	if s.width <= 0 {
+		return prefix + strings.Join(items, sep)
+	}
+
+	var lines []string
+	line := prefix
+	for i, item := range items {
+		part := item
+		if i < len(items)-1 {
+			part += sep
+		}
+		if i > 0 && displayWidth(line)+displayWidth(strings.TrimRight(part, " ")) > s.width {
+			lines = append(lines, strings.TrimRight(line, " "))
+			line = indent
+		}
+		line += part
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// displayWidth returns the number of terminal columns of text, ignoring ANSI escape sequences
+// East Asian wide characters (e.g., Japanese messages) take two columns
+func displayWidth(text string) int {
+	n := 0
+	inEscape := false
+	for _, r := range text {
+		switch {
+		case r == '\033':
+			inEscape = true
+		case inEscape:
+			inEscape = r != 'm'
+		case isWide(r):
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// isWide checks if a rune is an East Asian wide or fullwidth character
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) ||
+		(r >= 0x2E80 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) ||
+		(r >= 0xF900 && r <= 0xFAFF) ||
+		(r >= 0xFE30 && r <= 0xFE4F) ||
+		(r >= 0xFF00 && r <= 0xFF60) ||
+		(r >= 0xFFE0 && r <= 0xFFE6)
+}
+
+// pad right-pads text with spaces to width columns
+func pad(text string, width int) string {
+	n := displayWidth(text)
+	if n >= width {
+		return text
+	}
+	return text + strings.Repeat(" ", width-n)
+}
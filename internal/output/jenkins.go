@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// jenkinsWriter writes results as a Java properties file, read by Jenkins pipelines with readProperties
+// (Pipeline Utility Steps) or injected as environment variables by the EnvInject plugin
+type jenkinsWriter struct{}
+
+// newJenkinsWriter creates the Jenkins writer
+func newJenkinsWriter(Options) Writer {
+	return jenkinsWriter{}
+}
+
+// WriteAnalysisResult writes the analysis result as properties
+func (jenkinsWriter) WriteAnalysisResult(w io.Writer, result *AnalysisResult) error {
+	var b bytes.Buffer
+
+	var critical []string
+	for _, r := range result.AffectedResources {
+		if r.Severity == analyzer.SeverityCritical {
+			critical = append(critical, r.Name)
+		}
+	}
+	names := affectedNames(result.AffectedResources)
+	writeProperty(&b, "IMPACT_AFFECTED_RESOURCES", strings.Join(names, ","))
+	writeProperty(&b, "IMPACT_CRITICAL_RESOURCES", strings.Join(critical, ","))
+	writeProperty(&b, "IMPACT_COUNT", fmt.Sprint(len(names)))
+	writeProperty(&b, "IMPACT_HAS_CRITICAL", fmt.Sprint(len(critical) > 0))
+	writeProperty(&b, "IMPACT_TOTAL_RESOURCES", fmt.Sprint(result.TotalResources))
+	writeProperty(&b, "IMPACT_WARNINGS", fmt.Sprint(len(result.Warnings)))
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteResourceList writes the resource list as properties
+func (jenkinsWriter) WriteResourceList(w io.Writer, list *ResourceListResult) error {
+	var b bytes.Buffer
+
+	names := make([]string, 0, len(list.Resources))
+	for _, r := range list.Resources {
+		names = append(names, r.Name)
+	}
+	writeProperty(&b, "IMPACT_RESOURCES", strings.Join(names, ","))
+	writeProperty(&b, "IMPACT_TOTAL_RESOURCES", fmt.Sprint(list.Total))
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeProperty writes a key=value line, escaping the characters with a meaning in properties files
+func writeProperty(b *bytes.Buffer, key, value string) {
+	value = strings.NewReplacer(`\`, `\\`, "\n", `\n`, "\r", `\r`).Replace(value)
+	fmt.Fprintf(b, "%s=%s\n", key, value)
+}
@@ -0,0 +1,31 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// jsonWriter writes results as indented JSON
+type jsonWriter struct{}
+
+// newJSONWriter creates the JSON writer
+func newJSONWriter(Options) Writer {
+	return jsonWriter{}
+}
+
+// WriteAnalysisResult writes the analysis result in JSON format
+func (jsonWriter) WriteAnalysisResult(w io.Writer, result *AnalysisResult) error {
+	return writeJSON(w, result)
+}
+
+// WriteResourceList writes the resource list in JSON format
+func (jsonWriter) WriteResourceList(w io.Writer, list *ResourceListResult) error {
+	return writeJSON(w, list)
+}
+
+// writeJSON encodes a value with indentation
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
@@ -0,0 +1,226 @@
+package output
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// defaultMessages are the English templates of the text output, keyed by message ID
+// Templates use fmt verbs; overrides must keep the verbs of the original template
+var defaultMessages = map[string]string{
+	"result_title":       "=== Impact Analysis Result ===",
+	"changed_files":      "Changed Files:",
+	"changed_symbols":    "Changed Symbols:",
+	"unexported_changes": "(unexported code changed)",
+	"changed_packages":   "Changed Packages:",
+	"infra_changes":      "Infrastructure-as-Code Changes:",
+	"simulated_changes":  "Simulated Changes:",
+	"images":             "Images to Rebuild:",
+	"applications":       "Applications to Sync:",
+	"warnings":           "Warnings (result may be partial):",
+	"breaking_changes":   "Breaking API Changes:",
+	"api_added":          "%s.%s added (%s)",
+	"api_removed":        "%s.%s removed (%s)",
+	"api_changed":        "%s.%s changed: %s -> %s",
+	"api_diff":           "Exported API Changes:",
+	"api_decl_added":     "%s added: %s",
+	"api_decl_removed":   "%s removed: %s",
+	"api_decl_changed":   "%s changed: %s -> %s",
+	"policy_violations":  "Policy Violations:",
+	"import_violations":  "Import Boundary Violations:",
+	"import_violation":   "%s imports %s (%s)",
+	"summary":            "Summary:",
+	"summary_changes":    "%d files, %d packages, %d symbols changed",
+	"summary_affected":   "%d of %d resources affected (%.1f%%)",
+	"summary_max_depth":  "Max dependency depth: %d",
+	"bases":              "Impact by Base:",
+	"base_impact":        "%s: %d files changed, %d resources affected",
+	"patches":            "Impact by Patch:",
+	"affected_resources": "Affected Resources (%d):",
+	"none":               "(none)",
+	"reason":             "Reason:",
+	"schedule":           "Schedule:",
+	"next_run":           "%s (next run %s)",
+	"scheduled_jobs":     "Scheduled Jobs:",
+	"queues":             "Queues:",
+	"worker_queues":      "Worker Queues:",
+	"chain":              "Chain:",
+	"marker_breaking":    "breaking",
+	"incompatible":       "incompatible",
+	"marker_unknown":     "unknown impact",
+	"marker_wire_format": "wire-format change",
+	"timings":            "Timings:",
+	"timings_phases":     "Phases:",
+	"timings_packages":   "Slowest changed packages:",
+	"timings_resources":  "Slowest resource checks:",
+	"resources_title":    "=== Resources ===",
+	"api_services":       "API Services (%d):",
+	"jobs":               "Jobs (%d):",
+	"workers":            "Workers (%d):",
+	"functions":          "Functions (%d):",
+	"package":            "Package:",
+	"total_resources":    "Total: %d resources",
+}
+
+// localeMessages are the translations of the built-in locales; missing IDs fall back to English
+var localeMessages = map[string]map[string]string{
+	"en": {},
+	"ja": {
+		"result_title":       "=== 影響範囲分析結果 ===",
+		"changed_files":      "変更されたファイル:",
+		"changed_symbols":    "変更されたシンボル:",
+		"unexported_changes": "(非公開のコードが変更されました)",
+		"changed_packages":   "変更されたパッケージ:",
+		"infra_changes":      "IaC の変更:",
+		"simulated_changes":  "シミュレートした変更:",
+		"images":             "再ビルドするイメージ:",
+		"applications":       "同期するアプリケーション:",
+		"warnings":           "警告 (結果が不完全な可能性があります):",
+		"breaking_changes":   "破壊的な API 変更:",
+		"api_added":          "%s.%s が追加されました (%s)",
+		"api_removed":        "%s.%s が削除されました (%s)",
+		"api_changed":        "%s.%s が変更されました: %s -> %s",
+		"api_diff":           "公開 API の変更:",
+		"api_decl_added":     "%s が追加されました: %s",
+		"api_decl_removed":   "%s が削除されました: %s",
+		"api_decl_changed":   "%s が変更されました: %s -> %s",
+		"policy_violations":  "ポリシー違反:",
+		"import_violations":  "import 境界違反:",
+		"import_violation":   "%s が %s を import しています (%s)",
+		"summary":            "サマリー:",
+		"summary_changes":    "%d ファイル、%d パッケージ、%d シンボルが変更されました",
+		"summary_affected":   "%d / %d リソースが影響を受けます (%.1f%%)",
+		"summary_max_depth":  "最大依存深さ: %d",
+		"bases":              "ベースブランチ別の影響:",
+		"base_impact":        "%s: %d ファイルが変更され、%d リソースが影響を受けます",
+		"patches":            "パッチ別の影響:",
+		"affected_resources": "影響を受けるリソース (%d):",
+		"none":               "(なし)",
+		"reason":             "理由:",
+		"schedule":           "スケジュール:",
+		"next_run":           "%s (次回実行 %s)",
+		"scheduled_jobs":     "スケジュール実行されるジョブ:",
+		"queues":             "キュー:",
+		"worker_queues":      "ワーカーのキュー:",
+		"chain":              "依存経路:",
+		"marker_breaking":    "破壊的変更",
+		"incompatible":       "互換性なし",
+		"marker_unknown":     "影響不明",
+		"marker_wire_format": "ワイヤーフォーマット変更",
+		"timings":            "処理時間:",
+		"timings_phases":     "フェーズ:",
+		"timings_packages":   "時間のかかった変更パッケージ:",
+		"timings_resources":  "時間のかかったリソースチェック:",
+		"resources_title":    "=== リソース一覧 ===",
+		"api_services":       "API サービス (%d):",
+		"jobs":               "ジョブ (%d):",
+		"workers":            "ワーカー (%d):",
+		"functions":          "関数 (%d):",
+		"package":            "パッケージ:",
+		"total_resources":    "合計: %d リソース",
+	},
+}
+
+// Messages holds the templates of the text output for the selected locale
+type Messages map[string]string
+
+// NewMessages returns the templates of a locale with the given overrides applied
+// An empty locale is English; "auto" detects the locale from LC_ALL, LC_MESSAGES or LANG
+func NewMessages(locale string, overrides map[string]string) (Messages, error) {
+	switch locale {
+	case "":
+		locale = "en"
+	case "auto":
+		locale = detectLocale()
+	}
+	translations, ok := localeMessages[locale]
+	if !ok {
+		return nil, fmt.Errorf("unsupported language %q (want en, ja or auto)", locale)
+	}
+
+	m := make(Messages, len(defaultMessages))
+	for id, text := range defaultMessages {
+		m[id] = text
+	}
+	for id, text := range translations {
+		m[id] = text
+	}
+	for id, text := range overrides {
+		original, ok := defaultMessages[id]
+		if !ok {
+			return nil, fmt.Errorf("unknown message %q", id)
+		}
+		if !reflect.DeepEqual(templateVerbs(text), templateVerbs(original)) {
+			return nil, fmt.Errorf("message %q must keep the fmt verbs of %q", id, original)
+		}
+		m[id] = text
+	}
+	return m, nil
+}
+
+// templateVerbs returns the fmt verb of each argument of a template, by argument index
+// Explicit argument indexes (%[2]s) let translations reorder the arguments
+func templateVerbs(template string) map[int]byte {
+	verbs := make(map[int]byte)
+	arg := 0
+	for i := 0; i < len(template); i++ {
+		if template[i] != '%' {
+			continue
+		}
+		i++
+		for i < len(template) && strings.IndexByte("+-# 0", template[i]) >= 0 {
+			i++
+		}
+		if i < len(template) && template[i] == '[' {
+			end := strings.IndexByte(template[i:], ']')
+			if end < 0 {
+				break
+			}
+			if n, err := strconv.Atoi(template[i+1 : i+end]); err == nil && n > 0 {
+				arg = n - 1
+			}
+			i += end + 1
+		}
+		for i < len(template) && strings.IndexByte("0123456789.", template[i]) >= 0 {
+			i++
+		}
+		if i >= len(template) || template[i] == '%' {
+			continue
+		}
+		verbs[arg] = template[i]
+		arg++
+	}
+	return verbs
+}
+
+// detectLocale returns the built-in locale matching the environment, defaulting to English
+func detectLocale() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		lang, _, _ := strings.Cut(value, "_")
+		lang, _, _ = strings.Cut(lang, ".")
+		if _, ok := localeMessages[lang]; ok {
+			return lang
+		}
+		return "en"
+	}
+	return "en"
+}
+
+// text returns a message, formatted with args if any
+func (m Messages) text(id string, args ...any) string {
+	template, ok := m[id]
+	if !ok {
+		template = defaultMessages[id]
+	}
+	if len(args) == 0 {
+		return template
+	}
+	return fmt.Sprintf(template, args...)
+}
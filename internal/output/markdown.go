@@ -0,0 +1,200 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// markdownWriter writes results as GitHub-flavored Markdown (e.g., for pull request comments)
+type markdownWriter struct {
+	opts Options
+}
+
+// newMarkdownWriter creates the Markdown writer
+func newMarkdownWriter(opts Options) Writer {
+	return markdownWriter{opts: opts}
+}
+
+// heading returns a text output message as a Markdown heading text
+func (m markdownWriter) heading(id string, args ...any) string {
+	return strings.TrimSuffix(strings.Trim(m.opts.Messages.text(id, args...), "= "), ":")
+}
+
+// WriteAnalysisResult writes the analysis result in Markdown format
+func (m markdownWriter) WriteAnalysisResult(w io.Writer, result *AnalysisResult) error {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "## %s\n\n", m.heading("result_title"))
+
+	if s := result.Summary; s != nil {
+		fmt.Fprintf(&b, "%s  \n", m.opts.Messages.text("summary_affected", projectAffected(result), result.TotalResources, s.AffectedPercent))
+		fmt.Fprintf(&b, "%s  \n", m.opts.Messages.text("summary_changes", s.ChangedFiles, s.ChangedPackages, s.ChangedSymbols))
+		fmt.Fprintf(&b, "%s\n\n", m.opts.Messages.text("summary_max_depth", s.MaxDepth))
+	}
+
+	if len(result.Bases) > 0 {
+		fmt.Fprintf(&b, "### %s\n\n", m.heading("bases"))
+		fmt.Fprintln(&b, "| Base | Changed Files | Affected Resources |")
+		fmt.Fprintln(&b, "|------|---------------|--------------------|")
+		for _, base := range result.Bases {
+			var names []string
+			for _, name := range affectedNames(base.AffectedResources) {
+				names = append(names, "`"+name+"`")
+			}
+			fmt.Fprintf(&b, "| `%s` | %d | %s |\n", escapeCell(base.Base), len(base.ChangedFiles), escapeCell(strings.Join(names, ", ")))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Patches) > 0 {
+		fmt.Fprintf(&b, "### %s\n\n", m.heading("patches"))
+		fmt.Fprintln(&b, "| Patch | Changed Files | Affected Resources |")
+		fmt.Fprintln(&b, "|-------|---------------|--------------------|")
+		for _, patch := range result.Patches {
+			var names []string
+			for _, name := range affectedNames(patch.AffectedResources) {
+				names = append(names, "`"+name+"`")
+			}
+			fmt.Fprintf(&b, "| `%s` | %d | %s |\n", escapeCell(patch.Patch), len(patch.ChangedFiles), escapeCell(strings.Join(names, ", ")))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintf(&b, "### %s\n\n", m.heading("affected_resources", len(result.AffectedResources)))
+	if len(result.AffectedResources) == 0 {
+		fmt.Fprintf(&b, "%s\n\n", m.opts.Messages.text("none"))
+	} else {
+		fmt.Fprintln(&b, "| Resource | Type | Severity | Reason |")
+		fmt.Fprintln(&b, "|----------|------|----------|--------|")
+		for _, r := range result.AffectedResources {
+			name := "`" + r.Name + "`"
+			var markers []string
+			if r.Breaking {
+				markers = append(markers, m.opts.Messages.text("marker_breaking"))
+			}
+			if r.UnknownImpact {
+				markers = append(markers, m.opts.Messages.text("marker_unknown"))
+			}
+			if r.WireFormatChange {
+				markers = append(markers, m.opts.Messages.text("marker_wire_format"))
+			}
+			if len(markers) > 0 {
+				name += " (" + strings.Join(markers, ", ") + ")"
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", escapeCell(name), r.Type, r.Severity, escapeCell(r.Reason))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	var warnings []string
+	for _, w := range result.Warnings {
+		warnings = append(warnings, w.Error())
+	}
+	var breaking []string
+	for _, c := range result.BreakingChanges {
+		breaking = append(breaking, breakingChangeText(m.opts.Messages, c))
+	}
+	var policy []string
+	for _, v := range result.PolicyViolations {
+		policy = append(policy, "**"+v.Level+"** "+v.Message)
+	}
+	var imports []string
+	for _, v := range result.ImportViolations {
+		imports = append(imports, m.opts.Messages.text("import_violation", v.Package, v.Import, v.Rule))
+	}
+	var scheduled []string
+	for _, r := range result.AffectedResources {
+		if r.Schedule != "" {
+			scheduled = append(scheduled, "`"+r.Name+"`: "+scheduleText(m.opts.Messages, r))
+		}
+	}
+	m.writeList(&b, m.heading("scheduled_jobs"), scheduled)
+	var queues []string
+	for _, r := range result.AffectedResources {
+		if len(r.Queues) > 0 {
+			queues = append(queues, "`"+r.Name+"`: "+strings.Join(r.Queues, ", "))
+		}
+	}
+	m.writeList(&b, m.heading("worker_queues"), queues)
+	m.writeList(&b, m.heading("warnings"), warnings)
+	m.writeList(&b, m.heading("breaking_changes"), breaking)
+	m.writeAPIChanges(&b, result.APIChanges)
+	m.writeList(&b, m.heading("policy_violations"), policy)
+	m.writeList(&b, m.heading("import_violations"), imports)
+
+	// Inputs of the analysis are collapsed to keep comments short
+	m.writeDetails(&b, m.heading("changed_files"), result.ChangedFiles)
+	m.writeDetails(&b, m.heading("changed_packages"), result.ChangedPackages)
+	m.writeDetails(&b, m.heading("simulated_changes"), result.SimulatedSymbols)
+	m.writeDetails(&b, m.heading("images"), result.Images)
+	m.writeDetails(&b, m.heading("applications"), result.Applications)
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteResourceList writes the resource list in Markdown format
+func (m markdownWriter) WriteResourceList(w io.Writer, list *ResourceListResult) error {
+	var b bytes.Buffer
+
+	fmt.Fprintf(&b, "## %s\n\n", m.heading("resources_title"))
+	fmt.Fprintln(&b, "| Resource | Type | Package | Description |")
+	fmt.Fprintln(&b, "|----------|------|---------|-------------|")
+	for _, r := range list.Resources {
+		fmt.Fprintf(&b, "| `%s` | %s | %s | %s |\n", escapeCell(r.Name), r.Type, escapeCell(r.Package), escapeCell(r.Description))
+	}
+	fmt.Fprintf(&b, "\n%s\n", m.opts.Messages.text("total_resources", list.Total))
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// writeList writes a section with a bullet list, if it has items
+func (m markdownWriter) writeList(b *bytes.Buffer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### %s\n\n", title)
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", item)
+	}
+	fmt.Fprintln(b)
+}
+
+// writeAPIChanges writes the changed exported declarations as a list nested by package, if there are any
+func (m markdownWriter) writeAPIChanges(b *bytes.Buffer, changes []analyzer.APIChange) {
+	if len(changes) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "### %s\n\n", m.heading("api_diff"))
+	pkg := ""
+	for _, c := range changes {
+		if c.Package != pkg {
+			pkg = c.Package
+			fmt.Fprintf(b, "- `%s`\n", pkg)
+		}
+		fmt.Fprintf(b, "  - %s\n", apiChangeText(m.opts.Messages, c))
+	}
+	fmt.Fprintln(b)
+}
+
+// writeDetails writes a collapsed list of code items, if it has items
+func (m markdownWriter) writeDetails(b *bytes.Buffer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "<details><summary>%s (%d)</summary>\n\n", title, len(items))
+	for _, item := range items {
+		fmt.Fprintf(b, "- `%s`\n", item)
+	}
+	fmt.Fprintf(b, "\n</details>\n\n")
+}
+
+// escapeCell escapes characters that would break a Markdown table cell
+func escapeCell(text string) string {
+	return strings.NewReplacer("|", `\|`, "\n", " ").Replace(text)
+}
@@ -0,0 +1,149 @@
+package output
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// AnalysisResult represents the analysis result
+type AnalysisResult struct {
+	SchemaVersion     string                      `json:"schema_version"`
+	BuildInfo         *BuildInfo                  `json:"build_info,omitempty"`
+	ChangedPackages   []string                    `json:"changed_packages,omitempty"`
+	ChangedFiles      []string                    `json:"changed_files,omitempty"`
+	SimulatedSymbols  []string                    `json:"simulated_symbols,omitempty"`
+	InfraChanges      []analyzer.IaCChange        `json:"infra_changes,omitempty"`
+	ChangedSymbols    []analyzer.FileSymbols      `json:"changed_symbols,omitempty"`
+	Images            []string                    `json:"images,omitempty"`
+	Applications      []string                    `json:"applications,omitempty"`
+	ImportViolations  []analyzer.ImportViolation  `json:"import_violations,omitempty"`
+	BreakingChanges   []analyzer.APIChange        `json:"breaking_changes,omitempty"`
+	APIChanges        []analyzer.APIChange        `json:"api_changes,omitempty"`
+	Warnings          []*analyzer.AnalysisError   `json:"warnings,omitempty"`
+	PolicyViolations  []PolicyViolation           `json:"policy_violations,omitempty"`
+	Summary           *analyzer.Summary           `json:"summary,omitempty"`
+	Timings           *analyzer.Timings           `json:"timings,omitempty"`
+	Bases             []BaseImpact                `json:"bases,omitempty"`
+	Patches           []PatchImpact               `json:"patches,omitempty"`
+	AffectedResources []analyzer.AffectedResource `json:"affected_resources"`
+	TotalResources    int                         `json:"total_resources"`
+}
+
+// BaseImpact is the impact of the changes relative to one of several base branches (-bases)
+// The affected resources of the result combine those of all bases
+type BaseImpact struct {
+	Base              string                      `json:"base"`
+	ChangedFiles      []string                    `json:"changed_files"`
+	AffectedResources []analyzer.AffectedResource `json:"affected_resources"`
+}
+
+// PatchImpact is the impact of one patch of a batch analyzed together (-patches), e.g., a pull request
+// of a merge-queue batch. The affected resources of the result are the union of those of all patches
+type PatchImpact struct {
+	Patch             string                      `json:"patch"`
+	ChangedFiles      []string                    `json:"changed_files"`
+	AffectedResources []analyzer.AffectedResource `json:"affected_resources"`
+}
+
+// BuildInfo identifies the analyzer build that produced a result
+type BuildInfo struct {
+	Version    string `json:"version"`
+	Commit     string `json:"commit,omitempty"`
+	CommitTime string `json:"commit_time,omitempty"`
+	// Modified reports uncommitted changes in the build's working tree
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+	// Features are the optional capabilities compiled into the binary (e.g., cgo for Go plugins, build tags)
+	Features []string `json:"features"`
+}
+
+// PolicyViolation is a message produced by a deny or warn rule of the policy
+type PolicyViolation struct {
+	Level   string `json:"level"` // "deny" or "warn"
+	Message string `json:"message"`
+}
+
+// ResourceListResult represents the resource list
+type ResourceListResult struct {
+	SchemaVersion string              `json:"schema_version"`
+	Resources     []analyzer.Resource `json:"resources"`
+	Total         int                 `json:"total"`
+}
+
+// Writer writes the results of the analyzer in an output format
+type Writer interface {
+	WriteAnalysisResult(w io.Writer, result *AnalysisResult) error
+	WriteResourceList(w io.Writer, list *ResourceListResult) error
+}
+
+// Options configure the writers created by New
+type Options struct {
+	// NoColor disables colors in text output
+	NoColor bool
+	// Messages are the templates of the text output (default: English)
+	Messages Messages
+	// SeverityGroups groups the affected resources of the text output under severity headers,
+	// when severities are configured
+	SeverityGroups bool
+}
+
+// Factory creates a writer with the given options
+type Factory func(opts Options) Writer
+
+// registry maps format names to writer factories
+var registry = map[string]Factory{
+	"jenkins":  newJenkinsWriter,
+	"json":     newJSONWriter,
+	"markdown": newMarkdownWriter,
+	"teamcity": newTeamCityWriter,
+	"text":     newTextWriter,
+}
+
+// Register adds an output format selectable by name, replacing a format of the same name
+func Register(name string, factory Factory) {
+	registry[name] = factory
+}
+
+// New creates the writer of a registered output format
+func New(name string, opts Options) (Writer, error) {
+	factory, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (want %s)", name, strings.Join(Formats(), ", "))
+	}
+	return factory(opts), nil
+}
+
+// Formats returns the names of the registered output formats
+func Formats() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// affectedNames returns the names of affected resources
+func affectedNames(resources []analyzer.AffectedResource) []string {
+	names := make([]string, 0, len(resources))
+	for _, r := range resources {
+		names = append(names, r.Name)
+	}
+	return names
+}
+
+// projectAffected returns the number of affected resources of the project, leaving out those of external analyzers
+func projectAffected(result *AnalysisResult) int {
+	n := 0
+	for _, r := range result.AffectedResources {
+		if r.Type != analyzer.ResourceTypeExternal {
+			n++
+		}
+	}
+	return n
+}
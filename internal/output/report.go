@@ -0,0 +1,50 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// Report is the result of an analysis mode or subcommand other than the impact analysis and the resource list
+// (e.g., the usages of deprecated symbols or the dependency graph). The json format encodes it as is
+type Report interface {
+	// WriteText writes the report for the text format
+	WriteText(w io.Writer) error
+}
+
+// MarkdownReport is a report with its own Markdown rendering
+// The markdown format writes the text of other reports in a code block
+type MarkdownReport interface {
+	Report
+	WriteMarkdown(w io.Writer) error
+}
+
+// ReportWriter is implemented by the writers of the formats able to write reports
+// Formats made for the impact analysis only (e.g., CI service messages) leave it out
+type ReportWriter interface {
+	WriteReport(w io.Writer, report Report) error
+}
+
+// WriteReport writes the report in JSON format
+func (jsonWriter) WriteReport(w io.Writer, report Report) error {
+	return writeJSON(w, report)
+}
+
+// WriteReport writes the report in text format
+func (textWriter) WriteReport(w io.Writer, report Report) error {
+	return report.WriteText(w)
+}
+
+// WriteReport writes the report in Markdown format
+func (markdownWriter) WriteReport(w io.Writer, report Report) error {
+	if md, ok := report.(MarkdownReport); ok {
+		return md.WriteMarkdown(w)
+	}
+	var text bytes.Buffer
+	if err := report.WriteText(&text); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintf(w, "```text\n%s```\n", text.Bytes())
+	return err
+}
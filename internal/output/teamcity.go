@@ -0,0 +1,102 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// teamcityWriter writes results as TeamCity service messages, printed to the build log
+// Affected resources are reported as messages (critical ones as build problems) and as build parameters
+type teamcityWriter struct{}
+
+// newTeamCityWriter creates the TeamCity writer
+func newTeamCityWriter(Options) Writer {
+	return teamcityWriter{}
+}
+
+// WriteAnalysisResult writes the analysis result as TeamCity service messages
+func (teamcityWriter) WriteAnalysisResult(w io.Writer, result *AnalysisResult) error {
+	var b bytes.Buffer
+
+	serviceMessage(&b, "blockOpened", "name", "Impact analysis")
+	hasCritical := false
+	for _, r := range result.AffectedResources {
+		text := fmt.Sprintf("%s (%s, %s): %s", r.Name, r.Type, r.Severity, r.Reason)
+		if r.Severity != analyzer.SeverityCritical {
+			serviceMessage(&b, "message", "text", text, "status", "WARNING")
+			continue
+		}
+		hasCritical = true
+		// The identity keeps the problem of a resource the same across builds (at most 60 characters)
+		identity := "impact:" + r.Name
+		if len(identity) > 60 {
+			identity = identity[:60]
+		}
+		serviceMessage(&b, "buildProblem", "description", "Critical resource affected: "+text, "identity", identity)
+	}
+	for _, warning := range result.Warnings {
+		serviceMessage(&b, "message", "text", warning.Error(), "status", "WARNING")
+	}
+
+	names := affectedNames(result.AffectedResources)
+	serviceMessage(&b, "setParameter", "name", "impact.affected_resources", "value", strings.Join(names, ","))
+	serviceMessage(&b, "setParameter", "name", "impact.count", "value", strconv.Itoa(len(names)))
+	serviceMessage(&b, "setParameter", "name", "impact.has_critical", "value", strconv.FormatBool(hasCritical))
+	serviceMessage(&b, "buildStatisticValue", "key", "impact.affectedResources", "value", strconv.Itoa(len(names)))
+	serviceMessage(&b, "blockClosed", "name", "Impact analysis")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteResourceList writes the resource list as TeamCity service messages
+func (teamcityWriter) WriteResourceList(w io.Writer, list *ResourceListResult) error {
+	var b bytes.Buffer
+
+	names := make([]string, 0, len(list.Resources))
+	for _, r := range list.Resources {
+		names = append(names, r.Name)
+	}
+	serviceMessage(&b, "setParameter", "name", "impact.resources", "value", strings.Join(names, ","))
+	serviceMessage(&b, "setParameter", "name", "impact.total_resources", "value", strconv.Itoa(list.Total))
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// serviceMessage writes a TeamCity service message with name-value attributes
+func serviceMessage(b *bytes.Buffer, name string, attrs ...string) {
+	fmt.Fprintf(b, "##teamcity[%s", name)
+	for i := 0; i+1 < len(attrs); i += 2 {
+		fmt.Fprintf(b, " %s='%s'", attrs[i], escapeServiceValue(attrs[i+1]))
+	}
+	fmt.Fprintln(b, "]")
+}
+
+// escapeServiceValue escapes the characters of a service message attribute value with |
+func escapeServiceValue(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		switch r {
+		case '|', '\'', '[', ']':
+			b.WriteRune('|')
+			b.WriteRune(r)
+		case '\n':
+			b.WriteString("|n")
+		case '\r':
+			b.WriteString("|r")
+		default:
+			if r > 0x7f && r <= 0xffff {
+				fmt.Fprintf(&b, "|0x%04x", r)
+			} else {
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd || netbsd || dragonfly)
+
+package output
+
+import "os"
+
+// terminalWidth returns 0 on platforms where the terminal size is not queried; COLUMNS is used instead
+func terminalWidth(f *os.File) int {
+	return 0
+}
@@ -0,0 +1,413 @@
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// textWriter writes results for humans, with colors when writing to a terminal
+type textWriter struct {
+	opts Options
+}
+
+// newTextWriter creates the text writer
+func newTextWriter(opts Options) Writer {
+	return textWriter{opts: opts}
+}
+
+// WriteAnalysisResult writes the analysis result in text format
+func (t textWriter) WriteAnalysisResult(w io.Writer, result *AnalysisResult) error {
+	style := newTextStyle(w, t.opts.NoColor, t.opts.Messages)
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, style.header(style.msg.text("result_title")))
+	fmt.Fprintln(&b)
+
+	if len(result.ChangedFiles) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("changed_files")))
+		for _, f := range result.ChangedFiles {
+			fmt.Fprintf(&b, "  - %s\n", f)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.ChangedSymbols) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("changed_symbols")))
+		for _, f := range result.ChangedSymbols {
+			fmt.Fprintf(&b, "  - %s\n", f.File)
+			symbols := append(append([]string{}, f.Symbols...), f.InterfaceMethods...)
+			if len(symbols) == 0 {
+				symbols = []string{style.dim(style.msg.text("none"))}
+			}
+			fmt.Fprintln(&b, style.wrap("      ", symbols, ", ", "      "))
+			if f.HasUnexportedChanges {
+				fmt.Fprintf(&b, "      %s\n", style.dim(style.msg.text("unexported_changes")))
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.ChangedPackages) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("changed_packages")))
+		for _, p := range result.ChangedPackages {
+			fmt.Fprintf(&b, "  - %s\n", p)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.InfraChanges) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("infra_changes")))
+		for _, c := range result.InfraChanges {
+			if len(c.Resources) > 0 {
+				fmt.Fprintf(&b, "  - %s (%s)\n", c.File, strings.Join(c.Resources, ", "))
+			} else {
+				fmt.Fprintf(&b, "  - %s\n", c.File)
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.SimulatedSymbols) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("simulated_changes")))
+		for _, sym := range result.SimulatedSymbols {
+			fmt.Fprintf(&b, "  - %s\n", sym)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Images) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("images")))
+		for _, image := range result.Images {
+			fmt.Fprintf(&b, "  - %s\n", image)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Applications) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("applications")))
+		for _, app := range result.Applications {
+			fmt.Fprintf(&b, "  - %s\n", app)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Warnings) > 0 {
+		fmt.Fprintln(&b, style.warn(style.msg.text("warnings")))
+		for _, w := range result.Warnings {
+			fmt.Fprintf(&b, "  - %v\n", w)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.BreakingChanges) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("breaking_changes")))
+		for _, c := range result.BreakingChanges {
+			fmt.Fprintf(&b, "  - %s\n", breakingChangeText(style.msg, c))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.APIChanges) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("api_diff")))
+		pkg := ""
+		for _, c := range result.APIChanges {
+			if c.Package != pkg {
+				pkg = c.Package
+				fmt.Fprintf(&b, "  %s\n", pkg)
+			}
+			fmt.Fprintf(&b, "    - %s\n", apiChangeText(style.msg, c))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.PolicyViolations) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("policy_violations")))
+		for _, v := range result.PolicyViolations {
+			level := style.warn("[" + v.Level + "]")
+			if v.Level == "deny" {
+				level = style.alert("[" + v.Level + "]")
+			}
+			fmt.Fprintf(&b, "  - %s %s\n", level, v.Message)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.ImportViolations) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("import_violations")))
+		for _, v := range result.ImportViolations {
+			fmt.Fprintf(&b, "  - %s\n", style.msg.text("import_violation", v.Package, v.Import, v.Rule))
+		}
+		fmt.Fprintln(&b)
+	}
+
+	// Align resource names across severity groups
+	typeWidth := 0
+	for _, r := range result.AffectedResources {
+		typeWidth = max(typeWidth, len(r.Type)+2)
+	}
+
+	if s := result.Summary; s != nil {
+		fmt.Fprintln(&b, style.header(style.msg.text("summary")))
+		fmt.Fprintf(&b, "  %s\n", style.msg.text("summary_changes", s.ChangedFiles, s.ChangedPackages, s.ChangedSymbols))
+		fmt.Fprintf(&b, "  %s\n", style.msg.text("summary_affected", projectAffected(result), result.TotalResources, s.AffectedPercent))
+		for _, t := range []analyzer.ResourceType{analyzer.ResourceTypeAPI, analyzer.ResourceTypeJob, analyzer.ResourceTypeWorker, analyzer.ResourceTypeFunction, analyzer.ResourceTypeExternal} {
+			if n := s.AffectedByType[t]; n > 0 {
+				fmt.Fprintf(&b, "    %s %d\n", style.resourceType(t, typeWidth), n)
+			}
+		}
+		fmt.Fprintf(&b, "  %s\n", style.msg.text("summary_max_depth", s.MaxDepth))
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Bases) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("bases")))
+		for _, base := range result.Bases {
+			fmt.Fprintf(&b, "  %s\n", style.msg.text("base_impact", base.Base, len(base.ChangedFiles), len(base.AffectedResources)))
+			if names := affectedNames(base.AffectedResources); len(names) > 0 {
+				fmt.Fprintf(&b, "    %s\n", strings.Join(names, ", "))
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(result.Patches) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("patches")))
+		for _, patch := range result.Patches {
+			fmt.Fprintf(&b, "  %s\n", style.msg.text("base_impact", patch.Patch, len(patch.ChangedFiles), len(patch.AffectedResources)))
+			if names := affectedNames(patch.AffectedResources); len(names) > 0 {
+				fmt.Fprintf(&b, "    %s\n", strings.Join(names, ", "))
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintln(&b, style.header(style.msg.text("affected_resources", len(result.AffectedResources))))
+	if len(result.AffectedResources) == 0 {
+		fmt.Fprintln(&b, "  "+style.ok(style.msg.text("none")))
+	}
+
+	// Resources are sorted by severity, so print a group header whenever it changes
+	var currentSeverity analyzer.Severity
+	for _, r := range result.AffectedResources {
+		if t.opts.SeverityGroups && r.Severity != currentSeverity {
+			currentSeverity = r.Severity
+			fmt.Fprintf(&b, " %s:\n", style.severity(currentSeverity))
+		}
+		var markers []string
+		if r.Breaking {
+			markers = append(markers, style.alert(style.msg.text("marker_breaking")))
+		}
+		if r.UnknownImpact {
+			markers = append(markers, style.warn(style.msg.text("marker_unknown")))
+		}
+		if r.WireFormatChange {
+			markers = append(markers, style.warn(style.msg.text("marker_wire_format")))
+		}
+		if len(markers) > 0 {
+			fmt.Fprintf(&b, "  %s %s (%s)\n", style.resourceType(r.Type, typeWidth), style.header(r.Name), strings.Join(markers, ", "))
+		} else {
+			fmt.Fprintf(&b, "  %s %s\n", style.resourceType(r.Type, typeWidth), style.header(r.Name))
+		}
+		fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("reason")), r.Reason)
+		if r.Schedule != "" {
+			fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("schedule")), scheduleText(style.msg, r))
+		}
+		if len(r.Queues) > 0 {
+			fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("queues")), strings.Join(r.Queues, ", "))
+		}
+		if len(r.DependencyChain) > 0 {
+			label := style.msg.text("chain")
+			indent := strings.Repeat(" ", 5+displayWidth(label))
+			fmt.Fprintln(&b, style.wrap("    "+style.dim(label)+" ", chainItems(r), " -> ", indent))
+		}
+	}
+
+	if result.Timings != nil {
+		fmt.Fprintln(&b)
+		writeTimings(&b, style, result.Timings)
+	}
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// scheduleText formats the schedule of an affected resource with its next run, if known
+func scheduleText(msg Messages, r analyzer.AffectedResource) string {
+	if r.NextRun == "" {
+		return r.Schedule
+	}
+	return msg.text("next_run", r.Schedule, r.NextRun)
+}
+
+// breakingChangeText describes an incompatible change to an exported symbol, qualified by its package
+func breakingChangeText(msg Messages, c analyzer.APIChange) string {
+	switch c.Kind {
+	case analyzer.APIChangeAdded:
+		return msg.text("api_added", c.Package, c.Symbol, c.After)
+	case analyzer.APIChangeRemoved:
+		return msg.text("api_removed", c.Package, c.Symbol, c.Before)
+	}
+	return msg.text("api_changed", c.Package, c.Symbol, c.Before, c.After)
+}
+
+// apiChangeText describes a change to an exported declaration of a package, marking incompatible changes
+func apiChangeText(msg Messages, c analyzer.APIChange) string {
+	var text string
+	switch c.Kind {
+	case analyzer.APIChangeAdded:
+		text = msg.text("api_decl_added", c.Symbol, c.After)
+	case analyzer.APIChangeRemoved:
+		text = msg.text("api_decl_removed", c.Symbol, c.Before)
+	default:
+		text = msg.text("api_decl_changed", c.Symbol, c.Before, c.After)
+	}
+	if !c.Compatible {
+		text += " (" + msg.text("incompatible") + ")"
+	}
+	return text
+}
+
+// chainItems returns the packages of the dependency chain, each followed by the symbols used by the previous hop
+func chainItems(r analyzer.AffectedResource) []string {
+	items := append([]string(nil), r.DependencyChain...)
+	for _, link := range r.ChainLinks {
+		for i := 1; i < len(items); i++ {
+			if r.DependencyChain[i] == link.To && r.DependencyChain[i-1] == link.From && len(link.Symbols) > 0 {
+				items[i] += " (" + strings.Join(link.Symbols, ", ") + ")"
+				break
+			}
+		}
+	}
+	return items
+}
+
+// maxTimings is the number of slowest packages and resources listed in text output
+const maxTimings = 10
+
+// writeTimings writes the phases and the slowest packages and resources
+func writeTimings(b *bytes.Buffer, style textStyle, timings *analyzer.Timings) {
+	fmt.Fprintln(b, style.header(style.msg.text("timings")))
+	sections := []struct {
+		id      string
+		timings []analyzer.Timing
+	}{
+		{"timings_phases", timings.Phases},
+		{"timings_packages", timings.Packages},
+		{"timings_resources", timings.Resources},
+	}
+	for _, section := range sections {
+		if len(section.timings) == 0 {
+			continue
+		}
+		items := section.timings
+		if section.id != "timings_phases" && len(items) > maxTimings {
+			items = items[:maxTimings]
+		}
+		width := 0
+		for _, t := range items {
+			width = max(width, displayWidth(t.Name))
+		}
+		fmt.Fprintf(b, "  %s\n", style.msg.text(section.id))
+		for _, t := range items {
+			fmt.Fprintf(b, "    %s %s\n", pad(t.Name, width), style.dim(t.Duration.Round(time.Microsecond).String()))
+		}
+	}
+}
+
+// WriteResourceList writes the resource list in text format
+func (t textWriter) WriteResourceList(w io.Writer, list *ResourceListResult) error {
+	style := newTextStyle(w, t.opts.NoColor, t.opts.Messages)
+	resources := list.Resources
+	var b bytes.Buffer
+
+	fmt.Fprintln(&b, style.header(style.msg.text("resources_title")))
+	fmt.Fprintln(&b)
+
+	// Classify by type
+	var apiResources, jobResources, workerResources, functionResources []analyzer.Resource
+
+	for _, r := range resources {
+		switch r.Type {
+		case analyzer.ResourceTypeAPI:
+			apiResources = append(apiResources, r)
+		case analyzer.ResourceTypeJob:
+			jobResources = append(jobResources, r)
+		case analyzer.ResourceTypeWorker:
+			workerResources = append(workerResources, r)
+		case analyzer.ResourceTypeFunction:
+			functionResources = append(functionResources, r)
+		}
+	}
+
+	if len(apiResources) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("api_services", len(apiResources))))
+		nameWidth := resourceNameWidth(apiResources)
+		for _, r := range apiResources {
+			fmt.Fprintf(&b, "  - %s %s\n", pad(r.Name+":", nameWidth+1), r.Description)
+			if r.Package != "" {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("package")), r.Package)
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(jobResources) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("jobs", len(jobResources))))
+		nameWidth := resourceNameWidth(jobResources)
+		for _, r := range jobResources {
+			fmt.Fprintf(&b, "  - %s %s\n", pad(r.Name+":", nameWidth+1), r.Description)
+			if r.Package != "" {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("package")), r.Package)
+			}
+			if r.Schedule != "" {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("schedule")), r.Schedule)
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	if len(workerResources) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("workers", len(workerResources))))
+		nameWidth := resourceNameWidth(workerResources)
+		for _, r := range workerResources {
+			fmt.Fprintf(&b, "  - %s %s\n", pad(r.Name+":", nameWidth+1), r.Description)
+			if r.Package != "" {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("package")), r.Package)
+			}
+			if len(r.Queues) > 0 {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("queues")), strings.Join(r.Queues, ", "))
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+	if len(functionResources) > 0 {
+		fmt.Fprintln(&b, style.header(style.msg.text("functions", len(functionResources))))
+		nameWidth := resourceNameWidth(functionResources)
+		for _, r := range functionResources {
+			fmt.Fprintf(&b, "  - %s %s\n", pad(r.Name+":", nameWidth+1), r.Description)
+			if r.Package != "" {
+				fmt.Fprintf(&b, "    %s %s\n", style.dim(style.msg.text("package")), r.Package)
+			}
+		}
+		fmt.Fprintln(&b)
+	}
+
+	fmt.Fprintln(&b, style.msg.text("total_resources", len(resources)))
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// resourceNameWidth returns the display width of the longest resource name
+func resourceNameWidth(resources []analyzer.Resource) int {
+	width := 0
+	for _, r := range resources {
+		width = max(width, displayWidth(r.Name))
+	}
+	return width
+}
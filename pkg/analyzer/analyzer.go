@@ -0,0 +1,2237 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Config holds the analyzer configuration
+type Config struct {
+	// ModulePath is the Go module path (e.g., "github.com/org/repo")
+	ModulePath string
+	// ProjectRoot is the root directory of the project
+	ProjectRoot string
+	// CmdDir is the directory containing CLI command definitions (default: "cli/cmd")
+	CmdDir string
+	// PathPrefix is removed from file paths when converting to package paths (default: "")
+	PathPrefix string
+	// BaseBranch is the base branch for git diff (e.g., "main", "origin/main")
+	BaseBranch string
+	// ExtractorOptions are options passed to ResourceExtractor
+	ExtractorOptions []ExtractorOption
+	// InfrastructureFiles are files that should be treated as infrastructure files
+	// Changes to these files alone don't affect resources unless the exported symbols they define are used
+	// Example: ["sqlc/db.go", "sqlc/models.go"]
+	InfrastructureFiles []string
+	// InfrastructureDirs are directories whose files are all treated as infrastructure files
+	// Example: ["gen", "internal/mocks"]
+	InfrastructureDirs []string
+	// InfrastructurePatterns are glob patterns matching infrastructure files ("**" matches any number of directories)
+	// Example: ["**/*.pb.go", "**/mock_*.go"]
+	InfrastructurePatterns []string
+	// InfrastructureGeneratedFiles treats files with a "// Code generated ... DO NOT EDIT." header as infrastructure files
+	InfrastructureGeneratedFiles bool
+	// GitClient is the git client for git operations (optional, defaults to exec-based client)
+	GitClient GitClient
+	// GoListClient is the go list client for package listing (optional, defaults to exec-based client)
+	GoListClient GoListClient
+	// FileSystem is the file system abstraction (optional, defaults to os-based implementation)
+	FileSystem FileSystem
+	// SeverityRules assign severities to resources by name or glob pattern (first match wins)
+	// Resources matching no rule get SeverityNormal
+	SeverityRules []SeverityRule
+	// BreakingSeverity is the minimum severity of resources using incompatibly changed symbols
+	// (see MarkBreakingResources; default: severities are kept)
+	BreakingSeverity Severity
+	// FileMappings map non-Go files (e.g., runtime config files) to the resources they affect
+	FileMappings []FileMapping
+	// ForceInclude are rules marking resources as affected whenever files matching their paths change
+	ForceInclude []ForceIncludeRule
+	// IaCPatterns are glob patterns identifying infrastructure-as-code files (default: DefaultIaCPatterns)
+	IaCPatterns []string
+	// SqlcConfig is the path of the sqlc configuration relative to ProjectRoot
+	// (default: auto-detect sqlc.yaml, sqlc.yml or sqlc.json)
+	// When present, changes to SQL queries and generated query files only affect resources calling those queries
+	SqlcConfig string
+	// RegistrationTables describe resources registered as elements of slice or map literals
+	// (e.g., var jobs = []JobDef{{Name: "x", Run: pkg.Run}}) besides the cobra commands
+	RegistrationTables []RegistrationTable
+	// OpenAPISpecs link OpenAPI specs to the packages generated from them (oapi-codegen, ogen)
+	// A change to a spec only affects resources implementing or calling the changed operations
+	OpenAPISpecs []OpenAPISpec
+	// Services declare gRPC methods served and consumed by resources
+	// A change to a server also affects the resources calling its methods over the network
+	Services []ServiceDefinition
+	// Topics declare message-queue publishers and consumers
+	// A change to a publisher also affects the consumers of its topics
+	Topics []TopicDefinition
+	// FeatureFlags declare feature flags read through Go string constants
+	// A change to a flag in its definition files affects the resources reading its constant
+	FeatureFlags []FeatureFlagDefinition
+	// Aliases map extracted command names to canonical resource names (e.g., the names used by deploy systems)
+	// Resources are renamed right after extraction, so other settings and all outputs use the canonical names
+	// Example: {"update-price": "job-update-price"}
+	Aliases map[string]string
+	// Images map resource names to the container images built for them
+	Images map[string]string
+	// ImageTemplate derives the image of resources missing from Images ({name} and {type} are replaced)
+	// Example: "ghcr.io/org/{name}"
+	ImageTemplate string
+	// Applications map resource names to the CD applications deploying them (e.g., Argo CD Applications
+	// or Spinnaker applications), listed as the applications to sync when the resources are affected
+	Applications map[string][]string
+	// LabelRules attach arbitrary metadata to resources by name or glob pattern
+	LabelRules []LabelRule
+	// ImportRules are architecture rules restricting imports between packages
+	ImportRules []ImportRule
+	// CollapsePrefixes show the packages under each prefix as one node in dependency chains and graph exports
+	// Prefixes are package paths, absolute or relative to ModulePath; chains are collapsed after the rule plugins and hooks
+	// Example: ["internal/db"] shows github.com/org/repo/internal/db/... as one node
+	CollapsePrefixes []string
+	// Strict disables the best-effort fallbacks: when the changed symbols of a file cannot be
+	// determined, resources depending on its package are reported with an unknown impact
+	Strict bool
+	// Mode is the precision/speed preset of the analysis (default: balanced); it sets the defaults
+	// of FallbackPolicy and Reflection
+	Mode AnalysisMode
+	// FallbackPolicy selects the impact of a file without line-level diff information (default: all-exported)
+	FallbackPolicy FallbackPolicy
+	// Reflection selects whether symbols reached through reflection widen the impact (default: off)
+	Reflection ReflectionMode
+	// WireFormatOnly restricts struct changes limited to field tags (json, db, ...) to resources
+	// serializing values: encoding/json, database/sql, protobuf or sqlc-generated packages
+	WireFormatOnly bool
+	// ConfidenceDecayHops lowers the confidence of a resource by one level for every that many links of its
+	// dependency chain without a verified symbol usage (default: 0, disabled)
+	// Example: 2 reports a resource reached through 2 unverified imports with medium instead of high confidence
+	ConfidenceDecayHops int
+	// Concurrency limits the number of changed packages resolved in parallel (default: GOMAXPROCS)
+	Concurrency int
+	// IgnoreSymbols are symbols ("pkg/version.Version", "pkg/log.Logger.Debug") and packages ("pkg/telemetry",
+	// "pkg/telemetry/...") whose changes never affect resources
+	IgnoreSymbols []string
+	// TestPackages map resource names to the packages of their tests, absolute or relative to ModulePath
+	// (e.g., {"update-price": ["job/updateprice/...", "e2e/pricing"]}); resources missing from the map
+	// get their package and subpackages with _test.go files
+	TestPackages map[string][]string
+	// Resources limits the impact check to the named resources (default: all resources)
+	// Other resources are skipped before their symbol checks, which speeds up targeted questions
+	Resources []string
+
+	// Logger receives progress and warning messages (default: discarded)
+	Logger *slog.Logger
+	// GraphCacheDir keeps a memory-mapped index of the package listing of go list and of the exported symbols
+	// of parsed files between runs (optional): only the directories with files changed since the indexed commit
+	// are listed again (requires the default GitClient), and unchanged files are not parsed again
+	GraphCacheDir string
+	// Cache shares parsing results with other Analyzers (optional)
+	Cache Cache
+
+	// ExternalAnalyzers report affected resources of non-Go code, merged before the rule plugins
+	ExternalAnalyzers []ExternalAnalyzer
+	// RulePlugins add or remove affected resources after the analysis, before the hooks
+	RulePlugins []RulePlugin
+
+	// Hooks inject custom rules into GetAffectedResources; they run while the Analyzer is locked
+	// and must not call Analyze
+	// FilterChangedFiles is called before the analysis and returns the changed files to analyze
+	FilterChangedFiles func(changedFiles []string) []string
+	// FilterAffectedResources is called after the analysis and returns the affected resources to report
+	// All resources are passed so that custom rules can add resources
+	// (e.g., always include the auth service when security/ changes)
+	FilterAffectedResources func(changedFiles []string, affected []AffectedResource, resources []Resource) []AffectedResource
+	// AnnotateResource is called for each reported resource (e.g., to adjust its severity or labels)
+	AnnotateResource func(resource *AffectedResource)
+}
+
+// FallbackPolicy selects how a changed file is handled when line-level diff information is unavailable
+type FallbackPolicy string
+
+const (
+	// FallbackAllExported treats every exported symbol of the file as changed
+	FallbackAllExported FallbackPolicy = "all-exported"
+	// FallbackWholePackage affects every resource depending on the package, regardless of symbol usage
+	FallbackWholePackage FallbackPolicy = "whole-package"
+	// FallbackNone ignores the file
+	FallbackNone FallbackPolicy = "none"
+)
+
+// IsValid checks if the policy is one of the known policies
+func (p FallbackPolicy) IsValid() bool {
+	return p == FallbackAllExported || p == FallbackWholePackage || p == FallbackNone
+}
+
+// Analyzer analyzes dependencies and identifies affected resources
+// Queries are safe for concurrent use; Analyze blocks them while it rebuilds the state
+type Analyzer struct {
+	// mu guards the state built by Analyze
+	mu             sync.RWMutex
+	config         Config
+	graph          *DependencyGraph
+	extractor      *ResourceExtractor
+	symbolAnalyzer *SymbolAnalyzer
+	diffAnalyzer   *DiffAnalyzer
+	diAnalyzer     *DIAnalyzer
+	sqlc           *sqlcConfig
+	topics         []TopicDefinition
+	featureFlags   []featureFlag
+	resources      []Resource
+	// commands is the command hierarchy the resources were extracted from
+	commands *commandTree
+	// Import-boundary violations found during Analyze
+	importViolations []ImportViolation
+	// Non-fatal errors found during Analyze (e.g., packages that failed to load)
+	warnings []*AnalysisError
+	// Package path -> resource names that depend on it
+	reverseDeps map[string][]string
+	// Package path -> packages importing it with the blank identifier
+	blankImporters map[string][]string
+	// FileSystem for file operations
+	fs FileSystem
+	// Timings of Analyze and of the last impact analysis
+	timings *timingRecorder
+	// index keeps the package listing and parsed symbols in Config.GraphCacheDir
+	index *Index
+	// caseInsensitive is set when the filesystem of the project ignores case (macOS, Windows)
+	caseInsensitive bool
+}
+
+// NewAnalyzer creates a new Analyzer with the given configuration
+func NewAnalyzer(cfg Config) *Analyzer {
+	if cfg.CmdDir == "" {
+		cfg.CmdDir = "cli/cmd"
+	}
+	if cfg.BaseBranch == "" {
+		cfg.BaseBranch = "origin/main"
+	}
+	if cfg.Mode == "" {
+		cfg.Mode = ModeBalanced
+	}
+	cfg.Mode.applyDefaults(&cfg)
+	if cfg.FallbackPolicy == "" {
+		cfg.FallbackPolicy = FallbackAllExported
+	}
+	if cfg.Reflection == "" {
+		cfg.Reflection = ReflectionOff
+	}
+
+	// Set default implementations if not provided
+	if cfg.FileSystem == nil {
+		cfg.FileSystem = NewFileSystem()
+	}
+	if cfg.GitClient == nil {
+		cfg.GitClient = NewGitClient(cfg.ProjectRoot, cfg.BaseBranch)
+	}
+	if cfg.GoListClient == nil {
+		cfg.GoListClient = NewGoListClient()
+	}
+	if cfg.Logger == nil {
+		cfg.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+	cfg.CollapsePrefixes = normalizeCollapsePrefixes(cfg.ModulePath, cfg.CollapsePrefixes)
+	// Changed files are slash-separated as git lists them, on Windows too
+	cfg.PathPrefix = filepath.ToSlash(cfg.PathPrefix)
+
+	// Append FileSystem option to ExtractorOptions
+	extractorOpts := append(cfg.ExtractorOptions, WithFileSystem(cfg.FileSystem))
+
+	symbolAnalyzer := NewSymbolAnalyzerWithFS(cfg.ModulePath, cfg.ProjectRoot, cfg.FileSystem)
+	symbolAnalyzer.cache = cfg.Cache
+
+	return &Analyzer{
+		config:         cfg,
+		graph:          NewDependencyGraphWithClient(cfg.ModulePath, cfg.GoListClient),
+		extractor:      NewResourceExtractor(cfg.ModulePath, extractorOpts...),
+		symbolAnalyzer: symbolAnalyzer,
+		diffAnalyzer:   NewDiffAnalyzerWithClient(cfg.ProjectRoot, cfg.BaseBranch, cfg.GitClient),
+		diAnalyzer:     NewDIAnalyzerWithFS(cfg.ModulePath, cfg.ProjectRoot, cfg.FileSystem),
+		reverseDeps:    make(map[string][]string),
+		fs:             cfg.FileSystem,
+		timings:        newTimingRecorder(),
+	}
+}
+
+// NewAnalyzerSimple creates a new Analyzer with minimal configuration (for backward compatibility)
+func NewAnalyzerSimple(modulePath, projectRoot string) *Analyzer {
+	return NewAnalyzer(Config{
+		ModulePath:  modulePath,
+		ProjectRoot: projectRoot,
+	})
+}
+
+// WithBaseBranch returns an Analyzer sharing the analyzed project that compares changes against another base branch
+// The Config's GitClient is replaced by the default client for that branch; Analyze must have been called
+func (a *Analyzer) WithBaseBranch(baseBranch string) *Analyzer {
+	return a.WithGitClient(baseBranch, NewGitClient(a.config.ProjectRoot, baseBranch))
+}
+
+// WithGitClient returns an Analyzer sharing the analyzed project whose changes are read by another GitClient
+// (e.g., one of NewPatchGitClient), relative to a base branch; Analyze must have been called
+func (a *Analyzer) WithGitClient(baseBranch string, client GitClient) *Analyzer {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	cfg := a.config
+	cfg.BaseBranch = baseBranch
+	cfg.GitClient = client
+
+	return &Analyzer{
+		config:           cfg,
+		graph:            a.graph,
+		extractor:        a.extractor,
+		symbolAnalyzer:   a.symbolAnalyzer,
+		diffAnalyzer:     NewDiffAnalyzerWithClient(cfg.ProjectRoot, baseBranch, cfg.GitClient),
+		diAnalyzer:       a.diAnalyzer,
+		sqlc:             a.sqlc,
+		topics:           a.topics,
+		featureFlags:     a.featureFlags,
+		resources:        a.resources,
+		commands:         a.commands,
+		importViolations: a.importViolations,
+		warnings:         a.warnings,
+		reverseDeps:      a.reverseDeps,
+		blankImporters:   a.blankImporters,
+		fs:               a.fs,
+		timings:          a.timings,
+		index:            a.index,
+		caseInsensitive:  a.caseInsensitive,
+	}
+}
+
+// Analyze analyzes the project and builds resources and dependencies
+func (a *Analyzer) Analyze() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	// Start from a fresh state so that Analyze can be called again (e.g., after files changed)
+	a.graph = NewDependencyGraphWithClient(a.config.ModulePath, a.config.GoListClient)
+	a.reverseDeps = make(map[string][]string)
+	a.timings.startAnalyze()
+	a.openIndex()
+
+	// 1. Extract resources from cli/cmd
+	start := time.Now()
+	cmdDir := filepath.Join(a.config.ProjectRoot, a.config.CmdDir)
+	resources, commands, err := a.extractor.extractCommands(cmdDir)
+	if err != nil {
+		return fmt.Errorf("failed to extract resources: %w", err)
+	}
+	a.resources = resources
+	a.commands = commands
+
+	// Load sqlc configuration for query-level mapping
+	a.sqlc = a.loadSqlcConfig(a.config.SqlcConfig)
+	a.timings.analyzePhase("extract resources", start)
+
+	// 2. Build dependency graph for all packages
+	start = time.Now()
+	packages, err := a.listPackages()
+	if err != nil {
+		return fmt.Errorf("failed to build dependency graph: failed to run go list: %w", err)
+	}
+	a.graph.addPackages(packages)
+	a.addSymlinkedPackages()
+
+	// Record packages go list could not load completely
+	a.warnings = nil
+	loadErrors := a.graph.GetLoadErrors()
+	git, ok := a.config.GitClient.(sparseGitClient)
+	sparse := ok && len(loadErrors) > 0 && git.IsSparseCheckout()
+	for _, pkgPath := range a.graph.GetAllPackages() {
+		if msg, ok := loadErrors[pkgPath]; ok {
+			// Imports left out of a sparse checkout (see SparseCheckoutDirs) are reported, as the checkout
+			// may have missed a package the analysis needs
+			if sparse && a.importsMissingPackage(pkgPath) {
+				msg += " (imports packages left out of the sparse checkout)"
+			}
+			a.warnings = append(a.warnings, &AnalysisError{Kind: ErrPackageLoad, Package: pkgPath, Err: errors.New(msg)})
+		}
+	}
+	a.warnings = append(a.warnings, a.graph.caseCollisions()...)
+	a.caseInsensitive = isCaseInsensitiveDir(a.fs, a.config.ProjectRoot)
+	sort.Slice(a.warnings, func(i, j int) bool {
+		return a.warnings[i].Package < a.warnings[j].Package
+	})
+
+	// Resources of registration tables, impact:resource comments and Lambda functions
+	// (found among the packages of the graph) come after the commands
+	taken := make(map[string]bool)
+	for _, r := range a.resources {
+		taken[r.Name] = true
+	}
+	registered, registrationWarnings := a.extractRegistrations(taken)
+	a.resources = append(a.resources, registered...)
+	a.warnings = append(a.warnings, registrationWarnings...)
+	annotated, annotationWarnings := a.extractAnnotatedResources(taken)
+	a.resources = append(a.resources, annotated...)
+	a.warnings = append(a.warnings, annotationWarnings...)
+	a.resources = append(a.resources, a.extractLambdaFunctions(taken)...)
+	if err := applyAliases(a.resources, a.config.Aliases); err != nil {
+		return fmt.Errorf("failed to apply resource aliases: %w", err)
+	}
+
+	// Workers consume the queues passed to RunWorkerPool
+	a.resolveQueues()
+
+	// Jobs without a schedule in their command may register it on a scheduler
+	for i := range a.resources {
+		if r := &a.resources[i]; r.Type == ResourceTypeJob && r.Schedule == "" && r.Package != "" {
+			r.Schedule = a.findScheduleRegistration(a.getPkgDir(r.Package))
+		}
+	}
+
+	// Assign severity tiers, container images, applications and labels
+	for i := range a.resources {
+		a.resources[i].Labels = matchLabels(a.config.LabelRules, a.resources[i].Name)
+		a.resources[i].Severity = matchSeverity(a.config.SeverityRules, a.resources[i].Name)
+		a.resources[i].Image = resolveImage(a.config.Images, a.config.ImageTemplate, a.resources[i])
+		a.resources[i].Applications = a.config.Applications[a.resources[i].Name]
+	}
+
+	a.timings.analyzePhase("build dependency graph", start)
+
+	// 3. Build reverse dependency map
+	start = time.Now()
+	a.buildReverseDependencies()
+
+	// Track blank imports, whose usage cannot be detected from symbols
+	a.blankImporters = a.buildBlankImporters()
+
+	// Resolve topic names and feature flag keys declared through constants
+	a.topics = a.resolveTopics()
+	a.featureFlags = a.resolveFeatureFlags()
+
+	a.timings.analyzePhase("index dependencies", start)
+
+	// Evaluate import-boundary rules on the same graph
+	start = time.Now()
+	a.importViolations = a.checkImportRules()
+	a.timings.analyzePhase("check import rules", start)
+
+	for _, w := range a.warnings {
+		a.config.Logger.Warn("analysis degraded", "error", w)
+	}
+	a.config.Logger.Info("analyzed project", "root", a.config.ProjectRoot, "resources", len(a.resources), "packages", len(a.graph.GetAllPackages()))
+	return nil
+}
+
+// buildReverseDependencies builds the reverse dependency map
+func (a *Analyzer) buildReverseDependencies() {
+	for _, resource := range a.resources {
+		if resource.Package == "" {
+			continue
+		}
+
+		// Add the package that the resource directly depends on
+		a.reverseDeps[resource.Package] = append(a.reverseDeps[resource.Package], resource.Name)
+
+		// Get all packages that the resource depends on
+		allDeps := a.graph.GetAllDeps(resource.Package)
+		for _, dep := range allDeps {
+			a.reverseDeps[dep] = append(a.reverseDeps[dep], resource.Name)
+		}
+	}
+
+	// Remove duplicates
+	for pkg, resources := range a.reverseDeps {
+		a.reverseDeps[pkg] = uniqueStrings(resources)
+	}
+}
+
+// GetResources returns the extracted resource list
+func (a *Analyzer) GetResources() []Resource {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.resources
+}
+
+// changedSymbolsInfo holds detailed information about changed symbols per package
+type changedSymbolsInfo struct {
+	symbols              []string
+	interfaceMethods     []InterfaceMethodRange
+	hasUnexportedChanges bool
+	// note is appended to the reason of affected resources (e.g., "new file foo.go")
+	note string
+	// sideEffects marks changes to package initialization (init functions or variable initializers),
+	// which affect every importer regardless of symbol usage
+	sideEffects bool
+	// embedded marks interface methods reached through an interface embedding the changed one
+	embedded bool
+	// wireFormat marks struct types whose changes are limited to field tags
+	wireFormat bool
+	// reexported marks constants and variables whose value refers to a changed symbol of another package
+	reexported bool
+}
+
+// GetWarnings returns the non-fatal errors found during Analyze
+func (a *Analyzer) GetWarnings() []*AnalysisError {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.warnings
+}
+
+// reason appends the note to the reason of an affected resource
+func (info changedSymbolsInfo) reason(reason string) string {
+	if info.note == "" {
+		return reason
+	}
+	return reason + " (" + info.note + ")"
+}
+
+// GetAffectedResources identifies resources affected by changed files
+func (a *Analyzer) GetAffectedResources(changedFiles []string) []AffectedResource {
+	affected, _ := a.GetAffectedResourcesWithWarnings(changedFiles)
+	return affected
+}
+
+// GetAffectedResourcesWithWarnings identifies resources affected by changed files
+// It also returns the non-fatal errors (including those of Analyze) that may make the result partial
+func (a *Analyzer) GetAffectedResourcesWithWarnings(changedFiles []string) ([]AffectedResource, []*AnalysisError) {
+	impact := a.GetImpact(changedFiles)
+	return impact.Affected, impact.Warnings
+}
+
+// Impact is the result of the impact analysis of changed files
+type Impact struct {
+	Affected []AffectedResource
+	// Warnings are the non-fatal errors (including those of Analyze) that may make the result partial
+	Warnings []*AnalysisError
+	// ChangedSymbols are the changed symbols of each changed package, with interface methods as "Interface.Method"
+	ChangedSymbols map[string][]string
+	// Summary is computed from the same package changes as the affected resources
+	Summary *Summary
+	// Timings are those of this analysis; GetTimings returns those of Analyze
+	Timings *Timings
+}
+
+// GetImpact identifies resources affected by changed files, with the warnings and the summary of the analysis
+func (a *Analyzer) GetImpact(changedFiles []string) *Impact {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	defer a.saveIndex()
+
+	warnings := &warningCollector{warnings: append([]*AnalysisError(nil), a.warnings...)}
+	affectedMap := make(map[string]*AffectedResource)
+
+	if a.config.FilterChangedFiles != nil {
+		changedFiles = a.config.FilterChangedFiles(changedFiles)
+	}
+
+	timings := newImpactTimings()
+	start := time.Now()
+	changes := a.collectPackageChanges(changedFiles, warnings)
+	a.addFeatureFlagChanges(changedFiles, changes)
+	a.removeIgnoredChanges(changes)
+	timings.phase("resolve changed symbols", start)
+	packages := make([]string, 0, len(changes))
+	for pkgPath := range changes {
+		packages = append(packages, pkgPath)
+	}
+	sort.Strings(packages)
+
+	// Package path -> reasons why its changed symbols could not be determined (strict mode)
+	unknownByPackage := make(map[string][]string)
+	// Packages affecting all their dependent resources -> note explaining why
+	wholePackages := make(map[string]string)
+	// Package path -> changed symbols, passed to rule plugins
+	changedByPackage := make(map[string][]string)
+
+	start = time.Now()
+	for _, pkgPath := range packages {
+		pkgStart := time.Now()
+		pc := changes[pkgPath]
+		if pc.wholePackage != "" {
+			wholePackages[pkgPath] = pc.wholePackage
+		}
+		if len(pc.unknown) > 0 {
+			unknownByPackage[pkgPath] = pc.unknown
+		}
+
+		// Fast mode: any change affects every resource depending on the package
+		if a.config.Mode == ModeFast {
+			if pc.wholePackage == "" && (len(pc.symbolNames()) > 0 || pc.info.sideEffects || pc.info.hasUnexportedChanges) {
+				wholePackages[pkgPath] = strings.TrimPrefix(pc.info.note+", fast mode", ", ")
+			}
+			changedByPackage[pkgPath] = pc.symbolNames()
+			timings.addPackage(pkgPath, pkgStart)
+			continue
+		}
+		a.collectAffectedResources(pkgPath, pc.info, affectedMap, timings)
+		if len(pc.wireSymbols) > 0 {
+			wireInfo := changedSymbolsInfo{symbols: pc.wireSymbols, note: "wire-format change", wireFormat: true}
+			a.collectAffectedResources(pkgPath, wireInfo, affectedMap, timings)
+		}
+		changedByPackage[pkgPath] = pc.symbolNames()
+		timings.addPackage(pkgPath, pkgStart)
+	}
+	timings.phase("check resources", start)
+
+	// Whole-package impact: every resource depending on the package is affected
+	start = time.Now()
+	var fallbackPackages []string
+	for pkgPath := range wholePackages {
+		fallbackPackages = append(fallbackPackages, pkgPath)
+	}
+	sort.Strings(fallbackPackages)
+	for _, pkgPath := range fallbackPackages {
+		for _, name := range a.reverseDeps[pkgPath] {
+			if _, exists := affectedMap[name]; exists {
+				continue
+			}
+			resource := a.getResourceByName(name)
+			if resource == nil {
+				continue
+			}
+			affectedMap[name] = &AffectedResource{
+				Resource:        *resource,
+				Reason:          fmt.Sprintf("depends on %s (%s)", pkgPath, wholePackages[pkgPath]),
+				AffectedPackage: pkgPath,
+				DependencyChain: a.getDependencyChain(resource.Package, pkgPath),
+				Confidence:      ConfidenceMedium,
+			}
+		}
+	}
+
+	// Add resources mapped from changed config files
+	a.addFileMappedResources(changedFiles, affectedMap)
+	a.addForceIncludedResources(changedFiles, affectedMap)
+
+	// Add resources whose command-line flags or definitions changed
+	a.addFlagChangedResources(changedFiles, affectedMap, warnings)
+	a.addDefinitionChangedResources(changedFiles, affectedMap, warnings)
+
+	// Strict mode: resources that may depend on undetermined changes have an unknown impact
+	a.addUnknownImpactResources(unknownByPackage, affectedMap)
+
+	affected := a.finalizeAffectedResources(affectedMap)
+	timings.phase("finalize", start)
+
+	start = time.Now()
+	affected = a.addExternalResources(changedFiles, affected, warnings)
+	timings.phase("external analyzers", start)
+
+	affected = a.finishImpact(changedFiles, changes, changedByPackage, affected, warnings, timings)
+
+	a.config.Logger.Debug("analyzed impact", "changed_files", len(changedFiles), "changed_packages", len(packages), "affected", len(affected))
+	return &Impact{
+		Affected:       affected,
+		Warnings:       warnings.warnings,
+		ChangedSymbols: changedByPackage,
+		Summary:        Summarize(len(changedFiles), changedByPackage, affected, len(a.resources)),
+		Timings:        timings.timings(),
+	}
+}
+
+// finishImpact applies the rule plugins and resource hooks to the affected resources, annotates their chain links
+// and confidence, and collapses them, so that simulated changes are reported like those of a diff
+// It logs the warnings added since Analyze; a.mu must be held
+func (a *Analyzer) finishImpact(changedFiles []string, changes map[string]*packageChanges, changedByPackage map[string][]string, affected []AffectedResource, warnings *warningCollector, timings *impactTimings) []AffectedResource {
+	start := time.Now()
+	affected = a.applyRulePlugins(changedFiles, changedByPackage, affected, warnings)
+	affected = a.applyResourceHooks(changedFiles, affected)
+	timings.phase("rule plugins and hooks", start)
+
+	start = time.Now()
+	if a.config.Mode != ModeFast {
+		a.annotateChains(affected, changes)
+	}
+	a.decayConfidence(affected)
+	timings.phase("chain links", start)
+	affected = a.collapseAffectedResources(affected)
+
+	for _, w := range warnings.warnings[len(a.warnings):] {
+		a.config.Logger.Warn("analysis degraded", "error", w)
+	}
+	return affected
+}
+
+// FileSymbols lists the changed symbols detected in a modified file
+type FileSymbols struct {
+	File                 string   `json:"file"`
+	Package              string   `json:"package"`
+	Symbols              []string `json:"symbols"`
+	InterfaceMethods     []string `json:"interface_methods"` // Interface.Method
+	HasUnexportedChanges bool     `json:"has_unexported_changes"`
+}
+
+// newFileSymbols converts the changed symbols of a file for reporting
+func newFileSymbols(file, pkgPath string, info *ChangedSymbolInfo) FileSymbols {
+	fs := FileSymbols{
+		File:                 file,
+		Package:              pkgPath,
+		Symbols:              append([]string{}, info.Symbols...),
+		InterfaceMethods:     []string{},
+		HasUnexportedChanges: info.HasUnexportedChanges,
+	}
+	for _, m := range info.InterfaceMethods {
+		fs.InterfaceMethods = append(fs.InterfaceMethods, m.InterfaceName+"."+m.MethodName)
+	}
+	sort.Strings(fs.Symbols)
+	sort.Strings(fs.InterfaceMethods)
+	return fs
+}
+
+// GetChangedSymbolsByFile returns the changed symbols detected in each modified Go file
+// Files without a line-level diff (new files, embedded assets, sqlc queries) are not listed
+func (a *Analyzer) GetChangedSymbolsByFile(changedFiles []string) []FileSymbols {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.config.FilterChangedFiles != nil {
+		changedFiles = a.config.FilterChangedFiles(changedFiles)
+	}
+
+	result := []FileSymbols{}
+	for _, pc := range a.collectPackageChanges(changedFiles, nil) {
+		result = append(result, pc.files...)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].File < result[j].File
+	})
+	return result
+}
+
+// PackageSymbols is the changed-symbol information of a package
+type PackageSymbols struct {
+	Package              string   `json:"package"`
+	Symbols              []string `json:"symbols"`
+	InterfaceMethods     []string `json:"interface_methods"` // Interface.Method
+	HasUnexportedChanges bool     `json:"has_unexported_changes"`
+	// WireFormatSymbols are struct types whose changes are limited to field tags
+	WireFormatSymbols []string `json:"wire_format_symbols"`
+	// SideEffects reports changes to package initialization, which affect every importer
+	SideEffects bool `json:"side_effects"`
+	// WholePackage explains why every importer is affected regardless of symbols (empty otherwise)
+	WholePackage string `json:"whole_package,omitempty"`
+}
+
+// GetChangedSymbolsReport returns the changed symbols of each package touched by the changed files
+// It resolves changes the same way as GetAffectedResources but does not trace the dependent resources
+func (a *Analyzer) GetChangedSymbolsReport(changedFiles []string) []PackageSymbols {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.config.FilterChangedFiles != nil {
+		changedFiles = a.config.FilterChangedFiles(changedFiles)
+	}
+
+	report := []PackageSymbols{}
+	for pkgPath, pc := range a.collectPackageChanges(changedFiles, nil) {
+		ps := PackageSymbols{
+			Package:              pkgPath,
+			Symbols:              append([]string{}, pc.info.symbols...),
+			InterfaceMethods:     []string{},
+			HasUnexportedChanges: pc.info.hasUnexportedChanges,
+			WireFormatSymbols:    append([]string{}, pc.wireSymbols...),
+			SideEffects:          pc.info.sideEffects,
+			WholePackage:         pc.wholePackage,
+		}
+		for _, m := range pc.info.interfaceMethods {
+			ps.InterfaceMethods = append(ps.InterfaceMethods, m.InterfaceName+"."+m.MethodName)
+		}
+		sort.Strings(ps.Symbols)
+		sort.Strings(ps.InterfaceMethods)
+		report = append(report, ps)
+	}
+	sort.Slice(report, func(i, j int) bool {
+		return report[i].Package < report[j].Package
+	})
+	return report
+}
+
+// packageChanges describes the changes of a package
+type packageChanges struct {
+	info changedSymbolsInfo
+	// wireSymbols are struct types whose changes are limited to field tags
+	wireSymbols []string
+	// wholePackage explains why every resource depending on the package is affected (empty otherwise)
+	wholePackage string
+	// unknown are the reasons why changed symbols could not be determined (strict mode)
+	unknown []string
+	// files are the changed symbols detected in each modified file of the package
+	files []FileSymbols
+}
+
+// symbolNames returns the changed symbols, with interface methods as "Interface.Method"
+func (pc *packageChanges) symbolNames() []string {
+	names := append(append([]string(nil), pc.info.symbols...), pc.wireSymbols...)
+	for _, m := range pc.info.interfaceMethods {
+		names = append(names, m.InterfaceName+"."+m.MethodName)
+	}
+	return names
+}
+
+// changedFile is a changed file attributed to a package
+type changedFile struct {
+	absPath          string
+	origPath         string
+	isInfrastructure bool
+	// isEmbedding marks a Go file whose //go:embed directive matches a changed asset file
+	isEmbedding bool
+	// sqlcQueries extracts changed sqlc query names from a SQL query file or a generated query file
+	sqlcQueries func(content []byte, lines []int) []string
+	// openAPISpec is the spec of a changed OpenAPI file, whose operations map to the generated interfaces
+	openAPISpec *OpenAPISpec
+	// isAssembly marks a changed assembly (.s) file
+	isAssembly bool
+}
+
+// collectPackageChanges determines the changed symbols of each package touched by the changed files
+func (a *Analyzer) collectPackageChanges(changedFiles []string, warnings *warningCollector) map[string]*packageChanges {
+	// Group changed files by package with absolute paths
+	filesByPackage := make(map[string][]changedFile)
+
+	for _, file := range changedFiles {
+		// Convert to absolute path for symbol extraction
+		absPath := a.toAbsPath(file)
+		origPath := file
+
+		// sqlc query files change only the generated methods of the queries they define
+		if a.sqlc != nil {
+			relPath := a.projectRelPath(file)
+			if sqlcPkg := a.sqlc.packageForQueryFile(relPath); sqlcPkg != nil {
+				pkgPath := a.dirToPackage(sqlcPkg.Out)
+				filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, sqlcQueries: sqlcQueryNamesFromSQL})
+				continue
+			}
+			// Migrations change the queries referencing the tables they alter
+			if sqlcPkgs := a.sqlc.packagesForSchemaFile(relPath); len(sqlcPkgs) > 0 {
+				for _, sqlcPkg := range sqlcPkgs {
+					pkgPath := a.dirToPackage(sqlcPkg.Out)
+					filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, sqlcQueries: a.migrationQueries(sqlcPkg)})
+				}
+				continue
+			}
+			if a.sqlc.isGeneratedQueryFile(relPath) {
+				pkgPath := a.fileToPackage(file)
+				filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, sqlcQueries: sqlcQueryNamesFromGo})
+				continue
+			}
+		}
+
+		// OpenAPI specs change only the generated methods of the operations they alter
+		if specs := a.specsForOpenAPIFile(a.projectRelPath(file)); len(specs) > 0 {
+			for i := range specs {
+				pkgPath := a.dirToPackage(specs[i].Package)
+				filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, openAPISpec: &specs[i]})
+			}
+			continue
+		}
+
+		// Assembly files belong to the package of their directory
+		if strings.HasSuffix(file, ".s") {
+			pkgPath := a.dirToPackage(path.Dir(a.projectRelPath(file)))
+			filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, isAssembly: true})
+			continue
+		}
+
+		pkgPath := a.fileToPackage(file)
+		if pkgPath == "" {
+			// Non-Go files embedded via //go:embed change the embedding package
+			embedPkg, embeddingFiles := a.resolveEmbeddedFile(absPath)
+			for _, embeddingFile := range embeddingFiles {
+				filesByPackage[embedPkg] = append(filesByPackage[embedPkg], changedFile{absPath: embeddingFile, origPath: origPath, isEmbedding: true})
+			}
+			continue
+		}
+		// Check if this is an infrastructure file
+		isInfra := a.isInfrastructureFile(file)
+		filesByPackage[pkgPath] = append(filesByPackage[pkgPath], changedFile{absPath: absPath, origPath: origPath, isInfrastructure: isInfra})
+		// The file also changes the packages importing its directory through a symlink
+		for _, alias := range a.graph.GetAliases(pkgPath) {
+			filesByPackage[alias] = append(filesByPackage[alias], changedFile{absPath: absPath, origPath: origPath, isInfrastructure: isInfra})
+		}
+	}
+
+	packages := make([]string, 0, len(filesByPackage))
+	for pkgPath := range filesByPackage {
+		packages = append(packages, pkgPath)
+	}
+	sort.Strings(packages)
+
+	// Packages are resolved in parallel; warnings are merged in package order
+	results := make([]*packageChanges, len(packages))
+	pkgWarnings := make([]*warningCollector, len(packages))
+	forEachParallel(a.config.Concurrency, len(packages), func(i int) {
+		pkgWarnings[i] = &warningCollector{}
+		results[i] = a.resolvePackageChanges(packages[i], filesByPackage[packages[i]], pkgWarnings[i])
+	})
+
+	changes := make(map[string]*packageChanges, len(packages))
+	for i, pkgPath := range packages {
+		changes[pkgPath] = results[i]
+		if warnings != nil {
+			warnings.warnings = append(warnings.warnings, pkgWarnings[i].warnings...)
+		}
+	}
+	return changes
+}
+
+// resolvePackageChanges determines the changed symbols of a package from its changed files
+func (a *Analyzer) resolvePackageChanges(pkgPath string, files []changedFile, warnings *warningCollector) *packageChanges {
+	pc := &packageChanges{}
+
+	// Assembly and //go:linkname create edges symbol-level analysis cannot see
+	for _, fi := range files {
+		if fi.isAssembly {
+			pc.wholePackage = "assembly or go:linkname"
+			return pc
+		}
+	}
+
+	// Check if all files in this package are infrastructure files
+	allInfrastructure := true
+	hasNonInfraFiles := false
+	for _, fi := range files {
+		if !fi.isInfrastructure {
+			allInfrastructure = false
+			hasNonInfraFiles = true
+			break
+		}
+	}
+
+	// Extract only the symbols that were actually changed (function-level)
+	var changedSymbols []string
+	var changedInterfaceMethods []InterfaceMethodRange
+	hasUnexportedChanges := false
+
+	// Track symbols and interface methods from infrastructure files separately
+	var infraSymbols []string
+	var infraInterfaceMethods []InterfaceMethodRange
+
+	// Files that do not exist on the base branch
+	var newFiles []string
+
+	// Struct types whose changes are limited to field tags
+	var wireFormatTypes []string
+
+	// Whether package initialization changed
+	sideEffects := false
+	checkSideEffects := func(content []byte, lines []int) {
+		if changed, err := a.symbolAnalyzer.HasSideEffectChanges(content, lines); err == nil && changed {
+			sideEffects = true
+		}
+	}
+
+	for _, fi := range files {
+		// sqlc queries map to the Querier (or Queries) methods of the generated package
+		if fi.sqlcQueries != nil {
+			queries := a.getChangedNames(fi.absPath, fi.origPath, fi.sqlcQueries)
+			changedInterfaceMethods = append(changedInterfaceMethods, a.sqlcQueryMethods(a.getPkgDir(pkgPath), queries)...)
+			continue
+		}
+		// OpenAPI operations map to the server and client interfaces of the generated package
+		if fi.openAPISpec != nil {
+			operations := a.getChangedNames(fi.absPath, fi.origPath, openAPIOperationNames)
+			changedInterfaceMethods = append(changedInterfaceMethods, a.openAPIInterfaceMethods(*fi.openAPISpec, operations)...)
+			continue
+		}
+
+		// Embedded assets have no symbol-level diff: everything exported from the embedding file is affected
+		if fi.isEmbedding {
+			symbols, err := a.symbolAnalyzer.ExtractExportedSymbols(fi.absPath)
+			warnings.add(ErrParse, fi.absPath, err)
+			if err == nil {
+				changedSymbols = append(changedSymbols, symbols...)
+			}
+			continue
+		}
+
+		// New files have no base version: every exported symbol is added (the unexported ones can only
+		// be used from other files of the package, which changed to use them)
+		if isNew, err := a.config.GitClient.IsNewFile(fi.origPath); err == nil && isNew {
+			symbols, err := a.symbolAnalyzer.ExtractExportedSymbols(fi.absPath)
+			warnings.add(ErrParse, fi.origPath, err)
+			if content, err := a.fs.ReadFile(fi.absPath); err == nil {
+				checkSideEffects(content, nil)
+			}
+			if err == nil {
+				if fi.isInfrastructure {
+					infraSymbols = append(infraSymbols, symbols...)
+				} else {
+					changedSymbols = append(changedSymbols, symbols...)
+				}
+				newFiles = append(newFiles, filepath.Base(fi.origPath))
+			}
+			continue
+		}
+
+		// Get changed line numbers from git diff (including deleted lines)
+		diffResult, err := a.diffAnalyzer.GetChangedLinesWithDeleted(fi.origPath)
+		warnings.add(ErrGitDiff, fi.origPath, err)
+		if err != nil || (len(diffResult.AddedLines) == 0 && len(diffResult.DeletedLines) == 0) {
+			if a.config.Strict {
+				pc.unknown = append(pc.unknown, "no line-level diff for "+fi.origPath)
+				continue
+			}
+			switch a.config.FallbackPolicy {
+			case FallbackNone:
+				continue
+			case FallbackWholePackage:
+				pc.wholePackage = "no line-level diff"
+				continue
+			}
+			// Fallback: if we can't get diff info, use all exported symbols
+			symbols, err := a.symbolAnalyzer.ExtractExportedSymbols(fi.absPath)
+			warnings.add(ErrParse, fi.origPath, err)
+			if content, err := a.fs.ReadFile(fi.absPath); err == nil {
+				checkSideEffects(content, nil)
+			}
+			if err == nil {
+				if fi.isInfrastructure {
+					infraSymbols = append(infraSymbols, symbols...)
+				} else {
+					changedSymbols = append(changedSymbols, symbols...)
+				}
+			}
+			continue
+		}
+
+		// Tag-only struct changes are kept apart from other type changes
+		tagOnly := make(map[string]bool)
+		if len(diffResult.AddedLines) > 0 && len(diffResult.DeletedLines) > 0 && !fi.isInfrastructure {
+			oldContent, errOld := a.config.GitClient.GetFileContentAtBase(fi.origPath)
+			newContent, errNew := a.fs.ReadFile(fi.absPath)
+			if errOld == nil && errNew == nil {
+				types, _ := a.symbolAnalyzer.FindTagOnlyChanges(oldContent, newContent)
+				for _, t := range types {
+					tagOnly[t] = true
+				}
+			}
+		}
+		splitWireFormat := func(symbols []string) []string {
+			var rest []string
+			for _, sym := range symbols {
+				if tagOnly[sym] {
+					wireFormatTypes = append(wireFormatTypes, sym)
+				} else {
+					rest = append(rest, sym)
+				}
+			}
+			return rest
+		}
+
+		// Get symbols from added/modified lines in the current file
+		if len(diffResult.AddedLines) > 0 {
+			if content, err := a.fs.ReadFile(fi.absPath); err == nil {
+				checkSideEffects(content, diffResult.AddedLines)
+			}
+			symbolInfo, err := a.symbolAnalyzer.GetChangedSymbolsDetailed(fi.absPath, diffResult.AddedLines)
+			if err != nil {
+				warnings.add(ErrParse, fi.origPath, err)
+				if a.config.Strict {
+					pc.unknown = append(pc.unknown, "cannot resolve symbols of "+fi.origPath)
+					continue
+				}
+				// Fallback to all symbols on error
+				allSymbols, _ := a.symbolAnalyzer.ExtractExportedSymbols(fi.absPath)
+				if fi.isInfrastructure {
+					infraSymbols = append(infraSymbols, allSymbols...)
+				} else {
+					changedSymbols = append(changedSymbols, allSymbols...)
+				}
+			} else {
+				pc.files = append(pc.files, newFileSymbols(fi.origPath, pkgPath, symbolInfo))
+				if fi.isInfrastructure {
+					infraSymbols = append(infraSymbols, symbolInfo.Symbols...)
+					// Keep interface methods so that e.g. a changed sqlc Querier method
+					// only affects resources calling that method
+					infraInterfaceMethods = append(infraInterfaceMethods, symbolInfo.InterfaceMethods...)
+				} else {
+					changedSymbols = append(changedSymbols, splitWireFormat(symbolInfo.Symbols)...)
+					changedInterfaceMethods = append(changedInterfaceMethods, symbolInfo.InterfaceMethods...)
+					if symbolInfo.HasUnexportedChanges {
+						hasUnexportedChanges = true
+					}
+				}
+			}
+		}
+
+		// Get symbols from deleted lines by parsing the base branch version
+		if len(diffResult.DeletedLines) > 0 {
+			oldContent, err := a.config.GitClient.GetFileContentAtBase(fi.origPath)
+			warnings.add(ErrGitDiff, fi.origPath, err)
+			if err == nil && len(oldContent) > 0 {
+				checkSideEffects(oldContent, diffResult.DeletedLines)
+				deletedSymbols, err := a.symbolAnalyzer.GetDeletedSymbols(oldContent, diffResult.DeletedLines)
+				warnings.add(ErrParse, fi.origPath, err)
+				if err == nil {
+					if fi.isInfrastructure {
+						infraSymbols = append(infraSymbols, deletedSymbols...)
+					} else {
+						changedSymbols = append(changedSymbols, splitWireFormat(deletedSymbols)...)
+					}
+				}
+			}
+		}
+	}
+
+	// If all files are infrastructure files and no non-infra files changed,
+	// we need to find which resources actually use the changed symbols from infra files
+	if allInfrastructure && !hasNonInfraFiles && (len(infraSymbols) > 0 || len(infraInterfaceMethods) > 0) {
+		// Use infra symbols for checking but with more strict symbol-level matching
+		changedSymbols = infraSymbols
+		changedInterfaceMethods = infraInterfaceMethods
+	}
+
+	// Remove duplicates from changedSymbols
+	changedSymbols = uniqueStrings(changedSymbols)
+	changedInterfaceMethods = uniqueInterfaceMethods(changedInterfaceMethods)
+
+	// Remove interface names from changedSymbols if we have specific method info
+	// This prevents false positives where a resource uses the interface type but not the changed methods
+	if len(changedInterfaceMethods) > 0 {
+		interfaceNames := make(map[string]bool)
+		for _, m := range changedInterfaceMethods {
+			interfaceNames[m.InterfaceName] = true
+		}
+		var filteredSymbols []string
+		for _, sym := range changedSymbols {
+			if !interfaceNames[sym] {
+				filteredSymbols = append(filteredSymbols, sym)
+			}
+		}
+		changedSymbols = filteredSymbols
+	}
+
+	info := changedSymbolsInfo{
+		symbols:              changedSymbols,
+		interfaceMethods:     changedInterfaceMethods,
+		hasUnexportedChanges: hasUnexportedChanges,
+		sideEffects:          sideEffects,
+	}
+	if len(newFiles) > 0 {
+		info.note = "new file " + strings.Join(newFiles, ", ")
+	}
+	if sideEffects {
+		info.note = strings.TrimPrefix(info.note+", package initialization changed", ", ")
+	}
+	pc.info = info
+
+	// Packages whose changes reach symbols are read for assembly files and //go:linkname directives
+	// only now, which spares the packages with changes limited to comments or formatting
+	changed := len(changedSymbols) > 0 || len(changedInterfaceMethods) > 0 || hasUnexportedChanges || sideEffects || len(wireFormatTypes) > 0
+	if changed && a.symbolAnalyzer.HasAssemblyOrLinkname(a.getPkgDir(pkgPath)) {
+		return &packageChanges{wholePackage: "assembly or go:linkname"}
+	}
+
+	// Tag-only struct changes are reported as wire-format changes, unless the type changed otherwise
+	for _, sym := range uniqueStrings(wireFormatTypes) {
+		if !contains(changedSymbols, sym) {
+			pc.wireSymbols = append(pc.wireSymbols, sym)
+		}
+	}
+
+	return pc
+}
+
+// applyResourceHooks runs the FilterAffectedResources and AnnotateResource hooks
+func (a *Analyzer) applyResourceHooks(changedFiles []string, affected []AffectedResource) []AffectedResource {
+	if a.config.FilterAffectedResources == nil && a.config.AnnotateResource == nil {
+		return affected
+	}
+
+	if a.config.FilterAffectedResources != nil {
+		resources := append([]Resource(nil), a.resources...)
+		affected = a.config.FilterAffectedResources(changedFiles, affected, resources)
+	}
+	if a.config.AnnotateResource != nil {
+		for i := range affected {
+			a.config.AnnotateResource(&affected[i])
+		}
+	}
+
+	// Hooks may add resources or change severities
+	SortAffectedResources(affected)
+	return affected
+}
+
+// addUnknownImpactResources marks every resource depending on a package whose changes could not be determined
+func (a *Analyzer) addUnknownImpactResources(unknownByPackage map[string][]string, affectedMap map[string]*AffectedResource) {
+	packages := make([]string, 0, len(unknownByPackage))
+	for pkgPath := range unknownByPackage {
+		packages = append(packages, pkgPath)
+	}
+	sort.Strings(packages)
+
+	for _, pkgPath := range packages {
+		reasons := uniqueStrings(unknownByPackage[pkgPath])
+		for _, name := range a.reverseDeps[pkgPath] {
+			if _, exists := affectedMap[name]; exists {
+				continue
+			}
+			resource := a.getResourceByName(name)
+			if resource == nil {
+				continue
+			}
+			affectedMap[name] = &AffectedResource{
+				Resource:        *resource,
+				Reason:          fmt.Sprintf("unknown impact: %s", strings.Join(reasons, ", ")),
+				AffectedPackage: pkgPath,
+				DependencyChain: a.getDependencyChain(resource.Package, pkgPath),
+				UnknownImpact:   true,
+				Confidence:      ConfidenceLow,
+			}
+		}
+	}
+}
+
+// finalizeAffectedResources applies the network topology and returns the affected resources in sorted order
+func (a *Analyzer) finalizeAffectedResources(affectedMap map[string]*AffectedResource) []AffectedResource {
+	// Add resources calling affected services over the network
+	a.addNetworkConsumers(affectedMap)
+
+	now := time.Now()
+	result := make([]AffectedResource, 0, len(affectedMap))
+	for _, r := range affectedMap {
+		if r.Confidence == "" {
+			r.Confidence = ConfidenceHigh
+		}
+		if r.Schedule != "" {
+			if next, err := NextRun(r.Schedule, now); err == nil {
+				r.NextRun = next.Format(time.RFC3339)
+			}
+		}
+		r.TestsToRun = a.testsToRun(r.Resource)
+		result = append(result, *r)
+	}
+	SortAffectedResources(result)
+	return result
+}
+
+// SortAffectedResources sorts resources by severity (most critical first), then by name
+func SortAffectedResources(resources []AffectedResource) {
+	sort.SliceStable(resources, func(i, j int) bool {
+		ri, rj := resources[i].Severity.Rank(), resources[j].Severity.Rank()
+		if ri != rj {
+			return ri > rj
+		}
+		return resources[i].Name < resources[j].Name
+	})
+}
+
+// collectAffectedResources adds resources affected by the changed symbols of a package to affectedMap
+func (a *Analyzer) collectAffectedResources(pkgPath string, info changedSymbolsInfo, affectedMap map[string]*AffectedResource, timings *impactTimings) {
+	// Get resources that depend on this package
+	resourceNames := a.reverseDeps[pkgPath]
+	for _, name := range resourceNames {
+		if _, exists := affectedMap[name]; exists {
+			continue
+		}
+
+		resource := a.getResourceByName(name)
+		if resource == nil {
+			continue
+		}
+
+		// Blank-imported packages are used for their side effects, so any change affects the importers
+		if a.isBlankImportedBy(resource, pkgPath) {
+			affectedMap[name] = &AffectedResource{
+				Resource:        *resource,
+				Reason:          info.reason(fmt.Sprintf("depends on %s (blank import)", pkgPath)),
+				AffectedPackage: pkgPath,
+				DependencyChain: a.getDependencyChain(resource.Package, pkgPath),
+			}
+			continue
+		}
+
+		// Check if the resource actually uses the changed symbols or methods
+		// Changes to package initialization run in every importer
+		// Wire-format changes only affect resources serializing values, whichever way they reach the symbols
+		start := time.Now()
+		if info.wireFormat && a.config.WireFormatOnly && !a.isResourceSerializing(resource, pkgPath) {
+			timings.addResource(name, start)
+			continue
+		}
+		isAffected := info.sideEffects || a.isResourceAffectedBySymbols(resource, pkgPath, info)
+		timings.addResource(name, start)
+		if isAffected {
+			affectedMap[name] = &AffectedResource{
+				Resource:         *resource,
+				Reason:           info.reason(fmt.Sprintf("depends on %s", pkgPath)),
+				AffectedPackage:  pkgPath,
+				DependencyChain:  a.getDependencyChain(resource.Package, pkgPath),
+				WireFormatChange: info.wireFormat,
+			}
+			continue
+		}
+
+		// Symbols may also be reached through reflection (method/field lookup by name, encoding/json)
+		start = time.Now()
+		affectedByReflection := a.isResourceAffectedByReflection(resource, pkgPath, info)
+		timings.addResource(name, start)
+		if affectedByReflection {
+			affectedMap[name] = &AffectedResource{
+				Resource:         *resource,
+				Reason:           info.reason(fmt.Sprintf("depends on %s (reflection)", pkgPath)),
+				AffectedPackage:  pkgPath,
+				DependencyChain:  a.getDependencyChain(resource.Package, pkgPath),
+				WireFormatChange: info.wireFormat,
+				Confidence:       ConfidenceMedium,
+			}
+		}
+	}
+
+	// Find packages that propagate the interface method changes (wrapper packages)
+	// These packages call the changed interface methods and may re-expose them
+	if len(info.interfaceMethods) > 0 {
+		propagatingPkgs := a.findPackagesThatCallInterfaceMethods(pkgPath, info.interfaceMethods)
+		for _, propPkgPath := range propagatingPkgs {
+			// Get resources that depend on the propagating package
+			propResourceNames := a.reverseDeps[propPkgPath]
+			for _, name := range propResourceNames {
+				if _, exists := affectedMap[name]; exists {
+					continue
+				}
+
+				resource := a.getResourceByName(name)
+				if resource == nil {
+					continue
+				}
+
+				// Check if the resource actually calls the changed methods
+				// (not just any method from the propagating package)
+				resourcePkgDir := a.getPkgDir(resource.Package)
+				if resourcePkgDir == "" {
+					continue
+				}
+
+				// Check if resource calls the same method names that were changed
+				start := time.Now()
+				callsChangedMethods, _ := a.symbolAnalyzer.CheckMethodCallUsage(resourcePkgDir, propPkgPath, info.interfaceMethods)
+				timings.addResource(name, start)
+				if callsChangedMethods {
+					affectedMap[name] = &AffectedResource{
+						Resource:        *resource,
+						Reason:          info.reason(fmt.Sprintf("depends on %s (via %s)", pkgPath, propPkgPath)),
+						AffectedPackage: pkgPath,
+						DependencyChain: a.getDependencyChain(resource.Package, propPkgPath),
+					}
+				}
+			}
+		}
+	}
+
+	// Interfaces of other packages embedding the changed interfaces expose the changed methods too
+	if len(info.interfaceMethods) > 0 && !info.embedded {
+		embedding := a.findEmbeddingInterfaceMethods(pkgPath, info.interfaceMethods)
+		embeddingPkgs := make([]string, 0, len(embedding))
+		for embeddingPkg := range embedding {
+			embeddingPkgs = append(embeddingPkgs, embeddingPkg)
+		}
+		sort.Strings(embeddingPkgs)
+		for _, embeddingPkg := range embeddingPkgs {
+			a.collectAffectedResources(embeddingPkg, changedSymbolsInfo{
+				interfaceMethods: embedding[embeddingPkg],
+				note:             "embeds an interface of " + pkgPath,
+				embedded:         true,
+			}, affectedMap, timings)
+		}
+	}
+
+	// Constants and variables of other packages re-exporting the changed values change with them
+	if len(info.symbols) > 0 && !info.reexported {
+		reexported := a.findReexportedValues(pkgPath, info.symbols)
+		reexportingPkgs := make([]string, 0, len(reexported))
+		for reexportingPkg := range reexported {
+			reexportingPkgs = append(reexportingPkgs, reexportingPkg)
+		}
+		sort.Strings(reexportingPkgs)
+		for _, reexportingPkg := range reexportingPkgs {
+			a.collectAffectedResources(reexportingPkg, changedSymbolsInfo{
+				symbols:    reexported[reexportingPkg],
+				note:       "re-exports a value of " + pkgPath,
+				reexported: true,
+			}, affectedMap, timings)
+		}
+	}
+}
+
+// uniqueInterfaceMethods removes duplicate interface methods
+func uniqueInterfaceMethods(methods []InterfaceMethodRange) []InterfaceMethodRange {
+	seen := make(map[string]bool)
+	result := make([]InterfaceMethodRange, 0, len(methods))
+	for _, m := range methods {
+		key := m.InterfaceName + "." + m.MethodName
+		if !seen[key] {
+			seen[key] = true
+			result = append(result, m)
+		}
+	}
+	return result
+}
+
+// findPackagesThatCallInterfaceMethods finds packages that import the source package
+// and call the specified interface methods. These packages "propagate" the change.
+func (a *Analyzer) findPackagesThatCallInterfaceMethods(sourcePkgPath string, methods []InterfaceMethodRange) []string {
+	if len(methods) == 0 {
+		return nil
+	}
+
+	var propagatingPkgs []string
+
+	// Find all packages that import the source package
+	for pkgPath, deps := range a.graph.deps {
+		// Skip the source package itself
+		if pkgPath == sourcePkgPath {
+			continue
+		}
+
+		// Check if this package imports the source package
+		importsSource := false
+		for _, dep := range deps {
+			if dep == sourcePkgPath {
+				importsSource = true
+				break
+			}
+		}
+
+		if !importsSource {
+			continue
+		}
+
+		// Get the directory for this package
+		pkgDir := a.getPkgDir(pkgPath)
+		if pkgDir == "" {
+			continue
+		}
+
+		// Check if this package calls any of the interface methods
+		callsMethods, _ := a.symbolAnalyzer.CheckMethodCallUsage(pkgDir, sourcePkgPath, methods)
+		if callsMethods {
+			propagatingPkgs = append(propagatingPkgs, pkgPath)
+		}
+	}
+
+	return propagatingPkgs
+}
+
+// getPkgDir returns the directory path for a package
+func (a *Analyzer) getPkgDir(pkgPath string) string {
+	// Convert module-relative package path to filesystem path
+	if strings.HasPrefix(pkgPath, a.config.ModulePath) {
+		relPath := strings.TrimPrefix(pkgPath, a.config.ModulePath)
+		relPath = strings.TrimPrefix(relPath, "/")
+		return filepath.Join(a.config.ProjectRoot, relPath)
+	}
+	return ""
+}
+
+// isResourceAffectedBySymbols checks if a resource is actually affected by the changed symbols
+func (a *Analyzer) isResourceAffectedBySymbols(resource *Resource, changedPkgPath string, info changedSymbolsInfo) bool {
+	// If there are no exported symbols or interface methods changed, consider it not affected
+	// (Note: unexported changes are now handled by adding exported symbols from the same file)
+	if len(info.symbols) == 0 && len(info.interfaceMethods) == 0 {
+		return false
+	}
+
+	// If the changed package IS the resource's package (or a subpackage), it's always affected
+	// This handles cases where files are added/modified within the resource's own package
+	if resource.Package == changedPkgPath || strings.HasPrefix(changedPkgPath, resource.Package+"/") {
+		return true
+	}
+
+	// Special handling for DI provider packages (under pkg/provider/)
+	// For these packages, we check if the resource uses the interface that the provider provides,
+	// rather than checking the intermediate aggregation package (like job/provider)
+	if strings.Contains(changedPkgPath, "/pkg/provider/") {
+		return a.isResourceAffectedByProviderChange(resource, changedPkgPath, info)
+	}
+
+	// Special handling for aggregator provider packages (like job/provider)
+	// These packages aggregate multiple providers via fx.Options
+	// We need to identify which specific providers were changed and check if the resource uses them
+	if a.isAggregatorProviderPackage(changedPkgPath) {
+		return a.isResourceAffectedByAggregatorChange(resource, changedPkgPath, info)
+	}
+
+	// Get all packages that the resource depends on (including subpackages of the resource)
+	allDeps := a.graph.GetAllDeps(resource.Package)
+
+	// Collect packages to check: resource package itself + all its subpackages
+	packagesToCheck := []string{resource.Package}
+	for _, dep := range allDeps {
+		// Check if this dependency is a subpackage of the resource (e.g., resource/job)
+		if strings.HasPrefix(dep, resource.Package+"/") {
+			packagesToCheck = append(packagesToCheck, dep)
+		}
+	}
+
+	// Check each package for symbol usage
+	for _, pkg := range packagesToCheck {
+		// Verify that this package depends on the changed package (directly or transitively)
+		pkgDeps := a.graph.GetAllDeps(pkg)
+		dependsOnChanged := false
+		for _, d := range pkgDeps {
+			if d == changedPkgPath {
+				dependsOnChanged = true
+				break
+			}
+		}
+		if !dependsOnChanged {
+			continue
+		}
+
+		// Find all packages that directly import the changed package
+		// This could be pkg itself or multiple of its dependencies
+		var directImporters []string
+
+		// Check if pkg directly imports the changed package
+		pkgDirectDeps := a.graph.GetDirectDeps(pkg)
+		for _, d := range pkgDirectDeps {
+			if d == changedPkgPath {
+				directImporters = append(directImporters, pkg)
+				break
+			}
+		}
+
+		// Find which of pkg's dependencies directly import the changed package
+		for _, dep := range pkgDeps {
+			depDirectDeps := a.graph.GetDirectDeps(dep)
+			for _, d := range depDirectDeps {
+				if d == changedPkgPath {
+					directImporters = append(directImporters, dep)
+					break
+				}
+			}
+		}
+
+		if len(directImporters) == 0 {
+			continue
+		}
+
+		// Check each direct importer for symbol usage
+		for _, directImporter := range directImporters {
+			pkgDir := a.symbolAnalyzer.GetPackageDir(directImporter)
+
+			// Check regular symbol usage
+			if len(info.symbols) > 0 {
+				usesSymbols, err := a.symbolAnalyzer.CheckSymbolUsage(pkgDir, changedPkgPath, info.symbols)
+				if err != nil {
+					continue
+				}
+				if usesSymbols {
+					// Additional check: if the direct importer is an intermediate package (not the package being checked),
+					// verify that the package being checked (or resource) actually uses the affected symbols from the direct importer
+					if directImporter != pkg {
+						// Get the affected exported symbols in the direct importer
+						affectedSymbolsInImporter := a.getAffectedExportedSymbols(directImporter, changedPkgPath, info.symbols)
+						if len(affectedSymbolsInImporter) > 0 {
+							// Check if pkg or resource uses any of the affected symbols from the direct importer
+							checkPkgDir := a.symbolAnalyzer.GetPackageDir(pkg)
+							usesAffected, _ := a.symbolAnalyzer.CheckSymbolUsage(checkPkgDir, directImporter, affectedSymbolsInImporter)
+							if !usesAffected {
+								resourcePkgDir := a.symbolAnalyzer.GetPackageDir(resource.Package)
+								usesAffectedFromResource, _ := a.symbolAnalyzer.CheckSymbolUsage(resourcePkgDir, directImporter, affectedSymbolsInImporter)
+								if !usesAffectedFromResource {
+									continue
+								}
+							}
+						} else {
+							// No affected symbols in the intermediate package
+							continue
+						}
+					}
+					return true
+				}
+			}
+
+			// Check interface method usage
+			if len(info.interfaceMethods) > 0 {
+				usesMethods, err := a.symbolAnalyzer.CheckMethodCallUsage(pkgDir, changedPkgPath, info.interfaceMethods)
+				if err != nil {
+					continue
+				}
+				if usesMethods {
+					// Additional check: if the direct importer is an intermediate package,
+					// verify that the resource actually uses the affected symbols from the direct importer
+					if directImporter != pkg {
+						// Get the affected exported symbols in the direct importer that use the changed interface methods
+						affectedSymbolsInImporter := a.getAffectedExportedSymbolsByMethods(directImporter, changedPkgPath, info.interfaceMethods)
+						if len(affectedSymbolsInImporter) > 0 {
+							// Check if pkg or resource uses any of the affected symbols from the direct importer
+							checkPkgDir := a.symbolAnalyzer.GetPackageDir(pkg)
+							usesAffected, _ := a.symbolAnalyzer.CheckSymbolUsage(checkPkgDir, directImporter, affectedSymbolsInImporter)
+							if !usesAffected {
+								resourcePkgDir := a.symbolAnalyzer.GetPackageDir(resource.Package)
+								usesAffectedFromResource, _ := a.symbolAnalyzer.CheckSymbolUsage(resourcePkgDir, directImporter, affectedSymbolsInImporter)
+								if !usesAffectedFromResource {
+									continue
+								}
+							}
+						} else {
+							// No affected symbols in the intermediate package
+							continue
+						}
+					}
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// getAffectedExportedSymbols finds exported symbols in a package that use the changed symbols from another package
+func (a *Analyzer) getAffectedExportedSymbols(pkgPath, changedPkgPath string, changedSymbols []string) []string {
+	pkgDir := a.symbolAnalyzer.GetPackageDir(pkgPath)
+
+	// Get all exported symbols in the package
+	allExportedSymbols, err := a.symbolAnalyzer.ExtractAllExportedSymbolsFromDir(pkgDir)
+	if err != nil {
+		return nil
+	}
+
+	// For each exported symbol, check if it uses any of the changed symbols
+	var affectedSymbols []string
+	for _, sym := range allExportedSymbols {
+		// Check if this symbol's implementation uses any of the changed symbols
+		usesChanged, _ := a.symbolAnalyzer.CheckSymbolUsesSymbols(pkgDir, changedPkgPath, changedSymbols, sym)
+		if usesChanged {
+			affectedSymbols = append(affectedSymbols, sym)
+		}
+	}
+
+	// If a factory function (like New) is affected, also include the interface it returns
+	// This is because changes to the implementation affect all code using the interface
+	if len(affectedSymbols) > 0 {
+		returnTypes := a.symbolAnalyzer.GetFactoryReturnTypes(pkgDir, affectedSymbols)
+		for _, rt := range returnTypes {
+			// Check if return type is an exported interface in this package
+			for _, sym := range allExportedSymbols {
+				if sym == rt && !contains(affectedSymbols, sym) {
+					affectedSymbols = append(affectedSymbols, sym)
+				}
+			}
+		}
+	}
+
+	return affectedSymbols
+}
+
+// contains checks if a string slice contains a value
+func contains(slice []string, val string) bool {
+	for _, s := range slice {
+		if s == val {
+			return true
+		}
+	}
+	return false
+}
+
+// getAffectedExportedSymbolsByMethods finds exported symbols in a package that call the changed interface methods
+func (a *Analyzer) getAffectedExportedSymbolsByMethods(pkgPath, changedPkgPath string, changedMethods []InterfaceMethodRange) []string {
+	pkgDir := a.symbolAnalyzer.GetPackageDir(pkgPath)
+
+	// Get all exported symbols in the package
+	allExportedSymbols, err := a.symbolAnalyzer.ExtractAllExportedSymbolsFromDir(pkgDir)
+	if err != nil {
+		return nil
+	}
+
+	// For each exported symbol, check if it calls any of the changed interface methods
+	var affectedSymbols []string
+	for _, sym := range allExportedSymbols {
+		// Check if this symbol's implementation calls any of the changed methods
+		usesMethods, _ := a.symbolAnalyzer.CheckSymbolUsesInterfaceMethods(pkgDir, changedPkgPath, changedMethods, sym)
+		if usesMethods {
+			affectedSymbols = append(affectedSymbols, sym)
+		}
+	}
+
+	// If a factory function (like New) is affected, also include the interface it returns
+	if len(affectedSymbols) > 0 {
+		returnTypes := a.symbolAnalyzer.GetFactoryReturnTypes(pkgDir, affectedSymbols)
+		for _, rt := range returnTypes {
+			for _, sym := range allExportedSymbols {
+				if sym == rt && !contains(affectedSymbols, sym) {
+					affectedSymbols = append(affectedSymbols, sym)
+				}
+			}
+		}
+	}
+
+	return affectedSymbols
+}
+
+// isResourceAffectedByProviderChange checks if a resource is affected by changes to a DI provider package
+// For provider packages, we directly check if the resource uses the interface that the provider provides,
+// bypassing intermediate aggregation packages like job/provider
+func (a *Analyzer) isResourceAffectedByProviderChange(resource *Resource, changedPkgPath string, info changedSymbolsInfo) bool {
+	// Get the provider package directory
+	providerPkgDir := a.symbolAnalyzer.GetPackageDir(changedPkgPath)
+
+	// Find the interface types that this provider provides
+	// Provider packages typically have a New function that returns an interface
+	var providedInterfaces []string
+	for _, sym := range info.symbols {
+		returnTypes := a.symbolAnalyzer.GetFactoryReturnTypes(providerPkgDir, []string{sym})
+		providedInterfaces = append(providedInterfaces, returnTypes...)
+	}
+
+	// If no interfaces are provided, the provider may only have Provider variable changed
+	// In that case, we need to find what interface the New function returns
+	if len(providedInterfaces) == 0 {
+		returnTypes := a.symbolAnalyzer.GetFactoryReturnTypes(providerPkgDir, []string{"New"})
+		providedInterfaces = append(providedInterfaces, returnTypes...)
+	}
+
+	// If still no interfaces found, fall back to not affected (conservative for provider packages)
+	if len(providedInterfaces) == 0 {
+		return false
+	}
+
+	// Find the package that defines these interface types
+	// The provider typically imports and returns an interface from another package (e.g., mcm.MCMClient)
+	interfacePackages := a.findInterfaceDefinitionPackages(providerPkgDir, providedInterfaces)
+
+	// Check if the resource uses any of the provided interfaces
+	// We check the resource package and all its subpackages
+	allDeps := a.graph.GetAllDeps(resource.Package)
+	packagesToCheck := []string{resource.Package}
+	for _, dep := range allDeps {
+		if strings.HasPrefix(dep, resource.Package+"/") {
+			packagesToCheck = append(packagesToCheck, dep)
+		}
+	}
+
+	for _, pkg := range packagesToCheck {
+		pkgDir := a.symbolAnalyzer.GetPackageDir(pkg)
+
+		for interfacePkg, interfaceNames := range interfacePackages {
+			// Check if this package uses the provided interface via DI (struct fields, function params)
+			usesInterface, _ := a.diAnalyzer.CheckTypeUsage(pkgDir, interfacePkg, interfaceNames)
+			if usesInterface {
+				return true
+			}
+
+			// Also check direct symbol usage (for cases where the interface is used as a type annotation)
+			usesSymbol, _ := a.symbolAnalyzer.CheckSymbolUsage(pkgDir, interfacePkg, interfaceNames)
+			if usesSymbol {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isAggregatorProviderPackage checks if a package is an aggregator provider package
+// Aggregator packages export fx.Options variables that combine multiple providers
+func (a *Analyzer) isAggregatorProviderPackage(pkgPath string) bool {
+	// Check common patterns for aggregator packages
+	// - job/provider
+	// - api-gateway/provider (but not api-gateway/provider/*)
+	// - Contains "provider" in path but not under pkg/provider/
+	if strings.Contains(pkgPath, "/pkg/provider/") {
+		return false
+	}
+
+	// Check if the path ends with "/provider" or contains "/provider/" followed by no more subdirs
+	parts := strings.Split(pkgPath, "/")
+	for i, part := range parts {
+		if part == "provider" {
+			// If "provider" is the last part, it's likely an aggregator
+			if i == len(parts)-1 {
+				return true
+			}
+			// If there's only "internal" after provider, also check
+			if i < len(parts)-1 && parts[i+1] == "internal" {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isResourceAffectedByAggregatorChange checks if a resource is affected by changes to an aggregator provider package
+// It analyzes which providers were added/modified in the fx.Options and checks if the resource uses them
+func (a *Analyzer) isResourceAffectedByAggregatorChange(resource *Resource, changedPkgPath string, info changedSymbolsInfo) bool {
+	pkgDir := a.symbolAnalyzer.GetPackageDir(changedPkgPath)
+
+	// Parse the aggregator package to find fx.Options variables and their referenced providers
+	referencedProviders := a.extractReferencedProviders(pkgDir, info.symbols)
+
+	if len(referencedProviders) == 0 {
+		// If we can't determine which providers are referenced, fall back to checking
+		// if the resource uses any of the aggregator's direct dependencies
+		return false
+	}
+
+	// For each referenced provider package, check if the resource uses its provided interfaces
+	allDeps := a.graph.GetAllDeps(resource.Package)
+	packagesToCheck := []string{resource.Package}
+	for _, dep := range allDeps {
+		if strings.HasPrefix(dep, resource.Package+"/") {
+			packagesToCheck = append(packagesToCheck, dep)
+		}
+	}
+
+	for _, providerPkg := range referencedProviders {
+		// Get the interface that this provider provides
+		providerDir := a.symbolAnalyzer.GetPackageDir(providerPkg)
+		returnTypes := a.symbolAnalyzer.GetFactoryReturnTypes(providerDir, []string{"New"})
+
+		if len(returnTypes) == 0 {
+			continue
+		}
+
+		// Find where these interfaces are defined
+		interfacePackages := a.findInterfaceDefinitionPackages(providerDir, returnTypes)
+
+		// Check if the resource uses any of these interfaces
+		for _, pkg := range packagesToCheck {
+			checkPkgDir := a.symbolAnalyzer.GetPackageDir(pkg)
+
+			for interfacePkg, interfaceNames := range interfacePackages {
+				usesInterface, _ := a.diAnalyzer.CheckTypeUsage(checkPkgDir, interfacePkg, interfaceNames)
+				if usesInterface {
+					return true
+				}
+
+				usesSymbol, _ := a.symbolAnalyzer.CheckSymbolUsage(checkPkgDir, interfacePkg, interfaceNames)
+				if usesSymbol {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// extractReferencedProviders extracts provider package paths referenced in fx.Options variables
+func (a *Analyzer) extractReferencedProviders(pkgDir string, changedSymbols []string) []string {
+	var providers []string
+
+	entries, err := a.fs.ReadDir(pkgDir)
+	if err != nil {
+		return providers
+	}
+
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, entry.Name())
+		file, err := parser.ParseFile(fset, filePath, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		// Build import map: alias -> full path
+		importMap := make(map[string]string)
+		for _, imp := range file.Imports {
+			path := strings.Trim(imp.Path.Value, `"`)
+			alias := ""
+			if imp.Name != nil {
+				alias = imp.Name.Name
+			} else {
+				parts := strings.Split(path, "/")
+				alias = parts[len(parts)-1]
+			}
+			importMap[alias] = path
+		}
+
+		// Find variable declarations (fx.Options)
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+
+				// Check if this is one of the changed symbols
+				isChanged := len(changedSymbols) == 0 // If no specific symbols, check all
+				for _, name := range valueSpec.Names {
+					for _, changedSym := range changedSymbols {
+						if name.Name == changedSym {
+							isChanged = true
+							break
+						}
+					}
+				}
+
+				if !isChanged {
+					continue
+				}
+
+				// Extract provider references from the value (fx.Options(...))
+				for _, value := range valueSpec.Values {
+					a.extractProvidersFromExpr(value, importMap, &providers)
+				}
+			}
+		}
+	}
+
+	return uniqueStrings(providers)
+}
+
+// extractProvidersFromExpr recursively extracts provider package references from an expression
+func (a *Analyzer) extractProvidersFromExpr(expr ast.Expr, importMap map[string]string, providers *[]string) {
+	switch e := expr.(type) {
+	case *ast.CallExpr:
+		// fx.Options(...) or fx.Provide(...)
+		for _, arg := range e.Args {
+			a.extractProvidersFromExpr(arg, importMap, providers)
+		}
+	case *ast.SelectorExpr:
+		// pkg.Provider or pkg.New
+		if ident, ok := e.X.(*ast.Ident); ok {
+			if pkgPath, ok := importMap[ident.Name]; ok {
+				// Only include if it's a provider package (contains "provider" in path)
+				if strings.Contains(pkgPath, "provider") || e.Sel.Name == "Provider" {
+					*providers = append(*providers, pkgPath)
+				}
+			}
+		}
+	case *ast.Ident:
+		// Local reference (e.g., repository.Provider within the same package)
+		// Skip for now as it's internal
+	}
+}
+
+// findInterfaceDefinitionPackages finds the packages that define the given interface types
+// Returns a map of package path -> interface names
+func (a *Analyzer) findInterfaceDefinitionPackages(providerPkgDir string, interfaceNames []string) map[string][]string {
+	result := make(map[string][]string)
+
+	entries, err := a.fs.ReadDir(providerPkgDir)
+	if err != nil {
+		return result
+	}
+
+	fset := token.NewFileSet()
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(providerPkgDir, entry.Name())
+		file, err := parser.ParseFile(fset, filePath, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		// Build import map: alias -> full path
+		importMap := make(map[string]string)
+		for _, imp := range file.Imports {
+			path := strings.Trim(imp.Path.Value, `"`)
+			alias := ""
+			if imp.Name != nil {
+				alias = imp.Name.Name
+			} else {
+				parts := strings.Split(path, "/")
+				alias = parts[len(parts)-1]
+			}
+			importMap[alias] = path
+		}
+
+		// Find function declarations that return the target interface types
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Type.Results == nil {
+				continue
+			}
+
+			for _, resultField := range funcDecl.Type.Results.List {
+				// Check if the return type is one of our interface names
+				switch t := resultField.Type.(type) {
+				case *ast.SelectorExpr:
+					// pkg.Type
+					if ident, ok := t.X.(*ast.Ident); ok {
+						typeName := t.Sel.Name
+						for _, ifaceName := range interfaceNames {
+							if typeName == ifaceName {
+								if pkgPath, ok := importMap[ident.Name]; ok {
+									result[pkgPath] = append(result[pkgPath], typeName)
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// GetAffectedResourcesByPackage identifies resources affected by a package path
+func (a *Analyzer) GetAffectedResourcesByPackage(pkgPath string) []AffectedResource {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	var result []AffectedResource
+
+	resourceNames := a.reverseDeps[pkgPath]
+	for _, name := range resourceNames {
+		resource := a.getResourceByName(name)
+		if resource != nil {
+			result = append(result, AffectedResource{
+				Resource:        *resource,
+				Reason:          fmt.Sprintf("depends on %s", pkgPath),
+				AffectedPackage: pkgPath,
+				DependencyChain: a.getDependencyChain(resource.Package, pkgPath),
+				Confidence:      ConfidenceHigh,
+			})
+		}
+	}
+
+	return result
+}
+
+// toAbsPath converts a changed file path (relative to the project root, possibly with PathPrefix) to an absolute path
+func (a *Analyzer) toAbsPath(file string) string {
+	if filepath.IsAbs(file) {
+		return file
+	}
+	pathWithoutPrefix := filepath.ToSlash(file)
+	if a.config.PathPrefix != "" {
+		pathWithoutPrefix = strings.TrimPrefix(pathWithoutPrefix, a.config.PathPrefix)
+	}
+	return filepath.Join(a.config.ProjectRoot, filepath.FromSlash(pathWithoutPrefix))
+}
+
+// dirToPackage converts a directory relative to the project root to a package path
+func (a *Analyzer) dirToPackage(dir string) string {
+	dir = strings.Trim(filepath.ToSlash(filepath.Clean(dir)), "/")
+	if dir == "." || dir == "" {
+		return a.config.ModulePath
+	}
+	return a.packageCase(a.config.ModulePath + "/" + dir)
+}
+
+// fileToPackage infers package path from file path
+func (a *Analyzer) fileToPackage(filePath string) string {
+	// Convert to relative path
+	relPath := filePath
+	if filepath.IsAbs(filePath) {
+		var err error
+		relPath, err = filepath.Rel(a.config.ProjectRoot, filePath)
+		if err != nil {
+			return ""
+		}
+	}
+
+	// Package paths are slash-separated whatever the separator of the file path
+	relPath = filepath.ToSlash(relPath)
+
+	// Remove path prefix (e.g., "go/" if git diff returns paths from repo root)
+	if a.config.PathPrefix != "" {
+		relPath = strings.TrimPrefix(relPath, a.config.PathPrefix)
+	}
+
+	// Ignore non-Go files
+	if !strings.HasSuffix(relPath, ".go") {
+		return ""
+	}
+
+	// Get directory path
+	dir := path.Dir(relPath)
+	if dir == "." {
+		return a.config.ModulePath
+	}
+
+	// Build package path
+	pkgPath := a.config.ModulePath + "/" + dir
+	return a.packageCase(pkgPath)
+}
+
+// packageCase returns the listed package a package path derived from a file path designates
+// On case-insensitive filesystems, directories spelled with another case than go list reports are the same
+func (a *Analyzer) packageCase(pkgPath string) string {
+	if !a.caseInsensitive {
+		return pkgPath
+	}
+	return a.graph.packageCase(pkgPath)
+}
+
+// getResourceByName gets a resource by name
+// Resources excluded by Config.Resources are not returned, so that their impact is never checked
+func (a *Analyzer) getResourceByName(name string) *Resource {
+	if len(a.config.Resources) > 0 && !contains(a.config.Resources, name) {
+		return nil
+	}
+	for i := range a.resources {
+		if a.resources[i].Name == name {
+			return &a.resources[i]
+		}
+	}
+	return nil
+}
+
+// getDependencyChain gets the dependency chain
+func (a *Analyzer) getDependencyChain(fromPkg, toPkg string) []string {
+	// Simple implementation: find shortest path with BFS
+	if fromPkg == toPkg {
+		return []string{fromPkg}
+	}
+
+	type node struct {
+		pkg  string
+		path []string
+	}
+
+	visited := make(map[string]bool)
+	queue := []node{{pkg: fromPkg, path: []string{fromPkg}}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if visited[current.pkg] {
+			continue
+		}
+		visited[current.pkg] = true
+
+		deps := a.graph.GetDirectDeps(current.pkg)
+		for _, dep := range deps {
+			newPath := append([]string{}, current.path...)
+			newPath = append(newPath, dep)
+
+			if dep == toPkg {
+				return newPath
+			}
+
+			queue = append(queue, node{pkg: dep, path: newPath})
+		}
+	}
+
+	return nil
+}
+
+// GetReverseDeps returns resource names that depend on the specified package
+func (a *Analyzer) GetReverseDeps(pkgPath string) []string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.reverseDeps[pkgPath]
+}
+
+// GetAllReverseDeps returns all reverse dependency mappings
+func (a *Analyzer) GetAllReverseDeps() map[string][]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.reverseDeps
+}
+
+// GetDependencyGraph returns the dependency graph
+func (a *Analyzer) GetDependencyGraph() *DependencyGraph {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.graph
+}
+
+// uniqueStrings removes duplicates from a slice
+func uniqueStrings(s []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// isInfrastructureFile checks if a file is an infrastructure file
+// Infrastructure files are common files that many resources depend on,
+// but changes to them should only affect resources that use the specific changed symbols
+func (a *Analyzer) isInfrastructureFile(filePath string) bool {
+	// Normalize the file path
+	normalizedPath := filepath.ToSlash(filePath)
+
+	// Remove path prefix if present
+	if a.config.PathPrefix != "" {
+		normalizedPath = strings.TrimPrefix(normalizedPath, a.config.PathPrefix)
+	}
+
+	// Check against configured infrastructure files
+	for _, infraFile := range a.config.InfrastructureFiles {
+		infraNormalized := filepath.ToSlash(infraFile)
+		if normalizedPath == infraNormalized || strings.HasSuffix(normalizedPath, "/"+infraNormalized) {
+			return true
+		}
+	}
+
+	// Default infrastructure file patterns
+	// These are auto-generated files that define shared types/infrastructure
+	defaultInfraPatterns := []string{
+		"sqlc/db.go",
+		"sqlc/models.go",
+		"sqlc/querier.go",
+	}
+
+	for _, pattern := range defaultInfraPatterns {
+		if normalizedPath == pattern || strings.HasSuffix(normalizedPath, "/"+pattern) {
+			return true
+		}
+	}
+
+	// Check against configured infrastructure directories
+	for _, dir := range a.config.InfrastructureDirs {
+		dirNormalized := strings.TrimSuffix(filepath.ToSlash(dir), "/")
+		if strings.HasPrefix(normalizedPath, dirNormalized+"/") {
+			return true
+		}
+	}
+
+	// Check against configured glob patterns
+	for _, pattern := range a.config.InfrastructurePatterns {
+		if matchGlob(filepath.ToSlash(pattern), normalizedPath) {
+			return true
+		}
+	}
+
+	// Auto-detect generated files
+	if a.config.InfrastructureGeneratedFiles && a.isGeneratedFile(a.toAbsPath(filePath)) {
+		return true
+	}
+
+	return false
+}
+
+// generatedCodeRegex matches the standard generated code header (https://go.dev/s/generatedcode)
+var generatedCodeRegex = regexp.MustCompile(`(?m)^// Code generated .* DO NOT EDIT\.$`)
+
+// packageClauseRegex matches the package clause of a Go file
+var packageClauseRegex = regexp.MustCompile(`(?m)^package `)
+
+// isGeneratedFile checks if a Go file has a "// Code generated ... DO NOT EDIT." header before the package clause
+func (a *Analyzer) isGeneratedFile(absPath string) bool {
+	content, err := a.fs.ReadFile(absPath)
+	if err != nil {
+		return false
+	}
+
+	// The header must appear before the package clause
+	header := content
+	if idx := packageClauseRegex.FindIndex(content); idx != nil {
+		header = content[:idx[0]]
+	}
+	return generatedCodeRegex.Match(header)
+}
@@ -0,0 +1,126 @@
+package analyzer
+
+import (
+	"bytes"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// annotationMarker starts the comments declaring resources explicitly, for resources that static
+// extraction cannot find (e.g., commands built dynamically):
+//
+//	// impact:resource name=api type=api entry=github.com/org/x/api description="Public API"
+//
+// entry defaults to the package of the file holding the comment and may be relative to the module path
+const annotationMarker = "impact:resource"
+
+// extractAnnotatedResources returns the resources declared by impact:resource comments in the packages of the graph
+// Invalid annotations and names already taken are reported as parse warnings
+func (a *Analyzer) extractAnnotatedResources(taken map[string]bool) ([]Resource, []*AnalysisError) {
+	packages := a.graph.GetAllPackages()
+	sort.Strings(packages)
+
+	var resources []Resource
+	var warnings []*AnalysisError
+	for _, pkgPath := range packages {
+		pkgDir := a.getPkgDir(pkgPath)
+		entries, err := a.fs.ReadDir(pkgDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+			filePath := filepath.Join(pkgDir, entry.Name())
+			content, err := a.fs.ReadFile(filePath)
+			// Most files have no annotation: skip them before parsing
+			if err != nil || !bytes.Contains(content, []byte(annotationMarker)) {
+				continue
+			}
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments|parser.SkipObjectResolution)
+			if err != nil {
+				continue
+			}
+
+			for _, group := range file.Comments {
+				for _, c := range group.List {
+					text, ok := strings.CutPrefix(c.Text, "//")
+					if !ok {
+						continue
+					}
+					args, ok := strings.CutPrefix(strings.TrimSpace(text), annotationMarker)
+					if !ok || (args != "" && args[0] != ' ' && args[0] != '\t') {
+						continue
+					}
+					r, err := a.parseAnnotation(args, pkgPath)
+					if err == nil && taken[r.Name] {
+						err = fmt.Errorf("resource %s is already declared", r.Name)
+					}
+					if err != nil {
+						file := fmt.Sprintf("%s:%d", a.projectRelPath(filePath), fset.Position(c.Pos()).Line)
+						warnings = append(warnings, &AnalysisError{Kind: ErrParse, File: file, Err: fmt.Errorf("invalid %s comment: %w", annotationMarker, err)})
+						continue
+					}
+					taken[r.Name] = true
+					r.SourceFile = filePath
+					resources = append(resources, r)
+				}
+			}
+		}
+	}
+	return resources, warnings
+}
+
+// parseAnnotation parses the key=value arguments of an impact:resource comment of a package
+// Values may be quoted Go strings (description="Public API")
+func (a *Analyzer) parseAnnotation(args, pkgPath string) (Resource, error) {
+	r := Resource{Package: pkgPath}
+	for args = strings.TrimSpace(args); args != ""; args = strings.TrimSpace(args) {
+		key, rest, ok := strings.Cut(args, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			return Resource{}, fmt.Errorf("want key=value arguments, got %q", args)
+		}
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return Resource{}, fmt.Errorf("unterminated value of %s", key)
+			}
+			value, _ = strconv.Unquote(quoted)
+			args = rest[len(quoted):]
+		} else {
+			value, args, _ = strings.Cut(rest, " ")
+		}
+
+		switch key {
+		case "name":
+			r.Name = value
+		case "type":
+			r.Type = ResourceType(value)
+		case "entry":
+			r.Package = value
+			if value != a.config.ModulePath && !strings.HasPrefix(value, a.config.ModulePath+"/") {
+				r.Package = a.config.ModulePath + "/" + strings.Trim(value, "/")
+			}
+		case "description":
+			r.Description = value
+		default:
+			return Resource{}, fmt.Errorf("unknown argument %s", key)
+		}
+	}
+
+	if r.Name == "" {
+		return Resource{}, fmt.Errorf("missing name")
+	}
+	if !r.Type.IsValid() {
+		return Resource{}, fmt.Errorf("unknown resource type %q of %s", r.Type, r.Name)
+	}
+	return r, nil
+}
@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"go/parser"
+	"go/token"
+	"go/types"
+	"regexp"
+	"strings"
+
+	"golang.org/x/exp/apidiff"
+	"golang.org/x/tools/go/packages"
+)
+
+// apidiffMessage is a change reported by golang.org/x/exp/apidiff for a symbol
+type apidiffMessage struct {
+	// symbol is named like the symbols of ExtractAPIFromContent ("Store.Save" for (*Store).Save)
+	symbol     string
+	message    string
+	compatible bool
+}
+
+// typedAPIChanges compares the changed packages at base and head with golang.org/x/exp/apidiff
+// Both sides are type-checked with go/packages, the base one with the base content of the changed files
+// overlaid on the work tree (imported packages are those of the work tree). Packages that do not type-check
+// on either side (e.g., broken code, missing modules or cgo) are left out, and the classification of their
+// changes falls back to apiChangeCompatible
+func (a *Analyzer) typedAPIChanges(changedByPackage map[string]map[string]string) map[string][]apidiffMessage {
+	var pkgPaths []string
+	overlay := make(map[string][]byte)
+	for pkgPath, changed := range changedByPackage {
+		pkgPaths = append(pkgPaths, pkgPath)
+		for absPath, origPath := range changed {
+			content, err := a.config.GitClient.GetFileContentAtBase(origPath)
+			if err != nil || len(content) == 0 {
+				// Files added in head are reduced to their package clause at base
+				head, err := a.fs.ReadFile(absPath)
+				if err != nil {
+					continue
+				}
+				file, err := parser.ParseFile(token.NewFileSet(), absPath, head, parser.PackageClauseOnly)
+				if err != nil {
+					continue
+				}
+				content = []byte("package " + file.Name.Name + "\n")
+			}
+			overlay[absPath] = content
+		}
+	}
+	if len(pkgPaths) == 0 {
+		return nil
+	}
+
+	head := a.loadTypedPackages(pkgPaths, nil)
+	base := a.loadTypedPackages(pkgPaths, overlay)
+	reports := make(map[string][]apidiffMessage)
+	for _, pkgPath := range pkgPaths {
+		before, after := base[pkgPath], head[pkgPath]
+		if before == nil || after == nil {
+			continue
+		}
+		messages := []apidiffMessage{}
+		for _, c := range apidiff.Changes(before, after).Changes {
+			name, message, ok := strings.Cut(c.Message, ": ")
+			if !ok {
+				continue
+			}
+			// Pointer receivers are written (*T).M
+			name = strings.NewReplacer("(*", "", ")", "").Replace(name)
+			messages = append(messages, apidiffMessage{symbol: name, message: message, compatible: c.Compatible})
+		}
+		reports[pkgPath] = messages
+	}
+	return reports
+}
+
+// loadTypedPackages type-checks packages of the project with go/packages, the files of the overlay
+// replacing (or adding to) those of the work tree; packages with errors are left out
+func (a *Analyzer) loadTypedPackages(pkgPaths []string, overlay map[string][]byte) map[string]*types.Package {
+	cfg := &packages.Config{
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedSyntax,
+		Dir:     a.config.ProjectRoot,
+		Overlay: overlay,
+	}
+	pkgs, err := packages.Load(cfg, pkgPaths...)
+	if err != nil {
+		a.config.Logger.Debug("failed to type-check packages for apidiff", "error", err)
+		return nil
+	}
+	loaded := make(map[string]*types.Package)
+	for _, pkg := range pkgs {
+		if len(pkg.Errors) > 0 || pkg.Types == nil || pkg.Name == "main" {
+			a.config.Logger.Debug("apidiff skips package", "package", pkg.PkgPath, "errors", len(pkg.Errors))
+			continue
+		}
+		loaded[pkg.PkgPath] = pkg.Types
+	}
+	return loaded
+}
+
+// changedFrom matches the messages of apidiff describing a changed type ("changed from func() to func(int)")
+var changedFrom = regexp.MustCompile(`^(?:value )?changed from (.*) to (.*)$`)
+
+// applyAPIDiff sets the compatibility of the changes of a package from the messages of apidiff
+// A change is incompatible if apidiff reports an incompatible change of its symbol, of one of its members
+// (e.g., a method of a changed interface) or, for fields, of their struct (e.g., a field added to a comparable
+// struct making it incomparable). Incompatible changes of symbols missing from changes are added to them
+func applyAPIDiff(pkgPath string, changes []APIChange, messages []apidiffMessage) []APIChange {
+	matched := make(map[string]bool)
+	for i := range changes {
+		changes[i].Compatible = true
+		typeName, _, isMember := strings.Cut(changes[i].Symbol, ".")
+		isField := isMember && !strings.HasPrefix(changes[i].Before+changes[i].After, "func (")
+		for _, m := range messages {
+			if m.compatible {
+				continue
+			}
+			if m.symbol == changes[i].Symbol || (isField && m.symbol == typeName) || strings.HasPrefix(m.symbol, changes[i].Symbol+".") {
+				if changes[i].Compatible {
+					changes[i].Compatible = false
+					changes[i].Message = m.message
+				}
+				matched[m.symbol] = true
+			}
+		}
+	}
+
+	for _, m := range messages {
+		if m.compatible || matched[m.symbol] {
+			continue
+		}
+		c := APIChange{Package: pkgPath, Symbol: m.symbol, Kind: APIChangeChanged, Message: m.message}
+		switch {
+		case m.message == "removed":
+			c.Kind = APIChangeRemoved
+		case changedFrom.MatchString(m.message):
+			parts := changedFrom.FindStringSubmatch(m.message)
+			c.Before, c.After = parts[1], parts[2]
+		}
+		changes = append(changes, c)
+		matched[m.symbol] = true
+	}
+	return changes
+}
@@ -0,0 +1,455 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// APIChangeKind describes how an exported symbol changed
+type APIChangeKind string
+
+const (
+	APIChangeAdded   APIChangeKind = "added"
+	APIChangeRemoved APIChangeKind = "removed"
+	APIChangeChanged APIChangeKind = "changed"
+)
+
+// APIChange is a change to the public API of a package between base and head
+// Added symbols have no Before; incompatible changes are breaking
+type APIChange struct {
+	Package string `json:"package"`
+	// Symbol is the exported symbol (e.g., "GetUser", "Store.Save", "Config.Timeout")
+	Symbol string        `json:"symbol"`
+	Kind   APIChangeKind `json:"kind"`
+	Before string        `json:"before,omitempty"`
+	After  string        `json:"after,omitempty"`
+	// Compatible reports that code importing the package still compiles, as classified by golang.org/x/exp/apidiff
+	// (or apiChangeCompatible for packages that do not type-check)
+	Compatible bool `json:"compatible"`
+	// Message is the reason apidiff gives for an incompatible change (e.g., "changed from comparable to incomparable")
+	Message string `json:"message,omitempty"`
+}
+
+// ExtractAPIFromContent returns the exported API of a Go file as symbol -> signature
+// The package name is returned as well so that main packages can be skipped
+func (s *SymbolAnalyzer) ExtractAPIFromContent(content []byte) (map[string]string, string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, 0)
+	if err != nil {
+		return nil, "", err
+	}
+
+	api := make(map[string]string)
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if !isExported(d.Name.Name) {
+				continue
+			}
+			name := d.Name.Name
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				recv := extractTypeName(d.Recv.List[0].Type)
+				if !isExported(recv) {
+					continue
+				}
+				name = recv + "." + name
+				// Methods keep their receiver: a pointer receiver removes the method from the value's method set
+				api[name] = "func (" + renderNode(fset, d.Recv.List[0].Type) + ")" + renderSignature(fset, d.Type)
+				continue
+			}
+			api[name] = "func" + renderTypeParams(fset, d.Type.TypeParams) + renderSignature(fset, d.Type)
+
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch sp := spec.(type) {
+				case *ast.TypeSpec:
+					if !isExported(sp.Name.Name) {
+						continue
+					}
+					prefix := "type" + renderTypeParams(fset, sp.TypeParams) + " "
+					if sp.Assign.IsValid() {
+						prefix += "= "
+					}
+					st, ok := sp.Type.(*ast.StructType)
+					if !ok {
+						// Interfaces and other types are compared as a whole: adding an interface method breaks implementers
+						api[sp.Name.Name] = prefix + renderNode(fset, sp.Type)
+						continue
+					}
+					// Struct fields can be added freely, so only existing exported fields are tracked
+					api[sp.Name.Name] = prefix + "struct"
+					for _, field := range st.Fields.List {
+						fieldType := renderNode(fset, field.Type)
+						if len(field.Names) == 0 {
+							embedded := extractTypeName(field.Type)
+							if isExported(embedded) {
+								api[sp.Name.Name+"."+embedded] = fieldType
+							}
+							continue
+						}
+						for _, fieldName := range field.Names {
+							if isExported(fieldName.Name) {
+								api[sp.Name.Name+"."+fieldName.Name] = fieldType
+							}
+						}
+					}
+
+				case *ast.ValueSpec:
+					kind := d.Tok.String()
+					if sp.Type != nil {
+						kind += " " + renderNode(fset, sp.Type)
+					}
+					for _, name := range sp.Names {
+						if isExported(name.Name) {
+							api[name.Name] = kind
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return api, file.Name.Name, nil
+}
+
+// renderSignature renders parameter and result types of a function, ignoring parameter names
+func renderSignature(fset *token.FileSet, ft *ast.FuncType) string {
+	sig := "(" + renderFieldTypes(fset, ft.Params) + ")"
+	if ft.Results == nil || len(ft.Results.List) == 0 {
+		return sig
+	}
+	results := fieldTypes(fset, ft.Results)
+	if len(results) > 1 {
+		return sig + " (" + strings.Join(results, ", ") + ")"
+	}
+	return sig + " " + results[0]
+}
+
+// renderTypeParams renders type parameter constraints, ignoring their names
+func renderTypeParams(fset *token.FileSet, fl *ast.FieldList) string {
+	if fl == nil || len(fl.List) == 0 {
+		return ""
+	}
+	return "[" + renderFieldTypes(fset, fl) + "]"
+}
+
+// renderFieldTypes renders the types of a field list, ignoring names
+func renderFieldTypes(fset *token.FileSet, fl *ast.FieldList) string {
+	return strings.Join(fieldTypes(fset, fl), ", ")
+}
+
+// fieldTypes returns the types of a field list, one entry per name
+func fieldTypes(fset *token.FileSet, fl *ast.FieldList) []string {
+	if fl == nil {
+		return nil
+	}
+	var types []string
+	for _, field := range fl.List {
+		t := renderNode(fset, field.Type)
+		n := len(field.Names)
+		if n == 0 {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// renderNode prints an AST node in canonical form
+func renderNode(fset *token.FileSet, node ast.Node) string {
+	var buf bytes.Buffer
+	if err := printer.Fprint(&buf, fset, node); err != nil {
+		return ""
+	}
+	return buf.String()
+}
+
+// GetBreakingChanges detects removed or changed exported symbols between base and head
+// Only library packages are checked; main packages have no importable API
+func (a *Analyzer) GetBreakingChanges(changedFiles []string) []APIChange {
+	return BreakingChanges(a.GetAPIChanges(changedFiles))
+}
+
+// BreakingChanges returns the incompatible API changes
+func BreakingChanges(changes []APIChange) []APIChange {
+	var breaking []APIChange
+	for _, c := range changes {
+		if !c.Compatible {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+// GetAPIChanges compares the exported declarations of the changed packages between base and head,
+// read from the git blobs of the changed files: symbols added, removed or changed, sorted by package and symbol
+// Only library packages are checked; main packages have no importable API
+func (a *Analyzer) GetAPIChanges(changedFiles []string) []APIChange {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// Package path -> absolute path of changed file -> path passed to git
+	changedByPackage := make(map[string]map[string]string)
+	for _, file := range changedFiles {
+		if !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		pkgPath := a.fileToPackage(file)
+		if pkgPath == "" {
+			continue
+		}
+		if changedByPackage[pkgPath] == nil {
+			changedByPackage[pkgPath] = make(map[string]string)
+		}
+		changedByPackage[pkgPath][a.toAbsPath(file)] = file
+	}
+
+	typed := a.typedAPIChanges(changedByPackage)
+	var changes []APIChange
+	for pkgPath, changed := range changedByPackage {
+		messages, ok := typed[pkgPath]
+		if !ok {
+			a.config.Logger.Debug("classifying API changes without type information", "package", pkgPath)
+		}
+		changes = append(changes, a.comparePackageAPI(pkgPath, changed, messages, ok)...)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		if changes[i].Package != changes[j].Package {
+			return changes[i].Package < changes[j].Package
+		}
+		return changes[i].Symbol < changes[j].Symbol
+	})
+
+	return changes
+}
+
+// comparePackageAPI compares the exported API of a package at base and head, classified by the messages
+// of apidiff if typed, else by apiChangeCompatible
+func (a *Analyzer) comparePackageAPI(pkgPath string, changed map[string]string, messages []apidiffMessage, typed bool) []APIChange {
+	headAPI := make(map[string]string)
+	baseAPI := make(map[string]string)
+	isMain := false
+
+	merge := func(dst map[string]string, content []byte) {
+		api, pkgName, err := a.symbolAnalyzer.ExtractAPIFromContent(content)
+		if err != nil {
+			return
+		}
+		if pkgName == "main" {
+			isMain = true
+		}
+		for k, v := range api {
+			dst[k] = v
+		}
+	}
+
+	// Unchanged files contribute to both sides; changed files are read at head and at base
+	pkgDir := a.symbolAnalyzer.GetPackageDir(pkgPath)
+	if entries, err := a.fs.ReadDir(pkgDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+			filePath := filepath.Join(pkgDir, entry.Name())
+			content, err := a.fs.ReadFile(filePath)
+			if err != nil {
+				continue
+			}
+			merge(headAPI, content)
+			if _, isChanged := changed[filePath]; !isChanged {
+				merge(baseAPI, content)
+			}
+		}
+	}
+	for _, origPath := range changed {
+		// Files added in head have no base content
+		if content, err := a.config.GitClient.GetFileContentAtBase(origPath); err == nil && len(content) > 0 {
+			merge(baseAPI, content)
+		}
+	}
+
+	if isMain {
+		return nil
+	}
+
+	var changes []APIChange
+	for symbol, after := range headAPI {
+		if _, ok := baseAPI[symbol]; !ok {
+			changes = append(changes, APIChange{Package: pkgPath, Symbol: symbol, Kind: APIChangeAdded, After: after})
+		}
+	}
+	for symbol, before := range baseAPI {
+		after, ok := headAPI[symbol]
+		switch {
+		case !ok:
+			changes = append(changes, APIChange{Package: pkgPath, Symbol: symbol, Kind: APIChangeRemoved, Before: before})
+		case after != before:
+			changes = append(changes, APIChange{Package: pkgPath, Symbol: symbol, Kind: APIChangeChanged, Before: before, After: after})
+		}
+	}
+	if typed {
+		return applyAPIDiff(pkgPath, changes, messages)
+	}
+	for i := range changes {
+		changes[i].Compatible = apiChangeCompatible(changes[i], baseAPI)
+	}
+	return changes
+}
+
+// apiChangeCompatible classifies an API change from the declarations alone, when apidiff cannot type-check
+// the package; without types, doubtful changes are classified as incompatible:
+//   - added symbols are compatible, except fields that may make a comparable struct incomparable
+//   - removed symbols and changed types are incompatible, except methods moving from a pointer
+//     to a value receiver and methods added to interfaces with unexported methods (which
+//     no other package can implement); renamed parameters of interface methods are compatible
+func apiChangeCompatible(c APIChange, baseAPI map[string]string) bool {
+	switch c.Kind {
+	case APIChangeAdded:
+		typeName, _, isMember := strings.Cut(c.Symbol, ".")
+		decl := baseAPI[typeName]
+		isStruct := strings.HasPrefix(decl, "type") && strings.HasSuffix(decl, " struct")
+		if !isMember || strings.HasPrefix(c.After, "func (") || !isStruct || comparableType(c.After) {
+			return true
+		}
+		// Comparing values of the struct (==, map keys) stops compiling, unless it was incomparable already
+		for symbol, fieldType := range baseAPI {
+			if strings.HasPrefix(symbol, typeName+".") && !strings.HasPrefix(fieldType, "func (") && incomparableType(fieldType) {
+				return true
+			}
+		}
+		return false
+	case APIChangeChanged:
+		if strings.HasPrefix(c.Before, "func (*") && strings.HasPrefix(c.After, "func (") {
+			return "func ("+strings.TrimPrefix(c.Before, "func (*") == c.After
+		}
+		return interfaceCompatible(c.Before, c.After)
+	}
+	return false
+}
+
+// comparableTypes are the predeclared types known to be comparable without type information
+var comparableTypes = map[string]bool{
+	"bool": true, "string": true, "error": true, "any": true, "byte": true, "rune": true, "uintptr": true,
+	"int": true, "int8": true, "int16": true, "int32": true, "int64": true,
+	"uint": true, "uint8": true, "uint16": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "complex64": true, "complex128": true,
+}
+
+// comparableType checks if a rendered type is known to be comparable: predeclared types, pointers and channels
+// Named types may be structs with incomparable fields, so they are not
+func comparableType(t string) bool {
+	return comparableTypes[t] || strings.HasPrefix(t, "*") || strings.HasPrefix(t, "chan ") || strings.HasPrefix(t, "<-chan ")
+}
+
+// incomparableType checks if a rendered type is known to be incomparable: slices, maps and functions
+func incomparableType(t string) bool {
+	return strings.HasPrefix(t, "[]") || strings.HasPrefix(t, "map[") || strings.HasPrefix(t, "func(")
+}
+
+// interfaceCompatible checks if an interface type kept its methods, and only gained methods if it has
+// an unexported method, so that adding methods cannot break implementations in other packages
+func interfaceCompatible(before, after string) bool {
+	beforeMethods, beforeParams, ok := interfaceMethods(before)
+	if !ok {
+		return false
+	}
+	afterMethods, afterParams, ok := interfaceMethods(after)
+	if !ok || beforeParams != afterParams {
+		return false
+	}
+
+	sealed := false
+	for name, sig := range beforeMethods {
+		if afterMethods[name] != sig {
+			return false
+		}
+		sealed = sealed || !isExported(name)
+	}
+	return sealed || len(afterMethods) == len(beforeMethods)
+}
+
+// interfaceMethods parses a rendered interface type ("type interface{...}") into its methods and embedded
+// types (keyed by their rendering), along with the rendered type parameters
+func interfaceMethods(rendered string) (map[string]string, string, bool) {
+	rest, ok := strings.CutPrefix(rendered, "type")
+	if !ok {
+		return nil, "", false
+	}
+	params := ""
+	if strings.HasPrefix(rest, "[") {
+		depth := 0
+		for i, r := range rest {
+			if r == '[' {
+				depth++
+			} else if r == ']' {
+				depth--
+			}
+			if depth == 0 {
+				params, rest = rest[:i+1], rest[i+1:]
+				break
+			}
+		}
+	}
+
+	fset := token.NewFileSet()
+	expr, err := parser.ParseExprFrom(fset, "", strings.TrimSpace(rest), 0)
+	if err != nil {
+		return nil, "", false
+	}
+	iface, ok := expr.(*ast.InterfaceType)
+	if !ok {
+		return nil, "", false
+	}
+	methods := make(map[string]string)
+	for _, field := range iface.Methods.List {
+		rendered := renderNode(fset, field.Type)
+		if ft, ok := field.Type.(*ast.FuncType); ok {
+			rendered = renderSignature(fset, ft)
+		}
+		if len(field.Names) == 0 {
+			methods[rendered] = rendered
+		}
+		for _, name := range field.Names {
+			methods[name.Name] = rendered
+		}
+	}
+	return methods, params, true
+}
+
+// MarkBreakingResources flags affected resources that use a symbol with a breaking change
+// Flagged resources are raised to Config.BreakingSeverity, and the resources are sorted again
+func (a *Analyzer) MarkBreakingResources(affected []AffectedResource, changes []APIChange) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	symbolsByPackage := make(map[string][]string)
+	for _, c := range changes {
+		// Methods and fields are reached through their top-level type
+		topLevel, _, _ := strings.Cut(c.Symbol, ".")
+		symbolsByPackage[c.Package] = append(symbolsByPackage[c.Package], topLevel)
+	}
+
+	for i := range affected {
+		for pkgPath, symbols := range symbolsByPackage {
+			info := changedSymbolsInfo{symbols: uniqueStrings(symbols)}
+			if a.isResourceAffectedBySymbols(&affected[i].Resource, pkgPath, info) {
+				affected[i].Breaking = true
+				break
+			}
+		}
+		if affected[i].Breaking && a.config.BreakingSeverity != "" && affected[i].Severity.Rank() < a.config.BreakingSeverity.Rank() {
+			affected[i].Severity = a.config.BreakingSeverity
+		}
+	}
+	SortAffectedResources(affected)
+}
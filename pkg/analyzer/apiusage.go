@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"sort"
+	"strings"
+)
+
+// APISymbolUsage describes the consumers of an exported symbol
+type APISymbolUsage struct {
+	Symbol    string   `json:"symbol"`    // Exported symbol name
+	Count     int      `json:"count"`     // Number of usage sites outside the package
+	Packages  []string `json:"packages"`  // Packages referencing the symbol
+	Resources []string `json:"resources"` // Resources depending on a referencing package
+}
+
+// APIUsageReport lists how the exported API of a package is consumed
+type APIUsageReport struct {
+	Package string           `json:"package"`
+	Symbols []APISymbolUsage `json:"symbols"`
+}
+
+// ExtractPackageLevelSymbolsFromDir extracts exported package-level symbols (no methods) from a directory
+// These are the symbols other packages can reference as pkg.Symbol
+func (s *SymbolAnalyzer) ExtractPackageLevelSymbolsFromDir(pkgDir string) ([]string, error) {
+	var symbols []string
+	err := s.walkExportedSymbols(pkgDir, func(symbol string, _ ...*ast.CommentGroup) {
+		if !strings.Contains(symbol, ".") {
+			symbols = append(symbols, symbol)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uniqueStrings(symbols), nil
+}
+
+// GetAPIUsage reports every exported symbol of a package and which packages and resources consume it
+func (a *Analyzer) GetAPIUsage(pkgPath string) (*APIUsageReport, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.graph.HasPackage(pkgPath) {
+		return nil, fmt.Errorf("package %s not found in dependency graph", pkgPath)
+	}
+
+	symbols, err := a.symbolAnalyzer.ExtractPackageLevelSymbolsFromDir(a.symbolAnalyzer.GetPackageDir(pkgPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to extract symbols: %w", err)
+	}
+
+	// Symbol name -> usage sites across all importers
+	usagesBySymbol := make(map[string][]SymbolUsage)
+	for _, importer := range a.graph.GetImporters(pkgPath) {
+		usages, err := a.symbolAnalyzer.FindSymbolUsages(a.symbolAnalyzer.GetPackageDir(importer), pkgPath, symbols)
+		if err != nil {
+			continue
+		}
+		for sym, u := range usages {
+			usagesBySymbol[sym] = append(usagesBySymbol[sym], u...)
+		}
+	}
+
+	report := &APIUsageReport{
+		Package: pkgPath,
+		Symbols: make([]APISymbolUsage, 0, len(symbols)),
+	}
+
+	for _, sym := range symbols {
+		usage := APISymbolUsage{
+			Symbol:    sym,
+			Count:     len(usagesBySymbol[sym]),
+			Packages:  []string{},
+			Resources: []string{},
+		}
+		for _, u := range usagesBySymbol[sym] {
+			usage.Packages = append(usage.Packages, u.Package)
+			usage.Resources = append(usage.Resources, a.reverseDeps[u.Package]...)
+		}
+		usage.Packages = uniqueStrings(usage.Packages)
+		usage.Resources = uniqueStrings(usage.Resources)
+		sort.Strings(usage.Packages)
+		sort.Strings(usage.Resources)
+
+		report.Symbols = append(report.Symbols, usage)
+	}
+
+	sort.Slice(report.Symbols, func(i, j int) bool {
+		return report.Symbols[i].Symbol < report.Symbols[j].Symbol
+	})
+
+	return report, nil
+}
@@ -0,0 +1,149 @@
+package analyzer_test
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzertest"
+)
+
+// benchSizes are the sizes of the monorepos the benchmarks run on
+var benchSizes = []analyzertest.MonorepoOptions{
+	{Packages: 100, Resources: 10},
+	{Packages: 500, Resources: 50},
+	{Packages: 2000, Resources: 100},
+}
+
+// BenchmarkAnalyze measures Analyze on a monorepo: resource extraction, the dependency graph and its indexes
+func BenchmarkAnalyze(b *testing.B) {
+	for _, opts := range benchSizes {
+		b.Run(opts.String(), benchAnalyze(opts))
+	}
+}
+
+// BenchmarkGetAffectedResources measures the impact analysis of a change to a library package used by resources
+// Each iteration uses a new Analyzer, so that symbols parsed by previous iterations are not reused
+func BenchmarkGetAffectedResources(b *testing.B) {
+	for _, opts := range benchSizes {
+		b.Run(opts.String(), benchGetAffectedResources(opts))
+	}
+}
+
+// benchAnalyze returns the benchmark of Analyze on a monorepo of the given size
+func benchAnalyze(opts analyzertest.MonorepoOptions) func(b *testing.B) {
+	return func(b *testing.B) {
+		m := analyzertest.New(b, opts)
+		a := analyzer.NewAnalyzer(m.Config())
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := a.Analyze(); err != nil {
+				b.Fatalf("Analyze: %v", err)
+			}
+		}
+	}
+}
+
+// benchGetAffectedResources returns the benchmark of GetAffectedResources on a monorepo of the given size
+func benchGetAffectedResources(opts analyzertest.MonorepoOptions) func(b *testing.B) {
+	return func(b *testing.B) {
+		m := analyzertest.New(b, opts)
+		changed := []string{m.UsedLibraryFile(0)}
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			b.StopTimer()
+			a := analyzer.NewAnalyzer(m.Config(changed...))
+			if err := a.Analyze(); err != nil {
+				b.Fatalf("Analyze: %v", err)
+			}
+			b.StartTimer()
+			if affected := a.GetAffectedResources(changed); len(affected) == 0 {
+				b.Fatalf("no resource affected by %s", changed[0])
+			}
+		}
+	}
+}
+
+// updateBaseline rewrites the committed benchmark baseline instead of comparing with it
+var updateBaseline = flag.Bool("update-baseline", false, "rewrite testdata/bench_baseline.json with the current benchmark results")
+
+// baselinePath is the committed baseline of TestBenchmarkBaseline
+var baselinePath = filepath.Join("testdata", "bench_baseline.json")
+
+// baselineTolerance is how far above the baseline a result may go before the gate fails (20%)
+const baselineTolerance = 1.2
+
+// benchResult is the allocation profile of a benchmark, which unlike its duration does not depend on the machine
+type benchResult struct {
+	AllocsPerOp int64 `json:"allocs_per_op"`
+	BytesPerOp  int64 `json:"bytes_per_op"`
+}
+
+// TestBenchmarkBaseline is the performance regression gate: it runs the benchmarks on the mid-sized monorepo and
+// fails if their allocations exceed the committed baseline by more than baselineTolerance
+// Run it with -update-baseline after a change that is expected to cost more, and commit the new baseline
+func TestBenchmarkBaseline(t *testing.T) {
+	if testing.Short() {
+		t.Skip("benchmarks are skipped in short mode")
+	}
+	opts := benchSizes[1]
+	benchmarks := map[string]func(b *testing.B){
+		"Analyze/" + opts.String():              benchAnalyze(opts),
+		"GetAffectedResources/" + opts.String(): benchGetAffectedResources(opts),
+	}
+
+	// Allocations barely vary between iterations: a few are enough, unlike the durations of -bench runs
+	benchtime := flag.Lookup("test.benchtime")
+	previous := benchtime.Value.String()
+	if err := benchtime.Value.Set("10x"); err != nil {
+		t.Fatal(err)
+	}
+	defer benchtime.Value.Set(previous)
+
+	results := make(map[string]benchResult)
+	for name, bench := range benchmarks {
+		r := testing.Benchmark(bench)
+		if r.N == 0 {
+			t.Fatalf("benchmark %s failed", name)
+		}
+		results[name] = benchResult{AllocsPerOp: r.AllocsPerOp(), BytesPerOp: r.AllocedBytesPerOp()}
+	}
+
+	if *updateBaseline {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(baselinePath, append(data, '\n'), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	data, err := os.ReadFile(baselinePath)
+	if err != nil {
+		t.Fatalf("failed to read the baseline (create it with -update-baseline): %v", err)
+	}
+	var baseline map[string]benchResult
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		t.Fatalf("invalid baseline %s: %v", baselinePath, err)
+	}
+	for name, got := range results {
+		want, ok := baseline[name]
+		if !ok {
+			t.Errorf("%s: no baseline (update it with -update-baseline)", name)
+			continue
+		}
+		if float64(got.AllocsPerOp) > float64(want.AllocsPerOp)*baselineTolerance {
+			t.Errorf("%s: %d allocs/op, baseline %d", name, got.AllocsPerOp, want.AllocsPerOp)
+		}
+		if float64(got.BytesPerOp) > float64(want.BytesPerOp)*baselineTolerance {
+			t.Errorf("%s: %d B/op, baseline %d", name, got.BytesPerOp, want.BytesPerOp)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// ImportRule forbids packages matching From from importing packages matching Deny
+// Patterns are globs over module-relative package paths (e.g., "job/**" must not import "api/**")
+type ImportRule struct {
+	From string   `json:"from"`
+	Deny []string `json:"deny"`
+}
+
+// ImportViolation is a direct import that breaks an ImportRule
+type ImportViolation struct {
+	Package string `json:"package"`
+	Import  string `json:"import"`
+	// Rule describes the broken rule (e.g., "job/** must not import api/**")
+	Rule string `json:"rule"`
+}
+
+// checkImportRules evaluates the import rules against the dependency graph
+func (a *Analyzer) checkImportRules() []ImportViolation {
+	var violations []ImportViolation
+	if len(a.config.ImportRules) == 0 {
+		return violations
+	}
+
+	packages := a.graph.GetAllPackages()
+	sort.Strings(packages)
+
+	for _, pkgPath := range packages {
+		relPkg := a.relPackagePath(pkgPath)
+		for _, rule := range a.config.ImportRules {
+			if !matchGlob(rule.From, relPkg) {
+				continue
+			}
+			for _, dep := range a.graph.GetDirectDeps(pkgPath) {
+				relDep := a.relPackagePath(dep)
+				for _, deny := range rule.Deny {
+					if matchGlob(deny, relDep) {
+						violations = append(violations, ImportViolation{
+							Package: pkgPath,
+							Import:  dep,
+							Rule:    rule.From + " must not import " + deny,
+						})
+						break
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Package != violations[j].Package {
+			return violations[i].Package < violations[j].Package
+		}
+		return violations[i].Import < violations[j].Import
+	})
+
+	return violations
+}
+
+// relPackagePath returns the package path relative to the module path
+func (a *Analyzer) relPackagePath(pkgPath string) string {
+	if pkgPath == a.config.ModulePath {
+		return "."
+	}
+	return strings.TrimPrefix(pkgPath, a.config.ModulePath+"/")
+}
+
+// GetImportViolations returns the import-boundary violations found during Analyze
+func (a *Analyzer) GetImportViolations() []ImportViolation {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.importViolations
+}
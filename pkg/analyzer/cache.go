@@ -0,0 +1,49 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+// Cache stores the exported symbols of Go files by file content
+// Sharing a cache lets Analyzers re-created for each request skip parsing unchanged files
+// Implementations must be safe for concurrent use
+type Cache interface {
+	// Get returns the symbols stored for a key
+	Get(key string) ([]string, bool)
+	// Set stores the symbols for a key
+	Set(key string, symbols []string)
+}
+
+// memoryCache is a Cache kept in memory without eviction
+type memoryCache struct {
+	mu      sync.RWMutex
+	entries map[string][]string
+}
+
+// NewMemoryCache creates a Cache kept in memory
+func NewMemoryCache() Cache {
+	return &memoryCache{entries: make(map[string][]string)}
+}
+
+// Get returns the symbols stored for a key
+func (c *memoryCache) Get(key string) ([]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	symbols, ok := c.entries[key]
+	return symbols, ok
+}
+
+// Set stores the symbols for a key
+func (c *memoryCache) Set(key string, symbols []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = symbols
+}
+
+// contentKey returns the cache key of a file content
+func contentKey(kind string, src []byte) string {
+	sum := sha256.Sum256(src)
+	return kind + ":" + hex.EncodeToString(sum[:])
+}
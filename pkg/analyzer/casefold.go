@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// caseCollisions returns a warning for each group of packages whose paths differ only by case
+// Their directories are the same on case-insensitive filesystems (macOS, Windows), so that they cannot
+// be checked out side by side there and their files are mistaken for each other
+func (g *DependencyGraph) caseCollisions() []*AnalysisError {
+	var warnings []*AnalysisError
+	for _, pkgPaths := range g.folded {
+		if len(pkgPaths) < 2 {
+			continue
+		}
+		pkgPaths = append([]string{}, pkgPaths...)
+		sort.Strings(pkgPaths)
+		warnings = append(warnings, &AnalysisError{
+			Kind:    ErrCaseCollision,
+			Package: pkgPaths[0],
+			Err:     fmt.Errorf("differs only by case from %s", strings.Join(pkgPaths[1:], ", ")),
+		})
+	}
+	return warnings
+}
+
+// packageCase returns the package of the graph a package path derived from a file path designates
+// when the filesystem ignores case: git and the user may spell a directory differently than go list
+// (e.g., after a directory was renamed by case only). Ambiguous and unknown paths are returned as is
+func (g *DependencyGraph) packageCase(pkgPath string) string {
+	if g.HasPackage(pkgPath) {
+		return pkgPath
+	}
+	if pkgPaths := g.folded[strings.ToLower(pkgPath)]; len(pkgPaths) == 1 {
+		return pkgPaths[0]
+	}
+	return pkgPath
+}
+
+// isCaseInsensitiveDir reports whether the filesystem of a directory ignores case,
+// by checking whether the directory is found under a name with a toggled case
+func isCaseInsensitiveDir(fsys FileSystem, dir string) bool {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	for d := absDir; filepath.Dir(d) != d; d = filepath.Dir(d) {
+		toggled := toggleCase(filepath.Base(d))
+		if toggled == filepath.Base(d) {
+			continue
+		}
+		info, err := fsys.Stat(d)
+		if err != nil {
+			return false
+		}
+		toggledInfo, err := fsys.Stat(filepath.Join(filepath.Dir(d), toggled))
+		return err == nil && os.SameFile(info, toggledInfo)
+	}
+	return false
+}
+
+// toggleCase swaps the case of the first letter of a name
+func toggleCase(name string) string {
+	for i, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			return name[:i] + string(unicode.ToLower(r)) + name[i+len(string(r)):]
+		case unicode.IsLower(r):
+			return name[:i] + string(unicode.ToUpper(r)) + name[i+len(string(r)):]
+		}
+	}
+	return name
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import "sort"
+
+// ChainLink is an edge of a dependency chain with the symbols carrying the impact across it
+type ChainLink struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	// Symbols are the affected symbols of To used by From ("Interface.Method" for interface methods)
+	// Empty when the usage could not be traced (e.g., side effects or whole-package changes)
+	Symbols []string `json:"symbols,omitempty"`
+	// Verified reports that the impact propagates through a symbol usage rather than the import alone
+	Verified bool `json:"verified"`
+}
+
+// annotateChains sets the chain links of the affected resources from the changed symbols of their affected package
+func (a *Analyzer) annotateChains(affected []AffectedResource, changes map[string]*packageChanges) {
+	forEachParallel(a.config.Concurrency, len(affected), func(i int) {
+		r := &affected[i]
+		if len(r.DependencyChain) < 2 {
+			return
+		}
+		var info changedSymbolsInfo
+		if pc := changes[r.AffectedPackage]; pc != nil {
+			info = pc.info
+		}
+		r.ChainLinks = a.chainLinks(r.DependencyChain, info)
+	})
+}
+
+// chainLinks follows the chain from the changed package back to the resource, like the intermediate importer checks:
+// each package uses some of the symbols carried by the next one, and carries its own symbols depending on them
+func (a *Analyzer) chainLinks(chain []string, info changedSymbolsInfo) []ChainLink {
+	links := make([]ChainLink, len(chain)-1)
+	symbols := info.symbols
+	methods := info.interfaceMethods
+	for k := len(chain) - 2; k >= 0; k-- {
+		from, to := chain[k], chain[k+1]
+		links[k] = ChainLink{From: from, To: to}
+		if len(symbols) == 0 && len(methods) == 0 {
+			continue
+		}
+
+		fromDir := a.symbolAnalyzer.GetPackageDir(from)
+		var usedSymbols []string
+		for _, sym := range symbols {
+			if uses, _ := a.symbolAnalyzer.CheckSymbolUsage(fromDir, to, []string{sym}); uses {
+				usedSymbols = append(usedSymbols, sym)
+			}
+		}
+		var usedMethods []InterfaceMethodRange
+		for _, m := range methods {
+			if uses, _ := a.symbolAnalyzer.CheckMethodCallUsage(fromDir, to, []InterfaceMethodRange{m}); uses {
+				usedMethods = append(usedMethods, m)
+			}
+		}
+
+		names := append([]string(nil), usedSymbols...)
+		for _, m := range usedMethods {
+			names = append(names, m.InterfaceName+"."+m.MethodName)
+		}
+		sort.Strings(names)
+		links[k].Symbols = uniqueStrings(names)
+		links[k].Verified = len(names) > 0
+
+		// Symbols of the importer carry the impact to the next hop
+		symbols, methods = nil, nil
+		if k > 0 {
+			if len(usedSymbols) > 0 {
+				symbols = a.getAffectedExportedSymbols(from, to, usedSymbols)
+			}
+			if len(usedMethods) > 0 {
+				symbols = uniqueStrings(append(symbols, a.getAffectedExportedSymbolsByMethods(from, to, usedMethods)...))
+			}
+		}
+	}
+	return links
+}
+
+// decayConfidence lowers the confidence of resources by one level for every ConfidenceDecayHops unverified links
+func (a *Analyzer) decayConfidence(affected []AffectedResource) {
+	if a.config.ConfidenceDecayHops <= 0 {
+		return
+	}
+	for i := range affected {
+		unverified := 0
+		for _, link := range affected[i].ChainLinks {
+			if !link.Verified {
+				unverified++
+			}
+		}
+		affected[i].Confidence = affected[i].Confidence.lower(unverified / a.config.ConfidenceDecayHops)
+	}
+}
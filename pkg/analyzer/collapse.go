@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// normalizeCollapsePrefixes returns the collapse prefixes as full package paths without a trailing "/..."
+// Prefixes outside the module (e.g., "internal/db") are relative to the module path
+func normalizeCollapsePrefixes(modulePath string, prefixes []string) []string {
+	result := make([]string, 0, len(prefixes))
+	for _, prefix := range prefixes {
+		prefix = strings.TrimSuffix(strings.TrimSuffix(prefix, "..."), "/")
+		if prefix != modulePath && !strings.HasPrefix(prefix, modulePath+"/") {
+			prefix = modulePath + "/" + prefix
+		}
+		result = append(result, prefix)
+	}
+	return result
+}
+
+// collapsePackage returns the node shown for a package: "<prefix>/..." for the longest collapse prefix
+// containing the package, or the package itself
+func (a *Analyzer) collapsePackage(pkgPath string) string {
+	longest := ""
+	for _, prefix := range a.config.CollapsePrefixes {
+		if (pkgPath == prefix || strings.HasPrefix(pkgPath, prefix+"/")) && len(prefix) > len(longest) {
+			longest = prefix
+		}
+	}
+	if longest == "" {
+		return pkgPath
+	}
+	return longest + "/..."
+}
+
+// collapseChain replaces the packages of a dependency chain by their nodes
+// Consecutive packages collapsed into the same node become a single hop
+func (a *Analyzer) collapseChain(chain []string) []string {
+	if len(a.config.CollapsePrefixes) == 0 {
+		return chain
+	}
+	result := make([]string, 0, len(chain))
+	for _, pkgPath := range chain {
+		node := a.collapsePackage(pkgPath)
+		if len(result) > 0 && result[len(result)-1] == node {
+			continue
+		}
+		result = append(result, node)
+	}
+	return result
+}
+
+// collapseAffectedResources collapses the dependency chains of affected resources and their links
+// Links within a node are dropped
+func (a *Analyzer) collapseAffectedResources(affected []AffectedResource) []AffectedResource {
+	if len(a.config.CollapsePrefixes) == 0 {
+		return affected
+	}
+	for i := range affected {
+		affected[i].DependencyChain = a.collapseChain(affected[i].DependencyChain)
+		var links []ChainLink
+		for _, link := range affected[i].ChainLinks {
+			link.From, link.To = a.collapsePackage(link.From), a.collapsePackage(link.To)
+			if link.From != link.To {
+				links = append(links, link)
+			}
+		}
+		affected[i].ChainLinks = links
+	}
+	return affected
+}
+
+// collapseGraph merges the nodes of collapsed packages and their edges
+// Imports between packages of the same node are dropped
+func (a *Analyzer) collapseGraph(export *GraphExport) *GraphExport {
+	if len(a.config.CollapsePrefixes) == 0 {
+		return export
+	}
+
+	collapsed := &GraphExport{
+		Module:    export.Module,
+		Nodes:     []GraphNode{},
+		Edges:     a.collapseEdges(export.Edges),
+		TestEdges: a.collapseEdges(export.TestEdges),
+		ToolEdges: a.collapseEdges(export.ToolEdges),
+		Resources: export.Resources,
+	}
+
+	index := make(map[string]int)
+	for _, node := range export.Nodes {
+		name := a.collapsePackage(node.Package)
+		i, ok := index[name]
+		if !ok {
+			i = len(collapsed.Nodes)
+			index[name] = i
+			collapsed.Nodes = append(collapsed.Nodes, GraphNode{Package: name})
+		}
+		collapsed.Nodes[i].Resources = append(collapsed.Nodes[i].Resources, node.Resources...)
+	}
+	for i := range collapsed.Nodes {
+		sort.Strings(collapsed.Nodes[i].Resources)
+	}
+	sort.Slice(collapsed.Nodes, func(i, j int) bool {
+		return collapsed.Nodes[i].Package < collapsed.Nodes[j].Package
+	})
+	return collapsed
+}
+
+// collapseEdges maps edges to collapsed nodes, dropping duplicates and edges within a node
+func (a *Analyzer) collapseEdges(edges []GraphEdge) []GraphEdge {
+	if edges == nil {
+		return nil
+	}
+	result := []GraphEdge{}
+	seen := make(map[GraphEdge]bool)
+	for _, edge := range edges {
+		collapsed := GraphEdge{From: a.collapsePackage(edge.From), To: a.collapsePackage(edge.To)}
+		if collapsed.From == collapsed.To || seen[collapsed] {
+			continue
+		}
+		seen[collapsed] = true
+		result = append(result, collapsed)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].From != result[j].From {
+			return result[i].From < result[j].From
+		}
+		return result[i].To < result[j].To
+	})
+	return result
+}
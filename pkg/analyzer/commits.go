@@ -0,0 +1,69 @@
+package analyzer
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Commit is a commit of the history of the project
+type Commit struct {
+	Hash string
+	// Parent is the first parent of the commit
+	Parent  string
+	Subject string
+	Body    string
+}
+
+// FirstParentCommits returns the first-parent commits of the range from..to, oldest first
+// Each is a change as merged into the branch (a merge commit or a squashed pull request); the root
+// commit is left out since it has no parent to compare to
+func FirstParentCommits(projectDir, from, to string) ([]Commit, error) {
+	cmd := exec.Command("git", "log", "--first-parent", "--reverse", "--format=%H%x00%P%x00%s%x00%b%x1e", from+".."+to)
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the commits of %s..%s: %w", from, to, gitError(err))
+	}
+
+	var commits []Commit
+	for _, record := range strings.Split(string(out), "\x1e") {
+		fields := strings.SplitN(strings.TrimLeft(record, "\n"), "\x00", 4)
+		if len(fields) != 4 {
+			continue
+		}
+		parents := strings.Fields(fields[1])
+		if len(parents) == 0 {
+			continue
+		}
+		commits = append(commits, Commit{Hash: fields[0], Parent: parents[0], Subject: fields[2], Body: strings.TrimSpace(fields[3])})
+	}
+	return commits, nil
+}
+
+// ChangedFilesBetween returns the files changed between two commits (e.g., a commit and its parent),
+// relative to the git root; paths matching the excludes are left out as with NewGitClientWithExcludes
+func ChangedFilesBetween(projectDir, from, to string, excludes []string) ([]string, error) {
+	g := &execGitClient{projectDir: projectDir, excludes: excludes}
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.Command("git", append([]string{"diff", "--name-only", from, to}, g.excludePathspecs()...)...)
+	cmd.Dir = gitRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, gitError(err)
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		files = append(files, line)
+	}
+	return files, nil
+}
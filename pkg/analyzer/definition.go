@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// addDefinitionChangedResources adds resources whose definition in the command directory changed
+// (description, RunE wiring, flags, ...) or that are new: their fingerprint differs from the base
+// Resources already found by code analysis are kept as is
+func (a *Analyzer) addDefinitionChangedResources(changedFiles []string, affectedMap map[string]*AffectedResource, warnings *warningCollector) {
+	if a.commands == nil {
+		return
+	}
+	cmdDir := filepath.Join(a.config.ProjectRoot, a.config.CmdDir)
+
+	// Files removed from the command directory only exist at the base
+	files := append([]string{}, a.commands.files...)
+	changed := false
+	for _, file := range changedFiles {
+		absPath := a.toAbsPath(file)
+		if filepath.Dir(absPath) != cmdDir || !strings.HasSuffix(absPath, ".go") || strings.HasSuffix(absPath, "_test.go") {
+			continue
+		}
+		changed = true
+		if !contains(files, absPath) {
+			files = append(files, absPath)
+		}
+	}
+	if !changed {
+		return
+	}
+
+	baseFingerprints, err := a.baseDefinitionFingerprints(files)
+	if err != nil {
+		warnings.add(ErrGitDiff, a.config.CmdDir, err)
+		return
+	}
+
+	for _, node := range a.commands.nodes {
+		if node.index < 0 {
+			continue
+		}
+		name := a.resources[node.index].Name
+		reason := "definition changed"
+		if fingerprint, ok := baseFingerprints[name]; !ok {
+			reason = "definition changed (new command)"
+		} else if fingerprint == node.fingerprint {
+			continue
+		}
+		if _, exists := affectedMap[name]; exists {
+			continue
+		}
+		resource := a.getResourceByName(name)
+		if resource == nil {
+			continue
+		}
+		affectedMap[name] = &AffectedResource{
+			Resource:        *resource,
+			Reason:          fmt.Sprintf("%s in %s", reason, a.projectRelPath(node.command.SourceFile)),
+			DependencyChain: []string{},
+			Confidence:      ConfidenceHigh,
+		}
+	}
+}
+
+// baseDefinitionFingerprints extracts the commands of the files at the base and returns their fingerprints
+// by resource name (after aliasing)
+func (a *Analyzer) baseDefinitionFingerprints(files []string) (map[string]string, error) {
+	var readErr error
+	read := func(path string) ([]byte, error) {
+		content, err := a.config.GitClient.GetFileContentAtBase(path)
+		if err == nil {
+			return content, nil
+		}
+		// Files added since the base are expected to be missing; other failures make the comparison unreliable
+		if isNew, newErr := a.config.GitClient.IsNewFile(path); newErr != nil || !isNew {
+			if readErr == nil {
+				readErr = fmt.Errorf("failed to read %s at base: %w", path, err)
+			}
+		}
+		return nil, err
+	}
+
+	resources, tree := a.extractor.extractCommandFiles(token.NewFileSet(), files, read)
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	fingerprints := make(map[string]string)
+	for _, node := range tree.nodes {
+		if node.index < 0 {
+			continue
+		}
+		name := resources[node.index].Name
+		if alias, ok := a.config.Aliases[name]; ok {
+			name = alias
+		}
+		fingerprints[name] = node.fingerprint
+	}
+	return fingerprints, nil
+}
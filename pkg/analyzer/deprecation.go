@@ -0,0 +1,290 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SymbolUsage represents a location where a symbol is referenced
+type SymbolUsage struct {
+	Package string `json:"package"` // Package containing the usage
+	File    string `json:"file"`    // File path relative to the project root
+	Line    int    `json:"line"`    // Line number of the usage
+}
+
+// DeprecatedSymbolReport lists the remaining usages of a deprecated symbol
+type DeprecatedSymbolReport struct {
+	Symbol    string        `json:"symbol"`    // Fully qualified symbol (pkg.Symbol)
+	Resources []string      `json:"resources"` // Resources that still depend on a usage site
+	Usages    []SymbolUsage `json:"usages"`    // Usage sites outside the defining package
+}
+
+// FindDeprecatedSymbols returns exported symbols in a directory whose doc comment has a "Deprecated:" paragraph
+// Methods of exported types are named Type.Method
+func (s *SymbolAnalyzer) FindDeprecatedSymbols(pkgDir string) ([]string, error) {
+	var symbols []string
+	err := s.walkExportedSymbols(pkgDir, func(symbol string, docs ...*ast.CommentGroup) {
+		for _, doc := range docs {
+			if isDeprecated(doc) {
+				symbols = append(symbols, symbol)
+				return
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return uniqueStrings(symbols), nil
+}
+
+// walkExportedSymbols calls fn for each exported symbol declared in the non-test files of a directory,
+// with the doc comments describing it (the comment of a declaration group applies to its only spec)
+// Methods of exported types are named Type.Method
+func (s *SymbolAnalyzer) walkExportedSymbols(pkgDir string, fn func(symbol string, docs ...*ast.CommentGroup)) error {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, entry.Name())
+		file, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.FuncDecl:
+				if !isExported(d.Name.Name) {
+					continue
+				}
+				if d.Recv == nil {
+					fn(d.Name.Name, d.Doc)
+				} else if len(d.Recv.List) > 0 {
+					if typeName := extractTypeName(d.Recv.List[0].Type); isExported(typeName) {
+						fn(typeName+"."+d.Name.Name, d.Doc)
+					}
+				}
+			case *ast.GenDecl:
+				var groupDoc *ast.CommentGroup
+				if len(d.Specs) == 1 {
+					groupDoc = d.Doc
+				}
+				for _, spec := range d.Specs {
+					switch sp := spec.(type) {
+					case *ast.TypeSpec:
+						if isExported(sp.Name.Name) {
+							fn(sp.Name.Name, sp.Doc, groupDoc)
+						}
+					case *ast.ValueSpec:
+						for _, name := range sp.Names {
+							if isExported(name.Name) {
+								fn(name.Name, sp.Doc, groupDoc)
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// isDeprecated checks if a doc comment contains a "Deprecated:" paragraph
+func isDeprecated(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if strings.HasPrefix(line, "Deprecated:") {
+			return true
+		}
+	}
+	return false
+}
+
+// FindSymbolUsages returns the locations where a package references symbols of the target package
+// The result maps each symbol name to its usage sites. Methods (Type.Method) are matched by name on any value
+// of the files importing the target package, as the type of the receiver is unknown without type checking,
+// unless they are written as method expressions (pkg.Type.Method)
+func (s *SymbolAnalyzer) FindSymbolUsages(pkgDir string, targetPkgPath string, symbols []string) (map[string][]SymbolUsage, error) {
+	result := make(map[string][]SymbolUsage)
+	if len(symbols) == 0 {
+		return result, nil
+	}
+
+	symbolSet := make(map[string]bool)
+	// Method name -> Type.Method symbols
+	methods := make(map[string][]string)
+	for _, sym := range symbols {
+		symbolSet[sym] = true
+		if _, method, ok := strings.Cut(sym, "."); ok {
+			methods[method] = append(methods[method], sym)
+		}
+	}
+
+	targetPkgName := filepath.Base(targetPkgPath)
+
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, entry.Name())
+		file, err := parser.ParseFile(s.fset, filePath, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		// Find the import alias for the target package
+		importAlias := ""
+		for _, imp := range file.Imports {
+			impPath := strings.Trim(imp.Path.Value, `"`)
+			if impPath == targetPkgPath {
+				if imp.Name != nil {
+					importAlias = imp.Name.Name
+				} else {
+					importAlias = targetPkgName
+				}
+				break
+			}
+		}
+
+		if importAlias == "" || importAlias == "_" {
+			continue
+		}
+
+		relPath, err := filepath.Rel(s.projectDir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
+
+		record := func(symbol string, pos token.Pos) {
+			result[symbol] = append(result[symbol], SymbolUsage{
+				Package: s.FileToPackagePath(filePath),
+				File:    filepath.ToSlash(relPath),
+				Line:    s.fset.Position(pos).Line,
+			})
+		}
+
+		// Dot imports make symbols accessible without a qualifier
+		if importAlias == "." {
+			for _, ident := range findDotImportedUses(file, symbolSet) {
+				record(ident.Name, ident.Pos())
+			}
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+
+			switch x := sel.X.(type) {
+			case *ast.Ident:
+				if x.Name == importAlias {
+					if symbolSet[sel.Sel.Name] {
+						record(sel.Sel.Name, sel.Pos())
+					}
+					return true
+				}
+			case *ast.SelectorExpr:
+				// Method expression (pkg.Type.Method)
+				if ident, ok := x.X.(*ast.Ident); ok && ident.Name == importAlias {
+					if symbol := x.Sel.Name + "." + sel.Sel.Name; symbolSet[symbol] {
+						record(symbol, sel.Pos())
+						return true
+					}
+				}
+			}
+			for _, symbol := range methods[sel.Sel.Name] {
+				record(symbol, sel.Pos())
+			}
+			return true
+		})
+	}
+
+	return result, nil
+}
+
+// FindDeprecatedUsages reports resources that still reference deprecated symbols
+// If refs is empty, symbols marked with a "// Deprecated:" comment anywhere in the project are used
+func (a *Analyzer) FindDeprecatedUsages(refs []SymbolRef) []DeprecatedSymbolReport {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	// Package path -> deprecated symbol names
+	symbolsByPackage := make(map[string][]string)
+	if len(refs) > 0 {
+		for _, ref := range refs {
+			symbol := ref.Symbol
+			if ref.Method != "" {
+				symbol += "." + ref.Method
+			}
+			symbolsByPackage[ref.Package] = append(symbolsByPackage[ref.Package], symbol)
+		}
+	} else {
+		for _, pkgPath := range a.graph.GetAllPackages() {
+			symbols, err := a.symbolAnalyzer.FindDeprecatedSymbols(a.symbolAnalyzer.GetPackageDir(pkgPath))
+			if err != nil || len(symbols) == 0 {
+				continue
+			}
+			symbolsByPackage[pkgPath] = symbols
+		}
+	}
+
+	var reports []DeprecatedSymbolReport
+
+	for pkgPath, symbols := range symbolsByPackage {
+		// Symbol name -> usage sites across all importers
+		usagesBySymbol := make(map[string][]SymbolUsage)
+		for _, importer := range a.graph.GetImporters(pkgPath) {
+			usages, err := a.symbolAnalyzer.FindSymbolUsages(a.symbolAnalyzer.GetPackageDir(importer), pkgPath, symbols)
+			if err != nil {
+				continue
+			}
+			for sym, u := range usages {
+				usagesBySymbol[sym] = append(usagesBySymbol[sym], u...)
+			}
+		}
+
+		for _, sym := range uniqueStrings(symbols) {
+			report := DeprecatedSymbolReport{
+				Symbol:    pkgPath + "." + sym,
+				Resources: []string{},
+				Usages:    usagesBySymbol[sym],
+			}
+			if report.Usages == nil {
+				report.Usages = []SymbolUsage{}
+			}
+
+			// Resources depending on any package with a usage site still reference the symbol
+			for _, usage := range report.Usages {
+				report.Resources = append(report.Resources, a.reverseDeps[usage.Package]...)
+			}
+			report.Resources = uniqueStrings(report.Resources)
+			sort.Strings(report.Resources)
+
+			reports = append(reports, report)
+		}
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Symbol < reports[j].Symbol
+	})
+	return reports
+}
@@ -0,0 +1,78 @@
+package analyzer
+
+import "sort"
+
+// ResourceDependencyDiff lists the project packages a resource started or stopped depending on
+type ResourceDependencyDiff struct {
+	Resource string   `json:"resource"`
+	Added    []string `json:"added"`
+	Removed  []string `json:"removed"`
+}
+
+// ResourceDependencies returns the project packages each resource depends on (including its entry package)
+func (a *Analyzer) ResourceDependencies() map[string][]string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	result := make(map[string][]string)
+	for _, r := range a.resources {
+		if r.Package == "" {
+			result[r.Name] = []string{}
+			continue
+		}
+		deps := uniqueStrings(append([]string{r.Package}, a.graph.GetAllDeps(r.Package)...))
+		sort.Strings(deps)
+		result[r.Name] = deps
+	}
+	return result
+}
+
+// DiffResourceDependencies compares the dependency sets of resources between two analyses
+// Resources whose dependencies did not change are omitted
+func DiffResourceDependencies(base, head *Analyzer) []ResourceDependencyDiff {
+	baseDeps := base.ResourceDependencies()
+	headDeps := head.ResourceDependencies()
+
+	names := make(map[string]bool)
+	for name := range baseDeps {
+		names[name] = true
+	}
+	for name := range headDeps {
+		names[name] = true
+	}
+
+	var diffs []ResourceDependencyDiff
+	for name := range names {
+		added := subtractStrings(headDeps[name], baseDeps[name])
+		removed := subtractStrings(baseDeps[name], headDeps[name])
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+		diffs = append(diffs, ResourceDependencyDiff{
+			Resource: name,
+			Added:    added,
+			Removed:  removed,
+		})
+	}
+
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].Resource < diffs[j].Resource
+	})
+
+	return diffs
+}
+
+// subtractStrings returns the elements of a that are not in b, preserving order
+func subtractStrings(a, b []string) []string {
+	exclude := make(map[string]bool, len(b))
+	for _, s := range b {
+		exclude[s] = true
+	}
+	result := []string{}
+	for _, s := range a {
+		if !exclude[s] {
+			result = append(result, s)
+		}
+	}
+	return result
+}
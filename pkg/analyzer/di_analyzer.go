@@ -72,7 +72,7 @@ func (d *DIAnalyzer) AnalyzeDIUsage(pkgDir string) (*DIUsageInfo, error) {
 
 		filePath := filepath.Join(pkgDir, entry.Name())
 		file, err := parser.ParseFile(fset, filePath, nil, parser.ParseComments)
-		if err != nil {
+		if err != nil || isToolsFile(file) {
 			continue
 		}
 
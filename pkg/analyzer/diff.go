@@ -0,0 +1,187 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// DiffAnalyzer analyzes git diff to extract changed line information
+type DiffAnalyzer struct {
+	projectDir string
+	baseBranch string
+	gitClient  GitClient
+}
+
+// NewDiffAnalyzer creates a new DiffAnalyzer
+func NewDiffAnalyzer(projectDir, baseBranch string) *DiffAnalyzer {
+	return &DiffAnalyzer{
+		projectDir: projectDir,
+		baseBranch: baseBranch,
+		gitClient:  NewGitClient(projectDir, baseBranch),
+	}
+}
+
+// NewDiffAnalyzerWithClient creates a new DiffAnalyzer with a custom GitClient
+func NewDiffAnalyzerWithClient(projectDir, baseBranch string, gitClient GitClient) *DiffAnalyzer {
+	return &DiffAnalyzer{
+		projectDir: projectDir,
+		baseBranch: baseBranch,
+		gitClient:  gitClient,
+	}
+}
+
+// FileChanges represents changes in a single file
+type FileChanges struct {
+	FilePath     string
+	ChangedLines []int // Line numbers that were added or modified
+}
+
+// DiffResult contains both added and deleted line information
+type DiffResult struct {
+	AddedLines   []int // Line numbers in the new file that were added/modified
+	DeletedLines []int // Line numbers in the old file that were deleted
+}
+
+// GetChangedLines extracts changed line numbers for a specific file using git diff
+func (d *DiffAnalyzer) GetChangedLines(filePath string) ([]int, error) {
+	return d.gitClient.GetChangedLines(filePath)
+}
+
+// GetChangedLinesWithDeleted extracts both added and deleted line numbers for a specific file
+func (d *DiffAnalyzer) GetChangedLinesWithDeleted(filePath string) (*DiffResult, error) {
+	return d.gitClient.GetChangedLinesWithDeleted(filePath)
+}
+
+// parseUnifiedDiff parses unified diff output and extracts added/modified line numbers
+func parseUnifiedDiff(diffOutput string) ([]int, error) {
+	result, err := parseUnifiedDiffWithDeleted(diffOutput)
+	if err != nil {
+		return nil, err
+	}
+	return result.AddedLines, nil
+}
+
+// hunkHeaderRegex matches hunk headers: @@ -old_start[,old_count] +new_start[,new_count] @@ [section]
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@`)
+
+// errBinaryDiff is returned for binary files, whose diffs have no lines
+var errBinaryDiff = errors.New("binary file: no line-level diff")
+
+// parseUnifiedDiffWithDeleted parses unified diff output and extracts both added and deleted line numbers
+// Hunks are read by their line counts, so that removed or added lines looking like headers (e.g., "--- a")
+// are not taken for them; "\ No newline at end of file" markers, mode changes and renames carry no lines
+// Malformed or truncated diffs and binary files return an error instead of partial lines
+func parseUnifiedDiffWithDeleted(diffOutput string) (*DiffResult, error) {
+	result := &DiffResult{}
+
+	lines := strings.Split(diffOutput, "\n")
+	// The final newline ends the last line rather than starting an empty one
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	// Line numbers of the next old and new lines, and the lines left in the current hunk
+	var oldLine, newLine, oldLeft, newLeft int
+	for i, line := range lines {
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			// Some tools strip the space of empty context lines
+			case line == "" || line[0] == ' ':
+				if oldLeft == 0 || newLeft == 0 {
+					return nil, fmt.Errorf("malformed diff: line %d: context line beyond the hunk", i+1)
+				}
+				oldLine++
+				newLine++
+				oldLeft--
+				newLeft--
+			case line[0] == '+':
+				if newLeft == 0 {
+					return nil, fmt.Errorf("malformed diff: line %d: added line beyond the hunk", i+1)
+				}
+				result.AddedLines = append(result.AddedLines, newLine)
+				newLine++
+				newLeft--
+			case line[0] == '-':
+				if oldLeft == 0 {
+					return nil, fmt.Errorf("malformed diff: line %d: removed line beyond the hunk", i+1)
+				}
+				result.DeletedLines = append(result.DeletedLines, oldLine)
+				oldLine++
+				oldLeft--
+			case line[0] == '\\':
+				// "\ No newline at end of file" annotates the previous line
+			default:
+				return nil, fmt.Errorf("malformed diff: line %d: unexpected %q in a hunk", i+1, truncateDiffLine(line))
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			matches := hunkHeaderRegex.FindStringSubmatch(line)
+			if matches == nil {
+				return nil, fmt.Errorf("malformed diff: line %d: invalid hunk header %q", i+1, truncateDiffLine(line))
+			}
+			var err error
+			if oldLine, oldLeft, err = hunkRange(matches[1], matches[2]); err != nil {
+				return nil, fmt.Errorf("malformed diff: line %d: %w", i+1, err)
+			}
+			if newLine, newLeft, err = hunkRange(matches[3], matches[4]); err != nil {
+				return nil, fmt.Errorf("malformed diff: line %d: %w", i+1, err)
+			}
+		case strings.HasPrefix(line, "Binary files ") || line == "GIT binary patch":
+			return nil, errBinaryDiff
+		case strings.HasPrefix(line, "\\"):
+			// "\ No newline at end of file" after the last line of a hunk
+		}
+		// Other lines are file headers: diff --git, index, ---/+++, old/new mode, rename from/to, similarity index, ...
+	}
+	if oldLeft > 0 || newLeft > 0 {
+		return nil, errors.New("malformed diff: truncated hunk")
+	}
+
+	return result, nil
+}
+
+// hunkRange parses the start and line count of a hunk range (the count defaults to 1)
+func hunkRange(start, count string) (int, int, error) {
+	first, err := strconv.Atoi(start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid hunk start %q", start)
+	}
+	n := 1
+	if count != "" {
+		if n, err = strconv.Atoi(count); err != nil {
+			return 0, 0, fmt.Errorf("invalid hunk line count %q", count)
+		}
+	}
+	return first, n, nil
+}
+
+// truncateDiffLine shortens a diff line quoted in an error
+func truncateDiffLine(line string) string {
+	if len(line) > 40 {
+		return line[:40] + "..."
+	}
+	return line
+}
+
+// GetAllChangedLines returns changed lines for multiple files
+func (d *DiffAnalyzer) GetAllChangedLines(filePaths []string) (map[string][]int, error) {
+	result := make(map[string][]int)
+
+	for _, path := range filePaths {
+		lines, err := d.GetChangedLines(path)
+		if err != nil {
+			continue
+		}
+		if len(lines) > 0 {
+			result[path] = lines
+		}
+	}
+
+	return result, nil
+}
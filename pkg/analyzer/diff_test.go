@@ -0,0 +1,84 @@
+package analyzer
+
+import (
+	"strings"
+	"testing"
+)
+
+// FuzzParseUnifiedDiff checks that the diff parser never panics, and that the lines it returns are those
+// of the "+" and "-" lines of the diff
+func FuzzParseUnifiedDiff(f *testing.F) {
+	// Changed last line without a newline at the end of the file
+	f.Add(`diff --git a/main.go b/main.go
+index 3b18e51..a042389 100644
+--- a/main.go
++++ b/main.go
+@@ -1,3 +1,3 @@
+ package main
+
+-func main() {}
+\ No newline at end of file
++func main() { run() }
+\ No newline at end of file
+`)
+	// Binary file
+	f.Add(`diff --git a/logo.png b/logo.png
+index 0c2d8a1..9f1e4b2 100644
+Binary files a/logo.png and b/logo.png differ
+`)
+	// Rename with changes
+	f.Add(`diff --git a/old.go b/new.go
+similarity index 90%
+rename from old.go
+rename to new.go
+index 1a2b3c4..5d6e7f8 100644
+--- a/old.go
++++ b/new.go
+@@ -2,2 +2,3 @@ package main
+
+-func Old() {}
++func New() {}
++func Other() {}
+`)
+	// Mode change only
+	f.Add(`diff --git a/run.sh b/run.sh
+old mode 100644
+new mode 100755
+`)
+	// Removed and added lines looking like file headers
+	f.Add(`diff --git a/a.txt b/a.txt
+--- a/a.txt
++++ b/a.txt
+@@ -1,2 +1,2 @@
+--- a
++++ b
+ end
+`)
+
+	f.Fuzz(func(t *testing.T, diff string) {
+		result, err := parseUnifiedDiffWithDeleted(diff)
+		if err != nil {
+			if result != nil {
+				t.Fatalf("result %v returned with error %v", result, err)
+			}
+			return
+		}
+
+		added, deleted := 0, 0
+		for _, line := range strings.Split(diff, "\n") {
+			if strings.HasPrefix(line, "+") {
+				added++
+			} else if strings.HasPrefix(line, "-") {
+				deleted++
+			}
+		}
+		if len(result.AddedLines) > added || len(result.DeletedLines) > deleted {
+			t.Fatalf("got %d added and %d deleted lines from %d \"+\" and %d \"-\" lines", len(result.AddedLines), len(result.DeletedLines), added, deleted)
+		}
+
+		lines, err := parseUnifiedDiff(diff)
+		if err != nil || len(lines) != len(result.AddedLines) {
+			t.Fatalf("parseUnifiedDiff = %v, %v; want the added lines %v", lines, err, result.AddedLines)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"go/parser"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// FindEmbeddingFiles returns the Go files in a package directory whose //go:embed directives match the embedded file
+// relFile is the embedded file path relative to pkgDir
+func (s *SymbolAnalyzer) FindEmbeddingFiles(pkgDir string, relFile string) ([]string, error) {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+
+	relFile = filepath.ToSlash(relFile)
+	var files []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, entry.Name())
+		file, err := parser.ParseFile(s.fset, filePath, nil, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		found := false
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if !strings.HasPrefix(c.Text, "//go:embed ") {
+					continue
+				}
+				for _, pattern := range parseEmbedPatterns(strings.TrimPrefix(c.Text, "//go:embed ")) {
+					if matchEmbedPattern(pattern, relFile) {
+						found = true
+					}
+				}
+			}
+		}
+
+		if found {
+			files = append(files, filePath)
+		}
+	}
+
+	return files, nil
+}
+
+// parseEmbedPatterns splits the arguments of a //go:embed directive into patterns
+// Patterns are separated by spaces and may be quoted with double quotes or backquotes
+func parseEmbedPatterns(args string) []string {
+	var patterns []string
+
+	args = strings.TrimSpace(args)
+	for args != "" {
+		var pattern string
+		switch args[0] {
+		case '"', '`':
+			end := strings.IndexByte(args[1:], args[0])
+			if end < 0 {
+				return patterns
+			}
+			quoted := args[:end+2]
+			unquoted, err := strconv.Unquote(quoted)
+			if err != nil {
+				return patterns
+			}
+			pattern = unquoted
+			args = args[end+2:]
+		default:
+			end := strings.IndexAny(args, " \t")
+			if end < 0 {
+				end = len(args)
+			}
+			pattern = args[:end]
+			args = args[end:]
+		}
+
+		patterns = append(patterns, strings.TrimPrefix(pattern, "all:"))
+		args = strings.TrimSpace(args)
+	}
+
+	return patterns
+}
+
+// matchEmbedPattern checks if an embed pattern matches the file or one of its parent directories
+func matchEmbedPattern(pattern, relFile string) bool {
+	for p := relFile; p != "." && p != ""; p = path.Dir(p) {
+		if matched, err := path.Match(pattern, p); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveEmbeddedFile maps a changed non-Go file to the package embedding it and the Go files declaring the embed
+func (a *Analyzer) resolveEmbeddedFile(absPath string) (string, []string) {
+	pkgPath := a.graph.GetEmbeddingPackage(absPath)
+	if pkgPath == "" {
+		return "", nil
+	}
+
+	pkgDir := a.symbolAnalyzer.GetPackageDir(pkgPath)
+	relFile, err := filepath.Rel(pkgDir, absPath)
+	if err != nil {
+		return "", nil
+	}
+
+	files, err := a.symbolAnalyzer.FindEmbeddingFiles(pkgDir, relFile)
+	if err != nil || len(files) == 0 {
+		return "", nil
+	}
+
+	return pkgPath, files
+}
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Error kinds of failures that make the analysis result partial
+var (
+	// ErrParse indicates a Go file could not be parsed
+	ErrParse = errors.New("parse error")
+	// ErrGitDiff indicates the diff or base content of a file could not be read
+	ErrGitDiff = errors.New("git diff error")
+	// ErrPackageLoad indicates a package could not be loaded by go list
+	ErrPackageLoad = errors.New("package load error")
+	// ErrRulePlugin indicates a rule plugin failed or returned an invalid result
+	ErrRulePlugin = errors.New("rule plugin error")
+	// ErrExternalAnalyzer indicates an external analyzer failed or returned an invalid result
+	ErrExternalAnalyzer = errors.New("external analyzer error")
+	// ErrCaseCollision indicates packages whose paths differ only by case, which case-insensitive
+	// filesystems (macOS, Windows) cannot tell apart
+	ErrCaseCollision = errors.New("case collision")
+)
+
+// AnalysisError is a non-fatal failure recorded while analyzing
+// Use errors.Is with ErrParse, ErrGitDiff, ErrPackageLoad, ErrRulePlugin, ErrExternalAnalyzer or ErrCaseCollision to check its kind
+type AnalysisError struct {
+	Kind    error
+	File    string
+	Package string
+	Err     error
+}
+
+// Error returns the error message
+func (e *AnalysisError) Error() string {
+	subject := e.File
+	if subject == "" {
+		subject = e.Package
+	}
+	if subject == "" {
+		return fmt.Sprintf("%v: %v", e.Kind, e.Err)
+	}
+	return fmt.Sprintf("%v: %s: %v", e.Kind, subject, e.Err)
+}
+
+// Unwrap returns the error kind and the underlying error
+func (e *AnalysisError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+// MarshalJSON encodes the error as {"kind", "file", "package", "message"}
+func (e *AnalysisError) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Kind    string `json:"kind"`
+		File    string `json:"file,omitempty"`
+		Package string `json:"package,omitempty"`
+		Message string `json:"message"`
+	}{
+		Kind:    e.Kind.Error(),
+		File:    e.File,
+		Package: e.Package,
+		Message: e.Err.Error(),
+	})
+}
+
+// UnmarshalJSON decodes an error encoded by MarshalJSON (e.g., a cached result)
+// Known kinds are restored so that errors.Is still matches them
+func (e *AnalysisError) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Kind    string `json:"kind"`
+		File    string `json:"file"`
+		Package string `json:"package"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	e.Kind = errors.New(v.Kind)
+	for _, kind := range []error{ErrParse, ErrGitDiff, ErrPackageLoad, ErrRulePlugin, ErrExternalAnalyzer, ErrCaseCollision} {
+		if kind.Error() == v.Kind {
+			e.Kind = kind
+		}
+	}
+	e.File, e.Package, e.Err = v.File, v.Package, errors.New(v.Message)
+	return nil
+}
+
+// warningCollector collects the non-fatal errors of a single query
+type warningCollector struct {
+	warnings []*AnalysisError
+}
+
+// add records a warning for a file
+func (w *warningCollector) add(kind error, file string, err error) {
+	if w == nil || err == nil {
+		return
+	}
+	w.warnings = append(w.warnings, &AnalysisError{Kind: kind, File: file, Err: err})
+}
@@ -0,0 +1,93 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+)
+
+// GraphNode is a project package in the exported dependency graph
+type GraphNode struct {
+	Package string `json:"package"`
+	// Resources are the names of resources whose entry package is this package
+	Resources []string `json:"resources,omitempty"`
+	// EmbedDirs are the directories of the files the package embeds via //go:embed, relative to the project root
+	EmbedDirs []string `json:"embed_dirs,omitempty"`
+}
+
+// GraphEdge is an import from one project package to another
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// GraphExport is the full dependency graph annotated with resources
+type GraphExport struct {
+	Module string      `json:"module"`
+	Nodes  []GraphNode `json:"nodes"`
+	Edges  []GraphEdge `json:"edges"`
+	// TestEdges and ToolEdges are imports only made by _test.go files or tools files (//go:build tools)
+	// They are not part of Edges and do not propagate impact
+	TestEdges []GraphEdge `json:"test_edges,omitempty"`
+	ToolEdges []GraphEdge `json:"tool_edges,omitempty"`
+	Resources []Resource  `json:"resources"`
+}
+
+// ExportGraph returns the dependency graph with resource annotations for external tooling
+func (a *Analyzer) ExportGraph() *GraphExport {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.exportGraph()
+}
+
+// exportGraph builds the graph export; the caller must hold the lock
+func (a *Analyzer) exportGraph() *GraphExport {
+	export := &GraphExport{
+		Module:    a.config.ModulePath,
+		Nodes:     []GraphNode{},
+		Edges:     []GraphEdge{},
+		Resources: a.resources,
+	}
+	if export.Resources == nil {
+		export.Resources = []Resource{}
+	}
+
+	resourcesByPkg := make(map[string][]string)
+	for _, r := range a.resources {
+		if r.Package != "" {
+			resourcesByPkg[r.Package] = append(resourcesByPkg[r.Package], r.Name)
+		}
+	}
+
+	packages := a.graph.GetAllPackages()
+	sort.Strings(packages)
+
+	for _, pkgPath := range packages {
+		names := resourcesByPkg[pkgPath]
+		sort.Strings(names)
+		var embedDirs []string
+		for _, file := range a.graph.GetEmbeddedFiles(pkgPath) {
+			if rel, err := filepath.Rel(a.config.ProjectRoot, filepath.Dir(file)); err == nil {
+				embedDirs = append(embedDirs, filepath.ToSlash(rel))
+			}
+		}
+		export.Nodes = append(export.Nodes, GraphNode{
+			Package:   pkgPath,
+			Resources: names,
+			EmbedDirs: uniqueStrings(embedDirs),
+		})
+
+		deps := append([]string(nil), a.graph.GetDirectDeps(pkgPath)...)
+		sort.Strings(deps)
+		for _, dep := range deps {
+			export.Edges = append(export.Edges, GraphEdge{From: pkgPath, To: dep})
+		}
+		for _, dep := range a.graph.GetTestOnlyDeps(pkgPath) {
+			export.TestEdges = append(export.TestEdges, GraphEdge{From: pkgPath, To: dep})
+		}
+		for _, dep := range a.graph.GetToolDeps(pkgPath) {
+			export.ToolEdges = append(export.ToolEdges, GraphEdge{From: pkgPath, To: dep})
+		}
+	}
+
+	return a.collapseGraph(export)
+}
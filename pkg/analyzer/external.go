@@ -0,0 +1,132 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+)
+
+// ExternalAnalyzer determines the resources of non-Go code (e.g., TypeScript packages of a polyglot monorepo)
+// affected by the changes; its results are merged into the affected resources
+// The protocol is JSON: Analyze receives an ExternalInput and returns an ExternalOutput
+type ExternalAnalyzer interface {
+	// Name identifies the analyzer in reasons and warnings
+	Name() string
+	// Analyze evaluates a JSON-encoded ExternalInput and returns a JSON-encoded ExternalOutput
+	Analyze(input []byte) ([]byte, error)
+}
+
+// ExternalInput is the data passed to external analyzers
+type ExternalInput struct {
+	ProjectRoot  string   `json:"project_root"`
+	ChangedFiles []string `json:"changed_files"`
+}
+
+// ExternalOutput is the result of an external analyzer
+type ExternalOutput struct {
+	Affected []ExternalResource `json:"affected"`
+}
+
+// ExternalResource is a resource reported as affected by an external analyzer
+type ExternalResource struct {
+	Name string `json:"name"`
+	// Type is one of the resource types (default: external)
+	Type        ResourceType `json:"type"`
+	Description string       `json:"description"`
+	Reason      string       `json:"reason"`
+}
+
+// execExternalAnalyzer is an external analyzer run as an executable
+type execExternalAnalyzer struct {
+	path string
+}
+
+// NewExecExternalAnalyzer creates an external analyzer running an executable
+// that reads the input on stdin and writes the output on stdout
+func NewExecExternalAnalyzer(path string) ExternalAnalyzer {
+	return &execExternalAnalyzer{path: path}
+}
+
+// Name returns the file name of the executable
+func (e *execExternalAnalyzer) Name() string {
+	return filepath.Base(e.path)
+}
+
+// Analyze runs the executable with the input on stdin
+func (e *execExternalAnalyzer) Analyze(input []byte) ([]byte, error) {
+	return runJSONExecutable(e.path, input)
+}
+
+// addExternalResources merges the resources reported by the external analyzers; failing analyzers are reported as warnings
+// A resource already reported (e.g., the Go backend of the same service) keeps its Go analysis result
+func (a *Analyzer) addExternalResources(changedFiles []string, affected []AffectedResource, warnings *warningCollector) []AffectedResource {
+	if len(a.config.ExternalAnalyzers) == 0 {
+		return affected
+	}
+
+	input, err := json.Marshal(ExternalInput{ProjectRoot: a.config.ProjectRoot, ChangedFiles: changedFiles})
+	if err != nil {
+		warnings.add(ErrExternalAnalyzer, "", err)
+		return affected
+	}
+
+	reported := make(map[string]bool)
+	for _, r := range affected {
+		reported[r.Name] = true
+	}
+	for _, ea := range a.config.ExternalAnalyzers {
+		raw, err := ea.Analyze(input)
+		if err != nil {
+			warnings.add(ErrExternalAnalyzer, ea.Name(), err)
+			continue
+		}
+		var output ExternalOutput
+		if err := json.Unmarshal(raw, &output); err != nil {
+			warnings.add(ErrExternalAnalyzer, ea.Name(), fmt.Errorf("invalid output: %w", err))
+			continue
+		}
+
+		for _, er := range output.Affected {
+			if er.Name == "" {
+				warnings.add(ErrExternalAnalyzer, ea.Name(), fmt.Errorf("resource without a name"))
+				continue
+			}
+			if reported[er.Name] || (len(a.config.Resources) > 0 && !contains(a.config.Resources, er.Name)) {
+				continue
+			}
+			if er.Type == "" {
+				er.Type = ResourceTypeExternal
+			}
+			if !er.Type.IsValid() {
+				warnings.add(ErrExternalAnalyzer, ea.Name(), fmt.Errorf("unknown type %q of resource %s", er.Type, er.Name))
+				continue
+			}
+			reason := er.Reason
+			if reason == "" {
+				reason = "reported by " + ea.Name()
+			}
+
+			// Go resources of the same name (e.g., when the TypeScript client belongs to a Go service) are reused
+			resource := Resource{Name: er.Name, Type: er.Type, Description: er.Description}
+			if r := a.getResourceByName(er.Name); r != nil {
+				resource = *r
+			} else {
+				resource.Labels = matchLabels(a.config.LabelRules, resource.Name)
+				resource.Severity = matchSeverity(a.config.SeverityRules, resource.Name)
+				resource.Image = resolveImage(a.config.Images, a.config.ImageTemplate, resource)
+				resource.Applications = a.config.Applications[resource.Name]
+			}
+
+			reported[er.Name] = true
+			affected = append(affected, AffectedResource{
+				Resource:        resource,
+				Reason:          reason,
+				DependencyChain: []string{},
+				Confidence:      ConfidenceHigh,
+			})
+		}
+	}
+
+	SortAffectedResources(affected)
+	return affected
+}
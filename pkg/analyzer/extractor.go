@@ -0,0 +1,666 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ResourceExtractor extracts resources from CLI command definitions
+type ResourceExtractor struct {
+	modulePath string
+	fset       *token.FileSet
+	// cmdPackageSuffix is the suffix to match CLI command packages (default: "/cli/cmd")
+	cmdPackageSuffix string
+	// resourceFileMap maps filename to ResourceType
+	resourceFileMap map[string]ResourceType
+	// resourceFilePatterns map filenames matching glob patterns to ResourceType, after resourceFileMap
+	resourceFilePatterns []ResourceFilePattern
+	// commandPaths names subcommands by their command path instead of their own name
+	commandPaths bool
+	// FileSystem for file operations
+	fs FileSystem
+}
+
+// ExtractorOption is a function that configures ResourceExtractor
+type ExtractorOption func(*ResourceExtractor)
+
+// WithCmdPackageSuffix sets a custom CLI command package suffix
+func WithCmdPackageSuffix(suffix string) ExtractorOption {
+	return func(e *ResourceExtractor) {
+		e.cmdPackageSuffix = suffix
+	}
+}
+
+// WithResourceFileMap sets a custom mapping from filename to ResourceType
+func WithResourceFileMap(m map[string]ResourceType) ExtractorOption {
+	return func(e *ResourceExtractor) {
+		e.resourceFileMap = m
+	}
+}
+
+// ResourceFilePattern maps the command files whose name matches a glob pattern to a resource type,
+// for command definitions split across many files (e.g., "cmd_*_job.go")
+type ResourceFilePattern struct {
+	// Pattern is a path.Match pattern matched against the file name
+	Pattern string `json:"pattern"`
+	// Type is the type of the resources defined in matching files
+	Type ResourceType `json:"type"`
+}
+
+// WithResourceFilePatterns adds glob patterns mapping filenames to ResourceType
+// Exact filenames of the resource file map take precedence; the first matching pattern wins
+func WithResourceFilePatterns(patterns []ResourceFilePattern) ExtractorOption {
+	return func(e *ResourceExtractor) {
+		e.resourceFilePatterns = append(e.resourceFilePatterns, patterns...)
+	}
+}
+
+// WithCommandPaths names subcommands by their command path below the resource file command
+// (e.g., "job update-price") instead of their own name ("update-price")
+func WithCommandPaths(enabled bool) ExtractorOption {
+	return func(e *ResourceExtractor) {
+		e.commandPaths = enabled
+	}
+}
+
+// WithFileSystem sets a custom FileSystem
+func WithFileSystem(fs FileSystem) ExtractorOption {
+	return func(e *ResourceExtractor) {
+		e.fs = fs
+	}
+}
+
+// NewResourceExtractor creates a new ResourceExtractor
+func NewResourceExtractor(modulePath string, opts ...ExtractorOption) *ResourceExtractor {
+	e := &ResourceExtractor{
+		modulePath:       modulePath,
+		fset:             token.NewFileSet(),
+		cmdPackageSuffix: "/cli/cmd",
+		resourceFileMap: map[string]ResourceType{
+			"api.go":    ResourceTypeAPI,
+			"job.go":    ResourceTypeJob,
+			"worker.go": ResourceTypeWorker,
+		},
+		fs: NewFileSystem(),
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e
+}
+
+// resourceType returns the type of the resources defined in a command file, or "" outside the resource files
+func (e *ResourceExtractor) resourceType(filePath string) ResourceType {
+	name := filepath.Base(filePath)
+	if t, ok := e.resourceFileMap[name]; ok {
+		return t
+	}
+	for _, p := range e.resourceFilePatterns {
+		if matched, err := path.Match(p.Pattern, name); err == nil && matched {
+			return p.Type
+		}
+	}
+	return ""
+}
+
+// commandNode is a cobra.Command literal found in the command directory
+type commandNode struct {
+	// command holds the fields of the literal; its type is empty outside the resource files
+	command  *Resource
+	lit      *ast.CompositeLit
+	parent   *commandNode
+	children []*commandNode
+	// flags are the flag definitions of the command
+	flags []commandFlag
+	// index is the position of the command in the extracted resources, or -1 if it is not a resource
+	index int
+	// fingerprint identifies the definition of the command: its literal and the flags it accepts
+	fingerprint string
+	// queues are the queue or topic names passed to RunWorkerPool, resolved for workers by the Analyzer
+	queues []queueRef
+}
+
+// commandFlag is a flag definition: a call on cmd.Flags() or cmd.PersistentFlags()
+type commandFlag struct {
+	file       string
+	start, end int
+	// receiver is the binding key of the command variable ("name" at package level, "func.name" for locals)
+	receiver string
+	// persistent flags are inherited by the subcommands
+	persistent bool
+	call       *ast.CallExpr
+}
+
+// commandTree is the cobra command hierarchy of the command directory
+type commandTree struct {
+	// files are the Go files of the command directory
+	files []string
+	nodes []*commandNode
+	// Binding key -> command, to resolve the receivers of flag definitions
+	bindings map[string]*commandNode
+}
+
+// commandScope resolves the names bound to command literals in the command directory
+type commandScope struct {
+	// Package-level variable name -> literal
+	vars map[string]*ast.CompositeLit
+	// Function name + "." + local variable name -> literal
+	locals map[string]*ast.CompositeLit
+	// Function name -> returned expression
+	returns map[string]ast.Expr
+	// Function name + "." + local variable name -> flag set of a command (flags := cmd.Flags())
+	flagSets map[string]commandFlag
+}
+
+// newCommandScope creates an empty scope
+func newCommandScope() *commandScope {
+	return &commandScope{
+		vars:     make(map[string]*ast.CompositeLit),
+		locals:   make(map[string]*ast.CompositeLit),
+		returns:  make(map[string]ast.Expr),
+		flagSets: make(map[string]commandFlag),
+	}
+}
+
+// addCommandCall is a parent.AddCommand(children...) call
+type addCommandCall struct {
+	funcName string
+	parent   ast.Expr
+	children []ast.Expr
+}
+
+// ExtractFromDir extracts resources from the specified directory
+// Commands defined in the resource files (api.go, job.go, worker.go) and the subcommands added to them
+// with AddCommand, in any file of the directory, are resources named by their own name, or by their command
+// path with WithCommandPaths (e.g., "job update-price"); parent commands without a package of their own only
+// group subcommands
+func (e *ResourceExtractor) ExtractFromDir(dir string) ([]Resource, error) {
+	resources, _, err := e.extractCommands(dir)
+	return resources, err
+}
+
+// extractCommands extracts the resources and the command tree of the specified directory
+func (e *ResourceExtractor) extractCommands(dir string) ([]Resource, *commandTree, error) {
+	entries, err := e.fs.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, &commandTree{bindings: make(map[string]*commandNode)}, nil
+		}
+		return nil, nil, err
+	}
+
+	var files []string
+	for _, entry := range entries {
+		fileName := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(fileName, ".go") || strings.HasSuffix(fileName, "_test.go") {
+			continue
+		}
+		files = append(files, filepath.Join(dir, fileName))
+	}
+
+	resources, tree := e.extractCommandFiles(e.fset, files, e.fs.ReadFile)
+	return resources, tree, nil
+}
+
+// extractCommandFiles extracts the resources and the command tree of Go files whose content is returned by read
+// Files that cannot be read or parsed are skipped
+func (e *ResourceExtractor) extractCommandFiles(fset *token.FileSet, files []string, read func(path string) ([]byte, error)) ([]Resource, *commandTree) {
+	tree := &commandTree{files: files, bindings: make(map[string]*commandNode)}
+	byLit := make(map[*ast.CompositeLit]*commandNode)
+	scope := newCommandScope()
+	var calls []addCommandCall
+	var flags []commandFlag
+
+	for _, filePath := range files {
+		content, err := read(filePath)
+		if err != nil {
+			continue
+		}
+
+		// Parse the file
+		file, err := parser.ParseFile(fset, filePath, content, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+
+		// Build import map
+		importMap := e.buildImportMap(file)
+
+		// Extract command literals
+		for _, lit := range e.findCommandLiterals(file) {
+			command := e.extractResourceFromCompositeLit(lit, importMap, e.resourceType(filePath), filePath)
+			if command == nil {
+				continue
+			}
+			node := &commandNode{command: command, lit: lit, index: -1, queues: e.extractQueuesFromRunE(lit, importMap)}
+			tree.nodes = append(tree.nodes, node)
+			byLit[lit] = node
+		}
+
+		fileCalls, fileFlags := scope.collect(fset, file, filePath)
+		calls = append(calls, fileCalls...)
+		flags = append(flags, fileFlags...)
+	}
+
+	// Link subcommands to their parents
+	for _, call := range calls {
+		parent := byLit[scope.resolve(call.funcName, call.parent)]
+		if parent == nil {
+			continue
+		}
+		for _, expr := range call.children {
+			child := byLit[scope.resolve(call.funcName, expr)]
+			if child == nil || child.parent != nil || child == parent {
+				continue
+			}
+			child.parent = parent
+			parent.children = append(parent.children, child)
+		}
+	}
+
+	// Attach flag definitions to their commands
+	for key, lit := range scope.vars {
+		if node := byLit[lit]; node != nil {
+			tree.bindings[key] = node
+		}
+	}
+	for key, lit := range scope.locals {
+		if node := byLit[lit]; node != nil {
+			tree.bindings[key] = node
+		}
+	}
+	for _, flag := range flags {
+		if node := tree.bindings[flag.receiver]; node != nil {
+			node.flags = append(node.flags, flag)
+		}
+	}
+
+	var resources []Resource
+	for _, node := range tree.nodes {
+		node.fingerprint = node.definitionFingerprint(fset)
+		resource, ok := node.commandResource(e.commandPaths)
+		if !ok {
+			continue
+		}
+		if resource.Type == ResourceTypeJob {
+			resource.Schedule = node.schedule()
+		}
+		node.index = len(resources)
+		resources = append(resources, resource)
+	}
+	return resources, tree
+}
+
+// buildImportMap builds alias -> package path mapping from import declarations
+func (e *ResourceExtractor) buildImportMap(file *ast.File) map[string]string {
+	importMap := make(map[string]string)
+
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, `"`)
+
+		// Use alias if present, otherwise use package name
+		var alias string
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		} else {
+			// Use last element of path as package name
+			parts := strings.Split(path, "/")
+			alias = parts[len(parts)-1]
+			// Cannot use if contains hyphen
+			if strings.Contains(alias, "-") {
+				alias = ""
+			}
+		}
+
+		if alias != "" && alias != "_" && alias != "." {
+			importMap[alias] = path
+		}
+	}
+
+	return importMap
+}
+
+// commandResource returns the resource of a command: its type is inherited from the closest command
+// defined in a resource file, and its name is its own, or the command path starting at that command
+// Commands outside the resource files (e.g., the root command) and pure groups are not resources
+func (n *commandNode) commandResource(commandPath bool) (Resource, bool) {
+	if len(n.children) > 0 && n.command.Package == "" {
+		return Resource{}, false
+	}
+
+	resource := *n.command
+	names := []string{n.command.Name}
+	typed := n.command.Type != ""
+	seen := map[*commandNode]bool{n: true}
+	for p := n.parent; p != nil && !seen[p]; p = p.parent {
+		seen[p] = true
+		if typed && p.command.Type == "" {
+			break
+		}
+		if p.command.Type != "" && !typed {
+			// Subcommands defined outside the resource files take the type of their closest typed ancestor
+			typed = true
+			resource.Type = p.command.Type
+		}
+		names = append([]string{p.command.Name}, names...)
+	}
+	if !typed {
+		return Resource{}, false
+	}
+
+	if commandPath {
+		resource.Name = strings.Join(names, " ")
+	}
+	return resource, true
+}
+
+// definitionFingerprint hashes the formatted literal of the command, its flag definitions and the
+// persistent flags of its ancestors; formatting and comments do not change the fingerprint
+func (n *commandNode) definitionFingerprint(fset *token.FileSet) string {
+	h := sha256.New()
+	printer.Fprint(h, fset, n.lit)
+	for _, flag := range n.flags {
+		printer.Fprint(h, fset, flag.call)
+	}
+	seen := map[*commandNode]bool{n: true}
+	for p := n.parent; p != nil && !seen[p]; p = p.parent {
+		seen[p] = true
+		for _, flag := range p.flags {
+			if flag.persistent {
+				printer.Fprint(h, fset, flag.call)
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resourceIndexes returns the indexes of the resources affected by a flag of the command:
+// the command itself, and its subcommands for persistent flags
+func (n *commandNode) resourceIndexes(persistent bool) []int {
+	var indexes []int
+	if n.index >= 0 {
+		indexes = append(indexes, n.index)
+	}
+	if persistent {
+		for _, child := range n.children {
+			indexes = append(indexes, child.resourceIndexes(true)...)
+		}
+	}
+	return indexes
+}
+
+// findCommandLiterals returns the &cobra.Command{...} literals of a file in source order
+func (e *ResourceExtractor) findCommandLiterals(file *ast.File) []*ast.CompositeLit {
+	var lits []*ast.CompositeLit
+	ast.Inspect(file, func(n ast.Node) bool {
+		if expr, ok := n.(ast.Expr); ok {
+			if lit := commandLiteral(expr); lit != nil {
+				lits = append(lits, lit)
+			}
+		}
+		return true
+	})
+	return lits
+}
+
+// collect records the names bound to command literals of a file, its AddCommand calls and its flag definitions
+func (s *commandScope) collect(fset *token.FileSet, file *ast.File, filePath string) ([]addCommandCall, []commandFlag) {
+	var calls []addCommandCall
+	var flags []commandFlag
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, name := range vs.Names {
+					if i < len(vs.Values) {
+						if lit := commandLiteral(vs.Values[i]); lit != nil {
+							s.vars[name.Name] = lit
+						}
+					}
+				}
+			}
+
+		case *ast.FuncDecl:
+			if d.Body == nil || d.Recv != nil {
+				continue
+			}
+			funcName := d.Name.Name
+			ast.Inspect(d.Body, func(n ast.Node) bool {
+				switch node := n.(type) {
+				case *ast.FuncLit:
+					// Closures (e.g., RunE) run when the command executes
+					return false
+				case *ast.AssignStmt:
+					for i, lhs := range node.Lhs {
+						ident, ok := lhs.(*ast.Ident)
+						if !ok || i >= len(node.Rhs) {
+							continue
+						}
+						if flagSet, ok := s.flagSet(funcName, node.Rhs[i]); ok && node.Tok == token.DEFINE {
+							s.flagSets[funcName+"."+ident.Name] = flagSet
+							continue
+						}
+						lit := commandLiteral(node.Rhs[i])
+						if lit == nil {
+							continue
+						}
+						if node.Tok == token.DEFINE {
+							s.locals[funcName+"."+ident.Name] = lit
+						} else {
+							s.vars[ident.Name] = lit
+						}
+					}
+				case *ast.ReturnStmt:
+					if len(node.Results) == 1 {
+						s.returns[funcName] = node.Results[0]
+					}
+				case *ast.CallExpr:
+					sel, ok := node.Fun.(*ast.SelectorExpr)
+					if !ok {
+						return true
+					}
+					if sel.Sel.Name == "AddCommand" {
+						calls = append(calls, addCommandCall{funcName: funcName, parent: sel.X, children: node.Args})
+						return true
+					}
+					// Flag definitions: cmd.Flags().StringVar(...) or flags.StringVar(...)
+					flag, ok := s.flagSet(funcName, sel.X)
+					if !ok {
+						if ident, isIdent := sel.X.(*ast.Ident); isIdent {
+							flag, ok = s.flagSets[funcName+"."+ident.Name]
+						}
+					}
+					if ok {
+						flag.file = filePath
+						flag.call = node
+						flag.start = fset.Position(node.Pos()).Line
+						flag.end = fset.Position(node.End()).Line
+						flags = append(flags, flag)
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	return calls, flags
+}
+
+// flagSet returns the flag set of a cmd.Flags() or cmd.PersistentFlags() call on a command variable
+func (s *commandScope) flagSet(funcName string, expr ast.Expr) (commandFlag, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) > 0 {
+		return commandFlag{}, false
+	}
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || (sel.Sel.Name != "Flags" && sel.Sel.Name != "PersistentFlags") {
+		return commandFlag{}, false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return commandFlag{}, false
+	}
+	receiver := ident.Name
+	if _, ok := s.locals[funcName+"."+ident.Name]; ok {
+		receiver = funcName + "." + ident.Name
+	}
+	return commandFlag{receiver: receiver, persistent: sel.Sel.Name == "PersistentFlags"}, true
+}
+
+// resolve returns the command literal an expression of a function refers to: a literal, a local or
+// package-level variable, or a call to a function returning a command (e.g., newServeCmd())
+func (s *commandScope) resolve(funcName string, expr ast.Expr) *ast.CompositeLit {
+	seen := make(map[string]bool)
+	for {
+		if lit := commandLiteral(expr); lit != nil {
+			return lit
+		}
+		switch x := expr.(type) {
+		case *ast.Ident:
+			if lit, ok := s.locals[funcName+"."+x.Name]; ok {
+				return lit
+			}
+			return s.vars[x.Name]
+		case *ast.CallExpr:
+			ident, ok := x.Fun.(*ast.Ident)
+			if !ok || seen[ident.Name] {
+				return nil
+			}
+			seen[ident.Name] = true
+			funcName = ident.Name
+			if expr, ok = s.returns[funcName]; !ok {
+				return nil
+			}
+		default:
+			return nil
+		}
+	}
+}
+
+// commandLiteral returns the literal of a &cobra.Command{...} expression
+func commandLiteral(expr ast.Expr) *ast.CompositeLit {
+	unary, ok := expr.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return nil
+	}
+	lit, ok := unary.X.(*ast.CompositeLit)
+	if !ok {
+		return nil
+	}
+	sel, ok := lit.Type.(*ast.SelectorExpr)
+	if !ok || sel.Sel.Name != "Command" {
+		return nil
+	}
+	if ident, ok := sel.X.(*ast.Ident); !ok || ident.Name != "cobra" {
+		return nil
+	}
+	return lit
+}
+
+// extractResourceFromCompositeLit extracts resource info from CompositeLit
+func (e *ResourceExtractor) extractResourceFromCompositeLit(
+	lit *ast.CompositeLit,
+	importMap map[string]string,
+	resourceType ResourceType,
+	sourceFile string,
+) *Resource {
+	var name, description, pkg string
+
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+
+		switch key.Name {
+		case "Use":
+			// Use: "command-name" or Use: "command-name [args]"
+			if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+				useValue := strings.Trim(basicLit.Value, `"`)
+				// Command name is before space
+				parts := strings.SplitN(useValue, " ", 2)
+				name = parts[0]
+			}
+
+		case "Short":
+			if basicLit, ok := kv.Value.(*ast.BasicLit); ok && basicLit.Kind == token.STRING {
+				description = strings.Trim(basicLit.Value, `"`)
+			}
+
+		case "RunE":
+			// Identify package called from RunE
+			pkg = e.extractPackageFromRunE(kv.Value, importMap)
+		}
+	}
+
+	if name == "" {
+		return nil
+	}
+
+	return &Resource{
+		Name:        name,
+		Type:        resourceType,
+		Package:     pkg,
+		SourceFile:  sourceFile,
+		Description: description,
+	}
+}
+
+// extractPackageFromRunE identifies the package called from RunE field
+func (e *ResourceExtractor) extractPackageFromRunE(expr ast.Expr, importMap map[string]string) string {
+	var pkg string
+
+	ast.Inspect(expr, func(n ast.Node) bool {
+		// Look for package.Run(...) pattern
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		// Look for Run or RunWorkerPool method calls
+		if sel.Sel.Name != "Run" && sel.Sel.Name != "RunWorkerPool" {
+			return true
+		}
+
+		// Get package alias
+		ident, ok := sel.X.(*ast.Ident)
+		if !ok {
+			return true
+		}
+
+		// Get package path from importMap
+		if path, exists := importMap[ident.Name]; exists {
+			pkg = path
+			return false // Stop searching when found
+		}
+
+		return true
+	})
+
+	return pkg
+}
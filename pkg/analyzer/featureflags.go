@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// FeatureFlagDefinition declares feature flags whose keys are held by Go string constants
+// Resources read a flag through its constant, so a changed flag definition affects them like a changed constant
+type FeatureFlagDefinition struct {
+	// Constant is a string constant holding a flag key (e.g., "pkg/flags.NewCheckout"),
+	// or a package whose exported string constants all hold flag keys (e.g., "pkg/flags")
+	Constant string `json:"constant"`
+	// Files are glob patterns of the files defining the flags (e.g., default values or rollout rules)
+	// A changed line mentioning the key or the constant name of a flag changes the flag
+	Files []string `json:"files"`
+}
+
+// featureFlag is a feature flag resolved from its constant
+type featureFlag struct {
+	pkgPath  string
+	constant string
+	key      string
+	files    []string
+	// mention matches the key or the constant name as a whole word
+	mention *regexp.Regexp
+}
+
+// resolveFeatureFlags resolves the flag keys of Config.FeatureFlags from their constants
+// Constants that cannot be resolved are skipped
+func (a *Analyzer) resolveFeatureFlags() []featureFlag {
+	var flags []featureFlag
+	for _, def := range a.config.FeatureFlags {
+		constants := make(map[string]string)
+		pkgPath := ""
+		if ref, err := a.ParseSymbolRef(def.Constant); err == nil && a.graph.isProjectPackage(ref.Package) {
+			pkgPath = ref.Package
+			if value, ok := a.symbolAnalyzer.GetStringConstant(a.symbolAnalyzer.GetPackageDir(pkgPath), ref.Symbol); ok {
+				constants[ref.Symbol] = value
+			}
+		} else {
+			pkgPath = a.ResolvePackagePath(def.Constant)
+			constants = a.symbolAnalyzer.GetStringConstants(a.symbolAnalyzer.GetPackageDir(pkgPath))
+		}
+
+		names := make([]string, 0, len(constants))
+		for name := range constants {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			key := constants[name]
+			if key == "" {
+				continue
+			}
+			flags = append(flags, featureFlag{
+				pkgPath:  pkgPath,
+				constant: name,
+				key:      key,
+				files:    def.Files,
+				mention:  regexp.MustCompile(`(^|[^\w.-])(` + regexp.QuoteMeta(key) + `|` + regexp.QuoteMeta(name) + `)($|[^\w.-])`),
+			})
+		}
+	}
+	return flags
+}
+
+// addFeatureFlagChanges adds the constants of the flags changed in their definition files
+// to the changed symbols of their packages, so that the resources reading the flags are affected
+func (a *Analyzer) addFeatureFlagChanges(changedFiles []string, changes map[string]*packageChanges) {
+	for _, file := range changedFiles {
+		relPath := a.projectRelPath(file)
+		var flags []featureFlag
+		for _, flag := range a.featureFlags {
+			for _, pattern := range flag.files {
+				if matchGlob(pattern, relPath) {
+					flags = append(flags, flag)
+					break
+				}
+			}
+		}
+		if len(flags) == 0 {
+			continue
+		}
+
+		changed := a.getChangedNames(a.toAbsPath(file), file, func(content []byte, lines []int) []string {
+			var keys []string
+			contentLines := strings.Split(string(content), "\n")
+			for i := range contentLines {
+				if !linesInRange(lines, i+1, i+1) {
+					continue
+				}
+				for _, line := range enclosingLines(contentLines, i) {
+					for _, flag := range flags {
+						if flag.mention.MatchString(line) {
+							keys = append(keys, flag.pkgPath+"."+flag.constant)
+						}
+					}
+				}
+			}
+			return uniqueStrings(keys)
+		})
+
+		for _, flag := range flags {
+			if !contains(changed, flag.pkgPath+"."+flag.constant) {
+				continue
+			}
+			pc := changes[flag.pkgPath]
+			if pc == nil {
+				pc = &packageChanges{}
+				changes[flag.pkgPath] = pc
+			}
+			if !contains(pc.info.symbols, flag.constant) {
+				pc.info.symbols = append(pc.info.symbols, flag.constant)
+				sort.Strings(pc.info.symbols)
+			}
+			note := fmt.Sprintf("feature flag %s changed in %s", flag.key, relPath)
+			pc.info.note = strings.TrimPrefix(pc.info.note+", "+note, ", ")
+		}
+	}
+}
+
+// enclosingLines returns a line followed by the lines enclosing it by indentation (e.g., the keys of a YAML value),
+// so that a changed nested setting is attributed to the flag it belongs to
+func enclosingLines(lines []string, i int) []string {
+	indentOf := func(line string) int {
+		return len(line) - len(strings.TrimLeft(line, " \t"))
+	}
+	result := []string{lines[i]}
+	indent := indentOf(lines[i])
+	for j := i - 1; j >= 0 && indent > 0; j-- {
+		if strings.TrimSpace(lines[j]) == "" {
+			continue
+		}
+		if lineIndent := indentOf(lines[j]); lineIndent < indent {
+			result = append(result, lines[j])
+			indent = lineIndent
+		}
+	}
+	return result
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// FileMapping maps files matching a glob pattern to the resources they configure
+// This covers runtime config files that are not Go code (e.g., configs/api.yaml -> api-gateway)
+type FileMapping struct {
+	// Pattern is a glob relative to the project root; "**" matches any number of directories
+	Pattern string `json:"pattern"`
+	// Resources are the names of the resources affected when a matching file changes
+	Resources []string `json:"resources"`
+}
+
+// projectRelPath converts a changed file path to a slash-separated path relative to the project root
+// with PathPrefix removed
+func (a *Analyzer) projectRelPath(file string) string {
+	relPath := file
+	if filepath.IsAbs(file) {
+		var err error
+		relPath, err = filepath.Rel(a.config.ProjectRoot, file)
+		if err != nil {
+			return filepath.ToSlash(file)
+		}
+	}
+	relPath = filepath.ToSlash(relPath)
+	if a.config.PathPrefix != "" {
+		relPath = strings.TrimPrefix(relPath, a.config.PathPrefix)
+	}
+	return relPath
+}
+
+// addFileMappedResources adds resources configured by changed files matching Config.FileMappings
+// Resources already found by code analysis are kept as is
+func (a *Analyzer) addFileMappedResources(changedFiles []string, affectedMap map[string]*AffectedResource) {
+	for _, file := range changedFiles {
+		relPath := a.projectRelPath(file)
+		for _, mapping := range a.config.FileMappings {
+			if !matchGlob(mapping.Pattern, relPath) {
+				continue
+			}
+			for _, name := range mapping.Resources {
+				if _, exists := affectedMap[name]; exists {
+					continue
+				}
+				resource := a.getResourceByName(name)
+				if resource == nil {
+					continue
+				}
+				affectedMap[name] = &AffectedResource{
+					Resource:        *resource,
+					Reason:          fmt.Sprintf("config file %s changed", relPath),
+					DependencyChain: []string{},
+				}
+			}
+		}
+	}
+}
+
+// ForceIncludeRule marks resources as affected whenever a file matching one of its paths changes,
+// layered on top of the static analysis (e.g., any change under auth/ affects all API resources)
+type ForceIncludeRule struct {
+	// Paths are globs relative to the project root; "**" matches any number of directories
+	Paths []string `json:"paths"`
+	// Resources are resource names or glob patterns (e.g., "api-*"); empty matches every resource
+	Resources []string `json:"resources"`
+	// Types restrict the rule to resources of these types (e.g., ["api"]); empty matches every type
+	Types []ResourceType `json:"types"`
+}
+
+// matches checks if a resource is selected by the rule
+func (r ForceIncludeRule) matches(resource *Resource) bool {
+	if len(r.Types) > 0 && !slices.Contains(r.Types, resource.Type) {
+		return false
+	}
+	if len(r.Resources) == 0 {
+		return true
+	}
+	for _, pattern := range r.Resources {
+		if matched, err := path.Match(pattern, resource.Name); pattern == resource.Name || (err == nil && matched) {
+			return true
+		}
+	}
+	return false
+}
+
+// addForceIncludedResources adds the resources of Config.ForceInclude rules whose paths match a changed file
+// Resources already found by code analysis are kept as is
+func (a *Analyzer) addForceIncludedResources(changedFiles []string, affectedMap map[string]*AffectedResource) {
+	for _, rule := range a.config.ForceInclude {
+		trigger := ""
+		for _, file := range changedFiles {
+			relPath := a.projectRelPath(file)
+			for _, pattern := range rule.Paths {
+				if matchGlob(pattern, relPath) {
+					trigger = relPath
+					break
+				}
+			}
+			if trigger != "" {
+				break
+			}
+		}
+		if trigger == "" {
+			continue
+		}
+
+		for i := range a.resources {
+			name := a.resources[i].Name
+			if _, exists := affectedMap[name]; exists {
+				continue
+			}
+			resource := a.getResourceByName(name)
+			if resource == nil || !rule.matches(resource) {
+				continue
+			}
+			affectedMap[name] = &AffectedResource{
+				Resource:        *resource,
+				Reason:          fmt.Sprintf("%s changed (force-include rule)", trigger),
+				DependencyChain: []string{},
+			}
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// addFlagChangedResources adds resources whose flag definitions (cmd.Flags().StringVar(&cfg.Addr, ...))
+// changed in the command directory: their runtime behavior changes even if no package they run changed
+// Resources already found by code analysis are kept as is
+func (a *Analyzer) addFlagChangedResources(changedFiles []string, affectedMap map[string]*AffectedResource, warnings *warningCollector) {
+	if a.commands == nil {
+		return
+	}
+	cmdDir := filepath.Join(a.config.ProjectRoot, a.config.CmdDir)
+
+	for _, file := range changedFiles {
+		absPath := a.toAbsPath(file)
+		if filepath.Dir(absPath) != cmdDir || !strings.HasSuffix(absPath, ".go") {
+			continue
+		}
+
+		lines, err := a.changedFlagLines(file, absPath)
+		if err != nil {
+			warnings.add(ErrGitDiff, file, err)
+			continue
+		}
+
+		relPath := a.projectRelPath(file)
+		for _, index := range sortedKeys(lines) {
+			name := a.resources[index].Name
+			if _, exists := affectedMap[name]; exists {
+				continue
+			}
+			resource := a.getResourceByName(name)
+			if resource == nil {
+				continue
+			}
+			affectedMap[name] = &AffectedResource{
+				Resource:        *resource,
+				Reason:          fmt.Sprintf("flag definition changed (%s:%d)", relPath, lines[index]),
+				DependencyChain: []string{},
+				Confidence:      ConfidenceHigh,
+			}
+		}
+	}
+}
+
+// changedFlagLines returns the indexes of the resources with a changed flag definition in a command file,
+// with the first changed line of the definition
+func (a *Analyzer) changedFlagLines(file, absPath string) (map[int]int, error) {
+	diff, err := a.diffAnalyzer.GetChangedLinesWithDeleted(file)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make(map[int]int)
+	mark := func(node *commandNode, flag commandFlag, line int) {
+		for _, index := range node.resourceIndexes(flag.persistent) {
+			if _, ok := lines[index]; !ok {
+				lines[index] = line
+			}
+		}
+	}
+
+	// Added or modified lines of the current definitions
+	for _, node := range a.commands.nodes {
+		for _, flag := range node.flags {
+			if flag.file != absPath {
+				continue
+			}
+			if line, ok := firstLineIn(diff.AddedLines, flag.start, flag.end); ok {
+				mark(node, flag, line)
+			}
+		}
+	}
+
+	// Removed definitions only exist at the base; their receivers are matched by variable name
+	if len(diff.DeletedLines) > 0 {
+		content, err := a.config.GitClient.GetFileContentAtBase(file)
+		if err != nil || len(content) == 0 {
+			return lines, nil
+		}
+		fset := token.NewFileSet()
+		baseFile, err := parser.ParseFile(fset, absPath, content, 0)
+		if err != nil {
+			return lines, nil
+		}
+		_, baseFlags := newCommandScope().collect(fset, baseFile, absPath)
+		for _, flag := range baseFlags {
+			node := a.commands.bindings[flag.receiver]
+			if node == nil {
+				continue
+			}
+			// The line is reported at the base, where the definition was
+			if line, ok := firstLineIn(diff.DeletedLines, flag.start, flag.end); ok {
+				mark(node, flag, line)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// firstLineIn returns the first line within [start, end]
+func firstLineIn(lines []int, start, end int) (int, bool) {
+	for _, line := range lines {
+		if line >= start && line <= end {
+			return line, true
+		}
+	}
+	return 0, false
+}
+
+// sortedKeys returns the keys of a map in ascending order
+func sortedKeys(m map[int]int) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
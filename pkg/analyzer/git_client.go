@@ -0,0 +1,458 @@
+package analyzer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// execGitClient implements GitClient using exec.Command
+type execGitClient struct {
+	projectDir string
+	baseBranch string
+	// excludes are glob patterns (relative to projectDir) of paths git leaves out of the changed files
+	excludes []string
+
+	// rootDir is the root directory of the work tree, found on first use
+	rootOnce sync.Once
+	rootDir  string
+	rootErr  error
+
+	// submodules are the submodules whose pointer changed, listed on first use
+	submodulesOnce sync.Once
+	submodules     []SubmoduleChange
+	submodulesErr  error
+
+	// commitFiles are the files of the base branch and of the old commits of changed submodules,
+	// listed once per commit on first use
+	commitFilesMu sync.Mutex
+	commitFiles   map[string]map[string]bool
+}
+
+// NewGitClient creates a new GitClient implementation
+func NewGitClient(projectDir, baseBranch string) GitClient {
+	return &execGitClient{
+		projectDir: projectDir,
+		baseBranch: baseBranch,
+	}
+}
+
+// NewGitClientWithExcludes creates a GitClient whose changed files leave out paths matching glob patterns
+// relative to projectDir (e.g., "vendor", "gen/**/*.pb.go"); they are passed to git as :(exclude) pathspecs,
+// so that huge vendored or generated trees are never diffed
+func NewGitClientWithExcludes(projectDir, baseBranch string, excludes []string) GitClient {
+	return &execGitClient{
+		projectDir: projectDir,
+		baseBranch: baseBranch,
+		excludes:   excludes,
+	}
+}
+
+// excludePathspecs returns the pathspecs of the changed files: the whole repository without the excludes
+func (g *execGitClient) excludePathspecs() []string {
+	if len(g.excludes) == 0 {
+		return nil
+	}
+	pathspecs := []string{"--", "."}
+	for _, pattern := range g.excludes {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if _, gitRelPath, err := g.resolveGitPath(pattern); err == nil {
+			pattern = gitRelPath
+		}
+		pathspecs = append(pathspecs, ":(exclude,glob)"+pattern)
+	}
+	return pathspecs
+}
+
+// excluded checks if a file matches an exclude pattern, or is under a directory matching one like pathspecs
+func (g *execGitClient) excluded(gitRelPath string) bool {
+	for _, pattern := range g.excludes {
+		pattern = strings.TrimSuffix(filepath.ToSlash(pattern), "/")
+		if _, rel, err := g.resolveGitPath(pattern); err == nil {
+			pattern = rel
+		}
+		if matchGlob(pattern, gitRelPath) || matchGlob(pattern+"/**", gitRelPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRootDir returns the git repository root directory: the top of the work tree, which is the worktree
+// checkout for linked worktrees (where .git is a file). Bare repositories have no work tree to analyze
+func (g *execGitClient) GetRootDir() (string, error) {
+	g.rootOnce.Do(func() {
+		g.rootDir, g.rootErr = g.findRootDir()
+	})
+	return g.rootDir, g.rootErr
+}
+
+// findRootDir asks git for the top of the work tree of projectDir
+func (g *execGitClient) findRootDir() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
+	cmd.Dir = g.projectDir
+	out, err := cmd.Output()
+	if err == nil && strings.TrimSpace(string(out)) == "" {
+		// Older versions of git print nothing inside the git directory
+		err = fmt.Errorf("not in a work tree")
+	}
+	if err != nil {
+		cmd := exec.Command("git", "rev-parse", "--is-bare-repository")
+		cmd.Dir = g.projectDir
+		if bare, _ := cmd.Output(); strings.TrimSpace(string(bare)) == "true" {
+			return "", fmt.Errorf("%s is in a bare repository: check out a worktree (git worktree add) or set GIT_WORK_TREE", g.projectDir)
+		}
+		return "", fmt.Errorf("failed to find the work tree of %s: %w", g.projectDir, gitError(err))
+	}
+	return projectSpelling(strings.TrimSpace(string(out)), g.projectDir), nil
+}
+
+// projectSpelling returns the root directory spelled through the symlinks of projectDir
+// git resolves symlinks (e.g., /tmp on macOS, or CI workspaces linked into place), so that the
+// paths of the project would not be under the root it reports, and no changed file would match
+func projectSpelling(root, projectDir string) string {
+	absProjectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return root
+	}
+	if rel, err := filepath.Rel(root, absProjectDir); err == nil && !strings.HasPrefix(rel, "..") {
+		return root
+	}
+	realProjectDir, err := filepath.EvalSymlinks(absProjectDir)
+	if err != nil {
+		return root
+	}
+	rel, err := filepath.Rel(filepath.Clean(root), realProjectDir)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return root
+	}
+	// Walk up from projectDir as many directories as it is deep in the work tree
+	dir := absProjectDir
+	if rel != "." {
+		for range strings.Split(rel, string(filepath.Separator)) {
+			dir = filepath.Dir(dir)
+		}
+	}
+	if realDir, err := filepath.EvalSymlinks(dir); err != nil || realDir != filepath.Clean(root) {
+		return root
+	}
+	return dir
+}
+
+// gitError adds the message git printed to the error of a git command
+func gitError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && len(bytes.TrimSpace(exitErr.Stderr)) > 0 {
+		return fmt.Errorf("%w: %s", err, bytes.TrimSpace(exitErr.Stderr))
+	}
+	return err
+}
+
+// GetChangedFiles returns list of changed files compared to base branch
+func (g *execGitClient) GetChangedFiles(baseBranch string) ([]string, error) {
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	pathspecs := g.excludePathspecs()
+	cmd := exec.Command("git", append([]string{"diff", "--name-only", baseBranch + "...HEAD"}, pathspecs...)...)
+	cmd.Dir = gitRoot
+	out, err := cmd.Output()
+	if err != nil {
+		// Fallback: simple diff
+		cmd = exec.Command("git", append([]string{"diff", "--name-only", baseBranch}, pathspecs...)...)
+		cmd.Dir = gitRoot
+		out, err = cmd.Output()
+		if err != nil {
+			return nil, gitError(err)
+		}
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		files = append(files, line)
+	}
+	return g.expandSubmodules(files), nil
+}
+
+// GetChangedLines returns changed line numbers for a specific file
+func (g *execGitClient) GetChangedLines(filePath string) ([]int, error) {
+	// Diffing from another directory than the git root would silently report no changes
+	gitRoot, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run git diff to get line-by-line changes
+	output, err := g.lineDiff(gitRoot, gitRelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUnifiedDiff(string(output))
+}
+
+// GetChangedLinesWithDeleted returns both added and deleted line numbers for a specific file
+func (g *execGitClient) GetChangedLinesWithDeleted(filePath string) (*DiffResult, error) {
+	gitRoot, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Run git diff to get line-by-line changes
+	output, err := g.lineDiff(gitRoot, gitRelPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseUnifiedDiffWithDeleted(string(output))
+}
+
+// GetFileContentAtBase returns the content of a file at the base branch
+func (g *execGitClient) GetFileContentAtBase(filePath string) ([]byte, error) {
+	gitRoot, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Files of a changed submodule are read at the old pointer commit
+	if sub, rel, ok := g.submoduleOf(gitRelPath); ok && sub.OldCommit != "" {
+		cmd := exec.Command("git", "show", sub.OldCommit+":"+rel)
+		cmd.Dir = sub.Dir
+		return cmd.Output()
+	}
+
+	// Get file content at base branch
+	cmd := exec.Command("git", "show", g.baseBranch+":"+gitRelPath)
+	cmd.Dir = gitRoot
+
+	return cmd.Output()
+}
+
+// IsNewFile reports whether a file does not exist on the base branch
+func (g *execGitClient) IsNewFile(filePath string) (bool, error) {
+	gitRoot, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return false, err
+	}
+
+	dir, commit, rel := gitRoot, g.baseBranch, gitRelPath
+	if sub, subRel, ok := g.submoduleOf(gitRelPath); ok {
+		if sub.OldCommit == "" {
+			return true, nil
+		}
+		dir, commit, rel = sub.Dir, sub.OldCommit, subRel
+	}
+	files, err := g.filesAt(dir, commit)
+	if err != nil {
+		return false, err
+	}
+	return !files[rel], nil
+}
+
+// filesAt returns the set of files of a commit of the repository in dir, listed with a single git ls-tree
+func (g *execGitClient) filesAt(dir, commit string) (map[string]bool, error) {
+	g.commitFilesMu.Lock()
+	defer g.commitFilesMu.Unlock()
+
+	key := dir + "\x00" + commit
+	if files, ok := g.commitFiles[key]; ok {
+		return files, nil
+	}
+
+	cmd := exec.Command("git", "ls-tree", "-r", "-z", "--name-only", commit)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]bool)
+	for _, name := range strings.Split(string(out), "\x00") {
+		if name != "" {
+			files[name] = true
+		}
+	}
+	if g.commitFiles == nil {
+		g.commitFiles = make(map[string]map[string]bool)
+	}
+	g.commitFiles[key] = files
+	return files, nil
+}
+
+// resolveGitPath returns the git root directory and the slash-separated path of a file relative to it,
+// as git prints and expects paths on every platform (e.g., in "git show <rev>:<path>")
+// The file is either absolute, relative to the project directory, or already relative to the git root
+// (as listed by GetChangedFiles)
+func (g *execGitClient) resolveGitPath(filePath string) (string, string, error) {
+	// Ensure projectDir is absolute
+	projectDir := g.projectDir
+	if !filepath.IsAbs(projectDir) {
+		var err error
+		projectDir, err = filepath.Abs(projectDir)
+		if err != nil {
+			projectDir = g.projectDir
+		}
+	}
+
+	// Convert to relative path if absolute
+	relPath := filePath
+	if filepath.IsAbs(filePath) {
+		var err error
+		relPath, err = filepath.Rel(projectDir, filePath)
+		if err != nil {
+			relPath = filePath
+		}
+	}
+	relPath = filepath.ToSlash(relPath)
+
+	// Find git root directory
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return "", "", err
+	}
+
+	// Calculate relative path from project dir to git root
+	projectRelToGitRoot, err := filepath.Rel(gitRoot, projectDir)
+	if err != nil {
+		projectRelToGitRoot = ""
+	}
+	projectRelToGitRoot = filepath.ToSlash(projectRelToGitRoot)
+
+	// Build the full relative path from git root
+	// Check if relPath already starts with the prefix (e.g., "go/") to avoid double prefixing like "go/go/..."
+	gitRelPath := relPath
+	if projectRelToGitRoot != "" && projectRelToGitRoot != "." && !strings.HasPrefix(relPath, projectRelToGitRoot+"/") {
+		gitRelPath = path.Join(projectRelToGitRoot, relPath)
+	}
+
+	return gitRoot, gitRelPath, nil
+}
+
+// CheckoutBaseWorktree checks out the base branch into a temporary git worktree
+// It returns the directory corresponding to projectDir inside the worktree and a function removing the worktree
+func CheckoutBaseWorktree(projectDir, baseBranch string) (string, func(), error) {
+	g := &execGitClient{projectDir: projectDir, baseBranch: baseBranch}
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find git root: %w", err)
+	}
+
+	absProjectDir, err := filepath.Abs(projectDir)
+	if err != nil {
+		return "", nil, err
+	}
+	projectRel, err := filepath.Rel(gitRoot, absProjectDir)
+	if err != nil {
+		return "", nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "impact-analyzer-base-")
+	if err != nil {
+		return "", nil, err
+	}
+	worktree := filepath.Join(tmpDir, "worktree")
+
+	cmd := exec.Command("git", "worktree", "add", "--detach", worktree, baseBranch)
+	cmd.Dir = gitRoot
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.RemoveAll(tmpDir)
+		return "", nil, fmt.Errorf("failed to check out %s: %s", baseBranch, strings.TrimSpace(string(out)))
+	}
+
+	cleanup := func() {
+		cmd := exec.Command("git", "worktree", "remove", "--force", worktree)
+		cmd.Dir = gitRoot
+		cmd.Run()
+		os.RemoveAll(tmpDir)
+	}
+
+	return filepath.Join(worktree, projectRel), cleanup, nil
+}
+
+// CheckoutCommit checks out a commit, detached, in the work tree of dir (e.g., a worktree of CheckoutBaseWorktree),
+// so that successive commits are analyzed without creating a worktree for each
+func CheckoutCommit(dir, commit string) error {
+	cmd := exec.Command("git", "checkout", "--quiet", "--force", "--detach", commit)
+	cmd.Dir = dir
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to check out %s: %s", commit, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// DiffCommits returns the commits compared by the git diff of a project: the merge base of the base branch
+// and HEAD, and HEAD. Returns false when the work tree differs from HEAD (including untracked files),
+// as the commits then do not identify the changes
+func DiffCommits(projectDir, baseBranch string) (string, string, bool, error) {
+	g := &execGitClient{projectDir: projectDir}
+	head, err := g.HeadCommit()
+	if err != nil {
+		return "", "", false, gitError(err)
+	}
+	cmd := exec.Command("git", "merge-base", baseBranch, "HEAD")
+	cmd.Dir = projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		// Fallback: the base branch itself, like GetChangedFiles
+		cmd = exec.Command("git", "rev-parse", baseBranch)
+		cmd.Dir = projectDir
+		if out, err = cmd.Output(); err != nil {
+			return "", "", false, gitError(err)
+		}
+	}
+	dirty, err := g.ChangedFilesSince(head)
+	if err != nil {
+		return "", "", false, gitError(err)
+	}
+	return strings.TrimSpace(string(out)), head, len(dirty) == 0, nil
+}
+
+// HeadCommit returns the commit checked out in the project directory
+func (g *execGitClient) HeadCommit() (string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = g.projectDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// ChangedFilesSince returns the absolute paths of the files of the working tree differing from a commit,
+// including untracked files; renamed files are reported under both names
+func (g *execGitClient) ChangedFilesSince(commit string) ([]string, error) {
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, args := range [][]string{
+		{"diff", "--name-only", "--no-renames", commit, "--"},
+		{"ls-files", "--others", "--exclude-standard"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = gitRoot
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(out), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				files = append(files, filepath.Join(gitRoot, filepath.FromSlash(line)))
+			}
+		}
+	}
+	return files, nil
+}
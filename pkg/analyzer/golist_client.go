@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+)
+
+// execGoListClient implements GoListClient using exec.Command
+type execGoListClient struct{}
+
+// NewGoListClient creates a new GoListClient implementation
+func NewGoListClient() GoListClient {
+	return &execGoListClient{}
+}
+
+// goListPackage represents the output of go list -json (internal use)
+type goListPackage struct {
+	ImportPath     string        `json:"ImportPath"`
+	Imports        []string      `json:"Imports"`
+	TestImports    []string      `json:"TestImports"`
+	XTestImports   []string      `json:"XTestImports"`
+	IgnoredGoFiles []string      `json:"IgnoredGoFiles"`
+	Dir            string        `json:"Dir"`
+	EmbedFiles     []string      `json:"EmbedFiles"`
+	Error          *goListError  `json:"Error"`
+	DepsErrors     []goListError `json:"DepsErrors"`
+}
+
+// goListError represents a package error reported by go list -e (internal use)
+type goListError struct {
+	ImportStack []string `json:"ImportStack"`
+	Pos         string   `json:"Pos"`
+	Err         string   `json:"Err"`
+}
+
+// ListPackages returns package information for the given patterns
+func (c *execGoListClient) ListPackages(dir string, patterns ...string) ([]PackageInfo, error) {
+	// -e reports broken packages in the Error field instead of failing the whole listing
+	args := append([]string{"list", "-e", "-json"}, patterns...)
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var packages []PackageInfo
+	decoder := json.NewDecoder(strings.NewReader(string(output)))
+	for decoder.More() {
+		var pkg goListPackage
+		if err := decoder.Decode(&pkg); err != nil {
+			// Skip invalid JSON
+			continue
+		}
+		info := PackageInfo{
+			ImportPath:  pkg.ImportPath,
+			Imports:     pkg.Imports,
+			TestImports: uniqueStrings(append(pkg.TestImports, pkg.XTestImports...)),
+			ToolImports: toolImports(pkg.Dir, pkg.IgnoredGoFiles),
+			Dir:         pkg.Dir,
+			EmbedFiles:  pkg.EmbedFiles,
+		}
+		if pkg.Error != nil {
+			info.Error = pkg.Error.Err
+		}
+		// Import errors are reported as dependency errors; keep those raised by the package itself
+		for _, depErr := range pkg.DepsErrors {
+			if info.Error == "" && len(depErr.ImportStack) > 0 && depErr.ImportStack[len(depErr.ImportStack)-1] == pkg.ImportPath {
+				info.Error = depErr.Pos + ": " + depErr.Err
+			}
+		}
+		packages = append(packages, info)
+	}
+
+	return packages, nil
+}
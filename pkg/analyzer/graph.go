@@ -0,0 +1,216 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// DependencyGraph manages the dependency graph between packages
+type DependencyGraph struct {
+	// Package path -> packages it depends on
+	deps map[string][]string
+	// Package path -> packages imported only by its _test.go files, kept out of deps
+	testDeps map[string][]string
+	// Package path -> packages imported only by its tools files (//go:build tools), kept out of deps
+	toolDeps map[string][]string
+	// Absolute path of an embedded file -> package embedding it via //go:embed
+	embeds map[string]string
+	// Package path -> error reported while loading it
+	loadErrors map[string]string
+	// Lower-case package path -> package paths, to match paths spelled with another case
+	folded map[string][]string
+	// Package path -> packages built from the same directory through symlinks
+	aliases map[string][]string
+	// Module path (project root path)
+	modulePath string
+	// GoListClient for listing packages
+	goListClient GoListClient
+}
+
+// NewDependencyGraph creates a new dependency graph
+func NewDependencyGraph(modulePath string) *DependencyGraph {
+	return &DependencyGraph{
+		deps:         make(map[string][]string),
+		testDeps:     make(map[string][]string),
+		toolDeps:     make(map[string][]string),
+		embeds:       make(map[string]string),
+		loadErrors:   make(map[string]string),
+		folded:       make(map[string][]string),
+		aliases:      make(map[string][]string),
+		modulePath:   modulePath,
+		goListClient: NewGoListClient(),
+	}
+}
+
+// NewDependencyGraphWithClient creates a new dependency graph with a custom GoListClient
+func NewDependencyGraphWithClient(modulePath string, goListClient GoListClient) *DependencyGraph {
+	return &DependencyGraph{
+		deps:         make(map[string][]string),
+		testDeps:     make(map[string][]string),
+		toolDeps:     make(map[string][]string),
+		embeds:       make(map[string]string),
+		loadErrors:   make(map[string]string),
+		folded:       make(map[string][]string),
+		aliases:      make(map[string][]string),
+		modulePath:   modulePath,
+		goListClient: goListClient,
+	}
+}
+
+// Build loads packages matching the patterns and builds the dependency graph
+func (g *DependencyGraph) Build(dir string, patterns ...string) error {
+	packages, err := g.goListClient.ListPackages(dir, patterns...)
+	if err != nil {
+		return fmt.Errorf("failed to run go list: %w", err)
+	}
+	g.addPackages(packages)
+	return nil
+}
+
+// addPackages adds listed packages to the dependency graph
+func (g *DependencyGraph) addPackages(packages []PackageInfo) {
+	for _, pkg := range packages {
+		// Only track packages within the project
+		if !g.isProjectPackage(pkg.ImportPath) {
+			continue
+		}
+
+		// Filter imports to only project packages
+		var projectImports []string
+		for _, imp := range pkg.Imports {
+			if g.isProjectPackage(imp) {
+				projectImports = append(projectImports, imp)
+			}
+		}
+		if _, ok := g.deps[pkg.ImportPath]; !ok {
+			key := strings.ToLower(pkg.ImportPath)
+			g.folded[key] = append(g.folded[key], pkg.ImportPath)
+		}
+		g.deps[pkg.ImportPath] = projectImports
+
+		// Test and tool imports never reach a binary, so they are tracked apart from the main graph
+		if deps := g.extraDeps(pkg.ImportPath, pkg.TestImports, projectImports); len(deps) > 0 {
+			g.testDeps[pkg.ImportPath] = deps
+		}
+		if deps := g.extraDeps(pkg.ImportPath, pkg.ToolImports, projectImports); len(deps) > 0 {
+			g.toolDeps[pkg.ImportPath] = deps
+		}
+		if pkg.Error != "" {
+			g.loadErrors[pkg.ImportPath] = pkg.Error
+		}
+
+		// Track files embedded via //go:embed
+		for _, embedFile := range pkg.EmbedFiles {
+			g.embeds[filepath.Join(pkg.Dir, embedFile)] = pkg.ImportPath
+		}
+	}
+}
+
+// extraDeps returns the project packages among imports that are neither the package itself nor one of its deps
+func (g *DependencyGraph) extraDeps(pkgPath string, imports, deps []string) []string {
+	var result []string
+	for _, imp := range imports {
+		if imp != pkgPath && g.isProjectPackage(imp) && !contains(deps, imp) {
+			result = append(result, imp)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// isProjectPackage determines if a package belongs to the project
+func (g *DependencyGraph) isProjectPackage(pkgPath string) bool {
+	return strings.HasPrefix(pkgPath, g.modulePath)
+}
+
+// GetDirectDeps returns direct dependencies of a package
+func (g *DependencyGraph) GetDirectDeps(pkgPath string) []string {
+	return g.deps[pkgPath]
+}
+
+// GetTestOnlyDeps returns the project packages imported only by the _test.go files of a package
+func (g *DependencyGraph) GetTestOnlyDeps(pkgPath string) []string {
+	return g.testDeps[pkgPath]
+}
+
+// GetToolDeps returns the project packages imported only by the tools files (//go:build tools) of a package
+func (g *DependencyGraph) GetToolDeps(pkgPath string) []string {
+	return g.toolDeps[pkgPath]
+}
+
+// GetImporters returns packages that directly import the given package
+func (g *DependencyGraph) GetImporters(pkgPath string) []string {
+	var importers []string
+	for importer, deps := range g.deps {
+		for _, dep := range deps {
+			if dep == pkgPath {
+				importers = append(importers, importer)
+				break
+			}
+		}
+	}
+	sort.Strings(importers)
+	return importers
+}
+
+// GetAllDeps returns all dependencies (including transitive) of a package
+func (g *DependencyGraph) GetAllDeps(pkgPath string) []string {
+	visited := make(map[string]bool)
+	result := make([]string, 0)
+
+	g.collectAllDeps(pkgPath, visited, &result)
+
+	return result
+}
+
+func (g *DependencyGraph) collectAllDeps(pkgPath string, visited map[string]bool, result *[]string) {
+	if visited[pkgPath] {
+		return
+	}
+	visited[pkgPath] = true
+
+	for _, dep := range g.deps[pkgPath] {
+		*result = append(*result, dep)
+		g.collectAllDeps(dep, visited, result)
+	}
+}
+
+// GetEmbeddingPackage returns the package that embeds the given file via //go:embed
+// Returns an empty string if the file is not embedded
+func (g *DependencyGraph) GetEmbeddingPackage(absPath string) string {
+	return g.embeds[filepath.Clean(absPath)]
+}
+
+// GetEmbeddedFiles returns the files a package embeds via //go:embed, sorted
+func (g *DependencyGraph) GetEmbeddedFiles(pkgPath string) []string {
+	var files []string
+	for file, pkg := range g.embeds {
+		if pkg == pkgPath {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// GetLoadErrors returns the packages that could not be loaded completely with their errors
+func (g *DependencyGraph) GetLoadErrors() map[string]string {
+	return g.loadErrors
+}
+
+// GetAllPackages returns all package paths in the graph
+func (g *DependencyGraph) GetAllPackages() []string {
+	result := make([]string, 0, len(g.deps))
+	for pkgPath := range g.deps {
+		result = append(result, pkgPath)
+	}
+	return result
+}
+
+// HasPackage checks if a package exists in the graph
+func (g *DependencyGraph) HasPackage(pkgPath string) bool {
+	_, ok := g.deps[pkgPath]
+	return ok
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// revisionGitClient is implemented by GitClients that can tell which files changed since a commit
+// The graph cache is disabled with other clients
+type revisionGitClient interface {
+	// HeadCommit returns the commit checked out in the project directory
+	HeadCommit() (string, error)
+	// ChangedFilesSince returns the absolute paths of the files of the working tree differing from a commit,
+	// including untracked files
+	ChangedFilesSince(commit string) ([]string, error)
+}
+
+// moduleFiles are the files changing the packages of the whole module (dependencies, workspaces)
+var moduleFiles = map[string]bool{
+	"go.mod":      true,
+	"go.sum":      true,
+	"go.work":     true,
+	"go.work.sum": true,
+	"modules.txt": true,
+}
+
+// openIndex opens the index of Config.GraphCacheDir, once per Analyzer so that re-analyses reuse its mapping
+// Without Config.Cache, the index also keeps the exported symbols of the files parsed by the analysis
+func (a *Analyzer) openIndex() {
+	if a.config.GraphCacheDir == "" || a.index != nil {
+		return
+	}
+	path := a.indexPath()
+	index, err := OpenIndex(path)
+	if err != nil {
+		a.config.Logger.Debug("failed to open index", "path", path, "error", err)
+		return
+	}
+	a.index = index
+	if a.config.Cache == nil {
+		a.symbolAnalyzer.cache = index
+	}
+}
+
+// saveIndex writes the index if symbols or packages were added since it was saved
+func (a *Analyzer) saveIndex() {
+	if a.index == nil || !a.index.isModified() {
+		return
+	}
+	path := a.indexPath()
+	if err := a.index.Save(path); err != nil {
+		a.config.Logger.Debug("failed to write index", "path", path, "error", err)
+	}
+}
+
+// listPackages lists the packages of the project with go list, reusing the listing of the index:
+// only the directories with files changed since the indexed commit are listed again, and go list is skipped
+// when none changed, which also skips parsing their tools files
+func (a *Analyzer) listPackages() ([]PackageInfo, error) {
+	git, ok := a.config.GitClient.(revisionGitClient)
+	if a.index == nil || !ok {
+		return a.config.GoListClient.ListPackages(a.config.ProjectRoot, "./...")
+	}
+	head, err := git.HeadCommit()
+	if err != nil {
+		return a.config.GoListClient.ListPackages(a.config.ProjectRoot, "./...")
+	}
+
+	packages, ok := a.updateCachedPackages(git)
+	if !ok {
+		a.config.Logger.Debug("graph cache miss", "path", a.indexPath())
+		if packages, err = a.config.GoListClient.ListPackages(a.config.ProjectRoot, "./..."); err != nil {
+			return nil, err
+		}
+	}
+
+	dirty, err := git.ChangedFilesSince(head)
+	if err != nil {
+		return packages, nil
+	}
+	a.index.setPackageListing(&packageListing{commit: head, dirty: dirty, packages: packages})
+	a.saveIndex()
+	return packages, nil
+}
+
+// indexPath returns the index file of the project, named after its module path and root
+func (a *Analyzer) indexPath() string {
+	root, err := filepath.Abs(a.config.ProjectRoot)
+	if err != nil {
+		root = a.config.ProjectRoot
+	}
+	sum := sha256.Sum256([]byte(a.config.ModulePath + "\x00" + root))
+	return filepath.Join(a.config.GraphCacheDir, "index-"+hex.EncodeToString(sum[:8])+".idx")
+}
+
+// updateCachedPackages returns the cached packages, listing the packages of changed directories again
+// Returns false if the whole project must be listed: no usable cache, changed module files or removed packages
+func (a *Analyzer) updateCachedPackages(git revisionGitClient) ([]PackageInfo, bool) {
+	cache, ok := a.index.packageListing()
+	if !ok {
+		return nil, false
+	}
+	// The commit may be gone (e.g., rebased and garbage-collected)
+	changed, err := git.ChangedFilesSince(cache.commit)
+	if err != nil {
+		return nil, false
+	}
+	changed = append(changed, cache.dirty...)
+
+	root, err := filepath.Abs(a.config.ProjectRoot)
+	if err != nil {
+		return nil, false
+	}
+	changedFiles := make(map[string]bool)
+	staleDirs := make(map[string]bool)
+	for _, file := range changed {
+		if rel, err := filepath.Rel(root, file); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if moduleFiles[filepath.Base(file)] {
+			return nil, false
+		}
+		changedFiles[file] = true
+		staleDirs[filepath.Dir(file)] = true
+	}
+	for _, pkg := range cache.packages {
+		for _, embedFile := range pkg.EmbedFiles {
+			if changedFiles[filepath.Join(pkg.Dir, embedFile)] {
+				staleDirs[pkg.Dir] = true
+			}
+		}
+	}
+	if len(staleDirs) == 0 {
+		a.config.Logger.Debug("graph cache hit", "commit", cache.commit)
+		return cache.packages, true
+	}
+
+	var kept []PackageInfo
+	removed := make(map[string]bool)
+	for _, pkg := range cache.packages {
+		if staleDirs[pkg.Dir] {
+			removed[pkg.ImportPath] = true
+		} else {
+			kept = append(kept, pkg)
+		}
+	}
+
+	var patterns []string
+	for dir := range staleDirs {
+		if !a.hasGoFiles(dir) {
+			continue
+		}
+		rel, err := filepath.Rel(root, dir)
+		if err != nil {
+			return nil, false
+		}
+		patterns = append(patterns, "./"+filepath.ToSlash(rel))
+	}
+	sort.Strings(patterns)
+
+	var listed []PackageInfo
+	if len(patterns) > 0 {
+		if listed, err = a.config.GoListClient.ListPackages(root, patterns...); err != nil {
+			return nil, false
+		}
+	}
+	for _, pkg := range listed {
+		delete(removed, pkg.ImportPath)
+	}
+	// Importers of a removed package now fail to load, which only a full listing reports
+	if len(removed) > 0 {
+		return nil, false
+	}
+	a.config.Logger.Debug("graph cache updated", "commit", cache.commit, "listed", patterns)
+	return append(kept, listed...), true
+}
+
+// hasGoFiles checks if a directory contains Go files (including tests)
+func (a *Analyzer) hasGoFiles(dir string) bool {
+	entries, err := a.fs.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".go") {
+			return true
+		}
+	}
+	return false
+}
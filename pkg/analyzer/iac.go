@@ -0,0 +1,58 @@
+package analyzer
+
+import "sort"
+
+// DefaultIaCPatterns are the glob patterns used to detect infrastructure-as-code files when Config.IaCPatterns is empty
+var DefaultIaCPatterns = []string{
+	"**/*.tf",
+	"**/*.tf.json",
+	"**/*.tfvars",
+	"**/*.hcl",
+}
+
+// IaCChange represents a changed infrastructure-as-code file (e.g., Terraform)
+type IaCChange struct {
+	File      string   `json:"file"`                // File path relative to the project root
+	Resources []string `json:"resources,omitempty"` // Resources mapped to the file via FileMappings
+}
+
+// GetIaCChanges returns the changed files that are infrastructure-as-code
+// These files have no Go dependencies, so they are passed through for deployment pipelines to react to
+func (a *Analyzer) GetIaCChanges(changedFiles []string) []IaCChange {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	patterns := a.config.IaCPatterns
+	if len(patterns) == 0 {
+		patterns = DefaultIaCPatterns
+	}
+
+	var changes []IaCChange
+	for _, file := range changedFiles {
+		relPath := a.projectRelPath(file)
+
+		isIaC := false
+		for _, pattern := range patterns {
+			if matchGlob(pattern, relPath) {
+				isIaC = true
+				break
+			}
+		}
+		if !isIaC {
+			continue
+		}
+
+		change := IaCChange{File: relPath}
+		for _, mapping := range a.config.FileMappings {
+			if matchGlob(mapping.Pattern, relPath) {
+				change.Resources = append(change.Resources, mapping.Resources...)
+			}
+		}
+		change.Resources = uniqueStrings(change.Resources)
+		sort.Strings(change.Resources)
+
+		changes = append(changes, change)
+	}
+
+	return changes
+}
@@ -0,0 +1,83 @@
+package analyzer
+
+import "strings"
+
+// removeIgnoredChanges drops the changes to the packages and symbols of Config.IgnoreSymbols,
+// so that routine changes to them (e.g., version constants, telemetry helpers) affect no resource
+// A package whose changed symbols are all ignored is dropped, including its side effects and blank imports
+func (a *Analyzer) removeIgnoredChanges(changes map[string]*packageChanges) {
+	if len(a.config.IgnoreSymbols) == 0 {
+		return
+	}
+
+	// Package path -> ignored symbols (methods by name, interface methods also as "Interface.Method")
+	ignoredSymbols := make(map[string]map[string]bool)
+	var ignoredPackages, ignoredTrees []string
+	for _, entry := range a.config.IgnoreSymbols {
+		if prefix, ok := strings.CutSuffix(entry, "/..."); ok {
+			ignoredTrees = append(ignoredTrees, a.ResolvePackagePath(prefix))
+			continue
+		}
+		if pkgPath := a.ResolvePackagePath(entry); a.graph.HasPackage(pkgPath) {
+			ignoredPackages = append(ignoredPackages, pkgPath)
+			continue
+		}
+		ref, err := a.ParseSymbolRef(entry)
+		if err != nil {
+			continue
+		}
+		if ignoredSymbols[ref.Package] == nil {
+			ignoredSymbols[ref.Package] = make(map[string]bool)
+		}
+		if ref.Method != "" {
+			ignoredSymbols[ref.Package][ref.Method] = true
+			ignoredSymbols[ref.Package][ref.Symbol+"."+ref.Method] = true
+		} else {
+			ignoredSymbols[ref.Package][ref.Symbol] = true
+		}
+	}
+
+	for pkgPath, pc := range changes {
+		ignored := contains(ignoredPackages, pkgPath)
+		for _, tree := range ignoredTrees {
+			ignored = ignored || pkgPath == tree || strings.HasPrefix(pkgPath, tree+"/")
+		}
+		if ignored {
+			a.config.Logger.Debug("ignoring changed package", "package", pkgPath)
+			delete(changes, pkgPath)
+			continue
+		}
+
+		symbols := ignoredSymbols[pkgPath]
+		if symbols == nil {
+			continue
+		}
+		changed := len(pc.symbolNames())
+		keep := func(names []string) []string {
+			var kept []string
+			for _, name := range names {
+				if !symbols[name] {
+					kept = append(kept, name)
+				}
+			}
+			return kept
+		}
+		pc.info.symbols = keep(pc.info.symbols)
+		pc.wireSymbols = keep(pc.wireSymbols)
+		var methods []InterfaceMethodRange
+		for _, m := range pc.info.interfaceMethods {
+			if !symbols[m.InterfaceName] && !symbols[m.InterfaceName+"."+m.MethodName] {
+				methods = append(methods, m)
+			}
+		}
+		pc.info.interfaceMethods = methods
+
+		// When every changed symbol is ignored, the side effects and unexported changes of the package are
+		// those of the ignored symbols (e.g., var Version = buildinfo()), so the package change is dropped
+		// rather than affecting every importer
+		if changed > 0 && len(pc.symbolNames()) == 0 && pc.wholePackage == "" && len(pc.unknown) == 0 {
+			a.config.Logger.Debug("ignoring changed package", "package", pkgPath, "reason", "every changed symbol is ignored")
+			delete(changes, pkgPath)
+		}
+	}
+}
@@ -0,0 +1,287 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindBlankImports returns the packages imported with the blank identifier (import _ "pkg")
+// Such imports exist for their side effects (e.g., database drivers), so symbol usage cannot be detected
+func (s *SymbolAnalyzer) FindBlankImports(pkgDir string) ([]string, error) {
+	return s.findNamedImports(pkgDir, "_")
+}
+
+// findDotImportedUses returns the unqualified identifiers in node that may refer to symbols of a dot-imported package
+// Without type information a local identifier with the same name cannot be told apart, so matches are conservative
+func findDotImportedUses(node ast.Node, symbols map[string]bool) []*ast.Ident {
+	var uses []*ast.Ident
+	var inspect func(n ast.Node) bool
+	inspect = func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.SelectorExpr:
+			// x.Sel is a field or method name, not a package-level symbol
+			ast.Inspect(x.X, inspect)
+			return false
+		case *ast.Ident:
+			if symbols[x.Name] {
+				uses = append(uses, x)
+			}
+		}
+		return true
+	}
+	ast.Inspect(node, inspect)
+	return uses
+}
+
+// findNamedImports returns the import paths imported with the given name in a package directory
+func (s *SymbolAnalyzer) findNamedImports(pkgDir string, name string) ([]string, error) {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var imports []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, parser.ImportsOnly|parser.ParseComments)
+		// Tools files blank-import tools only to pin their versions; they are not part of the package
+		if err != nil || isToolsFile(file) {
+			continue
+		}
+
+		for _, imp := range file.Imports {
+			if imp.Name != nil && imp.Name.Name == name {
+				imports = append(imports, strings.Trim(imp.Path.Value, `"`))
+			}
+		}
+	}
+
+	return uniqueStrings(imports), nil
+}
+
+// buildBlankImporters maps each project package to the packages that blank-import it
+func (a *Analyzer) buildBlankImporters() map[string][]string {
+	importers := make(map[string][]string)
+	for _, pkgPath := range a.graph.GetAllPackages() {
+		blankImports, err := a.symbolAnalyzer.FindBlankImports(a.getPkgDir(pkgPath))
+		if err != nil {
+			continue
+		}
+		for _, imp := range blankImports {
+			if a.graph.HasPackage(imp) {
+				importers[imp] = append(importers[imp], pkgPath)
+			}
+		}
+	}
+	for imp := range importers {
+		sort.Strings(importers[imp])
+	}
+	return importers
+}
+
+// isBlankImportedBy checks if the resource's package or one of its dependencies blank-imports the changed package
+// Any change to a blank-imported package affects its importers since usage cannot be detected syntactically
+func (a *Analyzer) isBlankImportedBy(resource *Resource, changedPkgPath string) bool {
+	importers := a.blankImporters[changedPkgPath]
+	if len(importers) == 0 {
+		return false
+	}
+
+	deps := append([]string{resource.Package}, a.graph.GetAllDeps(resource.Package)...)
+	for _, importer := range importers {
+		if contains(deps, importer) {
+			return true
+		}
+	}
+	return false
+}
+
+// FindEmbeddingInterfaces returns the interfaces in a package that embed one of the given interfaces of another package
+func (s *SymbolAnalyzer) FindEmbeddingInterfaces(pkgDir string, targetPkgPath string, interfaceNames []string) ([]string, error) {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+
+	nameSet := make(map[string]bool)
+	for _, name := range interfaceNames {
+		nameSet[name] = true
+	}
+
+	var result []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, 0)
+		if err != nil {
+			continue
+		}
+
+		// Find the import alias for the target package
+		importAlias := ""
+		for _, imp := range file.Imports {
+			if strings.Trim(imp.Path.Value, `"`) != targetPkgPath {
+				continue
+			}
+			if imp.Name != nil {
+				importAlias = imp.Name.Name
+			} else {
+				importAlias = filepath.Base(targetPkgPath)
+			}
+			break
+		}
+		if importAlias == "" || importAlias == "_" {
+			continue
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			typeSpec, ok := n.(*ast.TypeSpec)
+			if !ok {
+				return true
+			}
+			iface, ok := typeSpec.Type.(*ast.InterfaceType)
+			if !ok {
+				return false
+			}
+			for _, field := range iface.Methods.List {
+				// Embedded interfaces have no names
+				if len(field.Names) > 0 {
+					continue
+				}
+				var embedded string
+				switch t := field.Type.(type) {
+				case *ast.SelectorExpr:
+					if ident, ok := t.X.(*ast.Ident); ok && ident.Name == importAlias {
+						embedded = t.Sel.Name
+					}
+				case *ast.Ident:
+					if importAlias == "." {
+						embedded = t.Name
+					}
+				}
+				if nameSet[embedded] {
+					result = append(result, typeSpec.Name.Name)
+					break
+				}
+			}
+			return false
+		})
+	}
+
+	return uniqueStrings(result), nil
+}
+
+// findEmbeddingInterfaceMethods resolves interfaces in other packages that embed the changed interfaces,
+// transitively, and returns the changed methods as seen through them (package path -> methods)
+func (a *Analyzer) findEmbeddingInterfaceMethods(pkgPath string, methods []InterfaceMethodRange) map[string][]InterfaceMethodRange {
+	type item struct {
+		pkgPath string
+		methods []InterfaceMethodRange
+	}
+
+	result := make(map[string][]InterfaceMethodRange)
+	seen := make(map[string]bool)
+	for _, m := range methods {
+		seen[pkgPath+"."+m.InterfaceName+"."+m.MethodName] = true
+	}
+
+	queue := []item{{pkgPath: pkgPath, methods: methods}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		// Group the methods by interface
+		methodsByInterface := make(map[string][]string)
+		var interfaceNames []string
+		for _, m := range current.methods {
+			if _, ok := methodsByInterface[m.InterfaceName]; !ok {
+				interfaceNames = append(interfaceNames, m.InterfaceName)
+			}
+			methodsByInterface[m.InterfaceName] = append(methodsByInterface[m.InterfaceName], m.MethodName)
+		}
+
+		for _, importer := range a.graph.GetImporters(current.pkgPath) {
+			byInterface := make(map[string][]InterfaceMethodRange)
+			for _, ifaceName := range interfaceNames {
+				embedders, err := a.symbolAnalyzer.FindEmbeddingInterfaces(a.getPkgDir(importer), current.pkgPath, []string{ifaceName})
+				if err != nil {
+					continue
+				}
+				for _, embedder := range embedders {
+					for _, methodName := range methodsByInterface[ifaceName] {
+						key := importer + "." + embedder + "." + methodName
+						if seen[key] {
+							continue
+						}
+						seen[key] = true
+						byInterface[embedder] = append(byInterface[embedder], InterfaceMethodRange{InterfaceName: embedder, MethodName: methodName})
+					}
+				}
+			}
+
+			var newMethods []InterfaceMethodRange
+			for _, ms := range byInterface {
+				newMethods = append(newMethods, ms...)
+			}
+			if len(newMethods) == 0 {
+				continue
+			}
+			result[importer] = append(result[importer], newMethods...)
+			queue = append(queue, item{pkgPath: importer, methods: newMethods})
+		}
+	}
+
+	return result
+}
+
+// HasAssemblyOrLinkname checks if a package has assembly (.s) files or //go:linkname directives
+// Both link symbols in ways that cannot be seen from Go selectors. Only files mentioning go:linkname are
+// parsed, and only the comments starting a line count as directives (not strings or other comments)
+func (s *SymbolAnalyzer) HasAssemblyOrLinkname(pkgDir string) bool {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return false
+	}
+
+	var candidates []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		if strings.HasSuffix(entry.Name(), ".s") {
+			return true
+		}
+		if strings.HasSuffix(entry.Name(), ".go") && !strings.HasSuffix(entry.Name(), "_test.go") {
+			candidates = append(candidates, filepath.Join(pkgDir, entry.Name()))
+		}
+	}
+
+	for _, filePath := range candidates {
+		content, err := s.fs.ReadFile(filePath)
+		if err != nil || !bytes.Contains(content, []byte("//go:linkname ")) {
+			continue
+		}
+		file, err := parser.ParseFile(s.fset, filePath, content, parser.ParseComments)
+		if err != nil {
+			continue
+		}
+		for _, group := range file.Comments {
+			for _, c := range group.List {
+				if strings.HasPrefix(c.Text, "//go:linkname ") && s.fset.Position(c.Slash).Column == 1 {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,395 @@
+package analyzer
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// An index file is little-endian and made of fixed-size entries, so that it is read in place once mapped:
+//
+//	header    "IAIX", then the uint32 fields indexed by the hdr* constants
+//	lists     uint32 string IDs; a list is a (start, length) range of them
+//	symbols   {key, list start, list length} entries sorted by key (the exported symbols of a file content)
+//	packages  {import path, dir, error, then the (start, length) lists of imports, test imports, tool imports, embed files}
+//	strings   uint32 offsets (string count + 1) followed by the string bytes; string 0 is ""
+const (
+	indexMagic   = "IAIX"
+	indexVersion = 1
+
+	indexHeaderSize  = len(indexMagic) + hdrFields*4
+	indexSymbolSize  = 3 * 4
+	indexPackageSize = 3*4 + 4*2*4
+)
+
+// Header fields of an index file
+const (
+	hdrVersion = iota
+	hdrStringCount
+	hdrStringsOffset
+	hdrListCount
+	hdrListsOffset
+	hdrSymbolCount
+	hdrSymbolsOffset
+	hdrPackageCount
+	hdrPackagesOffset
+	// hdrCommit is the string ID of the commit the packages were listed at
+	hdrCommit
+	// hdrDirtyStart and hdrDirtyLength are the list of files differing from the commit at that time
+	hdrDirtyStart
+	hdrDirtyLength
+	hdrFields
+)
+
+// Index is a compact binary index of the exported symbols of Go files (by content) and of the package
+// listing of a project (import edges), kept between runs
+// The file is memory-mapped and read in place, so opening even a large index takes no deserialization:
+// symbol lookups binary search the mapped entries
+// Index implements Cache; symbols added with Set are kept in memory until Save
+type Index struct {
+	mu sync.RWMutex
+	// data is the mapped file (nil for an empty index) and hdr its header fields
+	data  []byte
+	hdr   [hdrFields]uint32
+	unmap func() error
+	// symbols were added with Set since the index was opened
+	symbols map[string][]string
+	// listing replaces the mapped package listing when set
+	listing *packageListing
+	// modified is set when symbols or packages were added since the index was saved
+	modified bool
+}
+
+// packageListing is the package listing of a project at a commit
+type packageListing struct {
+	commit string
+	// dirty are the files differing from the commit when the packages were listed
+	dirty    []string
+	packages []PackageInfo
+}
+
+// OpenIndex maps an index file
+// A missing file, or a file of another format version, opens an empty index
+func OpenIndex(path string) (*Index, error) {
+	ix := &Index{symbols: make(map[string][]string)}
+	data, unmap, err := mapFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return ix, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ix.load(data) {
+		return ix, unmap()
+	}
+	ix.unmap = unmap
+	return ix, nil
+}
+
+// load reads the header of a mapped file and checks that its sections are within the file
+func (ix *Index) load(data []byte) bool {
+	if len(data) < indexHeaderSize || string(data[:len(indexMagic)]) != indexMagic {
+		return false
+	}
+	var hdr [hdrFields]uint32
+	for i := range hdr {
+		hdr[i] = binary.LittleEndian.Uint32(data[len(indexMagic)+i*4:])
+	}
+	if hdr[hdrVersion] != indexVersion {
+		return false
+	}
+	size := uint64(len(data))
+	sections := [][3]uint64{
+		{uint64(hdr[hdrStringsOffset]), uint64(hdr[hdrStringCount]) + 1, 4},
+		{uint64(hdr[hdrListsOffset]), uint64(hdr[hdrListCount]), 4},
+		{uint64(hdr[hdrSymbolsOffset]), uint64(hdr[hdrSymbolCount]), indexSymbolSize},
+		{uint64(hdr[hdrPackagesOffset]), uint64(hdr[hdrPackageCount]), indexPackageSize},
+	}
+	for _, s := range sections {
+		if s[0] < uint64(indexHeaderSize) || s[0]+s[1]*s[2] > size {
+			return false
+		}
+	}
+	ix.data, ix.hdr = data, hdr
+	return true
+}
+
+// u32 reads a field of the mapped file; callers only pass offsets within the sections checked by load
+func (ix *Index) u32(offset uint64) uint32 {
+	return binary.LittleEndian.Uint32(ix.data[offset:])
+}
+
+// stringBytes returns the bytes of a string of the mapped file, or nil if the ID or its offsets are invalid
+func (ix *Index) stringBytes(id uint32) []byte {
+	count := uint64(ix.hdr[hdrStringCount])
+	if uint64(id) >= count {
+		return nil
+	}
+	table := uint64(ix.hdr[hdrStringsOffset])
+	base := table + (count+1)*4
+	start := base + uint64(ix.u32(table+uint64(id)*4))
+	end := base + uint64(ix.u32(table+uint64(id)*4+4))
+	if start > end || end > uint64(len(ix.data)) {
+		return nil
+	}
+	return ix.data[start:end]
+}
+
+// list returns the strings of a list of the mapped file
+func (ix *Index) list(start, length uint32) []string {
+	if length == 0 || uint64(start)+uint64(length) > uint64(ix.hdr[hdrListCount]) {
+		return nil
+	}
+	offset := uint64(ix.hdr[hdrListsOffset]) + uint64(start)*4
+	result := make([]string, length)
+	for i := range result {
+		result[i] = string(ix.stringBytes(ix.u32(offset + uint64(i)*4)))
+	}
+	return result
+}
+
+// symbolEntry returns the offset of a symbol entry of the mapped file
+func (ix *Index) symbolEntry(i int) uint64 {
+	return uint64(ix.hdr[hdrSymbolsOffset]) + uint64(i)*indexSymbolSize
+}
+
+// Get returns the symbols stored for a key
+func (ix *Index) Get(key string) ([]string, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	if symbols, ok := ix.symbols[key]; ok {
+		return symbols, true
+	}
+
+	n := int(ix.hdr[hdrSymbolCount])
+	i := sort.Search(n, func(i int) bool {
+		return string(ix.stringBytes(ix.u32(ix.symbolEntry(i)))) >= key
+	})
+	if i == n || string(ix.stringBytes(ix.u32(ix.symbolEntry(i)))) != key {
+		return nil, false
+	}
+	entry := ix.symbolEntry(i)
+	return ix.list(ix.u32(entry+4), ix.u32(entry+8)), true
+}
+
+// Set stores the symbols for a key
+func (ix *Index) Set(key string, symbols []string) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.symbols[key] = symbols
+	ix.modified = true
+}
+
+// packageListing returns the package listing of the index, or false if it has none
+func (ix *Index) packageListing() (*packageListing, bool) {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.currentListing()
+}
+
+// currentListing returns the package listing set or mapped; ix.mu must be held
+func (ix *Index) currentListing() (*packageListing, bool) {
+	if ix.listing != nil {
+		return ix.listing, true
+	}
+	commit := string(ix.stringBytes(ix.hdr[hdrCommit]))
+	if commit == "" {
+		return nil, false
+	}
+
+	listing := &packageListing{
+		commit:   commit,
+		dirty:    ix.list(ix.hdr[hdrDirtyStart], ix.hdr[hdrDirtyLength]),
+		packages: make([]PackageInfo, ix.hdr[hdrPackageCount]),
+	}
+	for i := range listing.packages {
+		entry := uint64(ix.hdr[hdrPackagesOffset]) + uint64(i)*indexPackageSize
+		listing.packages[i] = PackageInfo{
+			ImportPath:  string(ix.stringBytes(ix.u32(entry))),
+			Dir:         string(ix.stringBytes(ix.u32(entry + 4))),
+			Error:       string(ix.stringBytes(ix.u32(entry + 8))),
+			Imports:     ix.list(ix.u32(entry+12), ix.u32(entry+16)),
+			TestImports: ix.list(ix.u32(entry+20), ix.u32(entry+24)),
+			ToolImports: ix.list(ix.u32(entry+28), ix.u32(entry+32)),
+			EmbedFiles:  ix.list(ix.u32(entry+36), ix.u32(entry+40)),
+		}
+	}
+	return listing, true
+}
+
+// setPackageListing replaces the package listing of the index
+func (ix *Index) setPackageListing(listing *packageListing) {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	ix.listing = listing
+	ix.modified = true
+}
+
+// isModified checks if symbols or packages were added since the index was saved
+func (ix *Index) isModified() bool {
+	ix.mu.RLock()
+	defer ix.mu.RUnlock()
+	return ix.modified
+}
+
+// Save writes the index with the symbols and packages added since it was opened
+// The file is written through a temporary file, so that concurrent runs never map a partial file;
+// the index keeps reading the file it mapped
+func (ix *Index) Save(path string) error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+
+	symbols := make(map[string][]string, int(ix.hdr[hdrSymbolCount])+len(ix.symbols))
+	for i := 0; i < int(ix.hdr[hdrSymbolCount]); i++ {
+		entry := ix.symbolEntry(i)
+		symbols[string(ix.stringBytes(ix.u32(entry)))] = ix.list(ix.u32(entry+4), ix.u32(entry+8))
+	}
+	for key, s := range ix.symbols {
+		symbols[key] = s
+	}
+	listing, _ := ix.currentListing()
+	content, err := encodeIndex(symbols, listing)
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(path, content); err != nil {
+		return err
+	}
+	ix.modified = false
+	return nil
+}
+
+// Close unmaps the index file; afterwards, the index only holds the symbols and packages added since it was opened
+func (ix *Index) Close() error {
+	ix.mu.Lock()
+	defer ix.mu.Unlock()
+	if ix.unmap == nil {
+		return nil
+	}
+	err := ix.unmap()
+	ix.data, ix.hdr, ix.unmap = nil, [hdrFields]uint32{}, nil
+	return err
+}
+
+// indexWriter interns the strings and lists of an index file
+type indexWriter struct {
+	ids     map[string]uint32
+	strings []string
+	lists   []uint32
+}
+
+// str returns the ID of a string
+func (w *indexWriter) str(s string) uint32 {
+	if id, ok := w.ids[s]; ok {
+		return id
+	}
+	id := uint32(len(w.strings))
+	w.ids[s] = id
+	w.strings = append(w.strings, s)
+	return id
+}
+
+// list appends a list of strings and returns its start and length
+func (w *indexWriter) list(values []string) (uint32, uint32) {
+	start := uint32(len(w.lists))
+	for _, v := range values {
+		w.lists = append(w.lists, w.str(v))
+	}
+	return start, uint32(len(values))
+}
+
+// encodeIndex encodes the symbols and the package listing (optional) as an index file
+func encodeIndex(symbols map[string][]string, listing *packageListing) ([]byte, error) {
+	w := &indexWriter{ids: make(map[string]uint32)}
+	w.str("")
+
+	keys := make([]string, 0, len(symbols))
+	for key := range symbols {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var symbolEntries []uint32
+	for _, key := range keys {
+		start, length := w.list(symbols[key])
+		symbolEntries = append(symbolEntries, w.str(key), start, length)
+	}
+
+	var hdr [hdrFields]uint32
+	var packageEntries []uint32
+	if listing != nil {
+		hdr[hdrCommit] = w.str(listing.commit)
+		hdr[hdrDirtyStart], hdr[hdrDirtyLength] = w.list(listing.dirty)
+		for _, pkg := range listing.packages {
+			packageEntries = append(packageEntries, w.str(pkg.ImportPath), w.str(pkg.Dir), w.str(pkg.Error))
+			for _, values := range [][]string{pkg.Imports, pkg.TestImports, pkg.ToolImports, pkg.EmbedFiles} {
+				start, length := w.list(values)
+				packageEntries = append(packageEntries, start, length)
+			}
+		}
+	}
+
+	// The uint32 sections come first so that they stay aligned
+	size := uint64(indexHeaderSize)
+	hdr[hdrListsOffset], hdr[hdrListCount] = uint32(size), uint32(len(w.lists))
+	size += uint64(len(w.lists)) * 4
+	hdr[hdrSymbolsOffset], hdr[hdrSymbolCount] = uint32(size), uint32(len(keys))
+	size += uint64(len(symbolEntries)) * 4
+	hdr[hdrPackagesOffset] = uint32(size)
+	if listing != nil {
+		hdr[hdrPackageCount] = uint32(len(listing.packages))
+	}
+	size += uint64(len(packageEntries)) * 4
+	hdr[hdrStringsOffset], hdr[hdrStringCount] = uint32(size), uint32(len(w.strings))
+	size += uint64(len(w.strings)+1) * 4
+	for _, s := range w.strings {
+		size += uint64(len(s))
+	}
+	if size > math.MaxUint32 {
+		return nil, fmt.Errorf("index of %d bytes exceeds the 4 GiB limit of the format", size)
+	}
+	hdr[hdrVersion] = indexVersion
+
+	buf := make([]byte, 0, size)
+	buf = append(buf, indexMagic...)
+	for _, sections := range [][]uint32{hdr[:], w.lists, symbolEntries, packageEntries} {
+		for _, v := range sections {
+			buf = binary.LittleEndian.AppendUint32(buf, v)
+		}
+	}
+	offset := uint32(0)
+	for _, s := range w.strings {
+		buf = binary.LittleEndian.AppendUint32(buf, offset)
+		offset += uint32(len(s))
+	}
+	buf = binary.LittleEndian.AppendUint32(buf, offset)
+	for _, s := range w.strings {
+		buf = append(buf, s...)
+	}
+	return buf, nil
+}
+
+// writeFileAtomic writes a file through a temporary file, so that concurrent runs never read a partial file
+func writeFileAtomic(path string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
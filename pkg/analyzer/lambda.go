@@ -0,0 +1,115 @@
+package analyzer
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// lambdaPackage is the import path of the AWS Lambda Go runtime
+const lambdaPackage = "github.com/aws/aws-lambda-go/lambda"
+
+// lambdaStartFuncs are the functions of the runtime that run a handler
+var lambdaStartFuncs = map[string]bool{
+	"Start":            true,
+	"StartWithOptions": true,
+	"StartHandler":     true,
+	"StartWithContext": true,
+}
+
+// extractLambdaFunctions returns a function resource for each project package calling lambda.Start
+// Functions are named after the directory of their package (e.g., functions/create-order -> "create-order"),
+// or its path relative to the project root when the name is already taken
+func (a *Analyzer) extractLambdaFunctions(taken map[string]bool) []Resource {
+	packages := a.graph.GetAllPackages()
+	sort.Strings(packages)
+
+	var functions []Resource
+	for _, pkgPath := range packages {
+		pkgDir := a.getPkgDir(pkgPath)
+		sourceFile := a.findLambdaStart(pkgDir)
+		if sourceFile == "" {
+			continue
+		}
+
+		relDir := strings.TrimPrefix(strings.TrimPrefix(pkgPath, a.config.ModulePath), "/")
+		name := filepath.Base(pkgDir)
+		if relDir == "" || taken[name] {
+			name = relDir
+		}
+		if name == "" || taken[name] {
+			continue
+		}
+		taken[name] = true
+
+		functions = append(functions, Resource{
+			Name:        name,
+			Type:        ResourceTypeFunction,
+			Package:     pkgPath,
+			SourceFile:  sourceFile,
+			Description: "AWS Lambda function",
+		})
+	}
+	return functions
+}
+
+// findLambdaStart returns the file of a package directory calling lambda.Start (or one of its variants)
+// Returns an empty string if the package does not start a Lambda handler
+func (a *Analyzer) findLambdaStart(pkgDir string) string {
+	entries, err := a.fs.ReadDir(pkgDir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		filePath := filepath.Join(pkgDir, entry.Name())
+		content, err := a.fs.ReadFile(filePath)
+		// Most files do not mention the runtime: skip them before parsing
+		if err != nil || !bytes.Contains(content, []byte(lambdaPackage)) {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filePath, content, 0)
+		if err != nil {
+			continue
+		}
+
+		alias := ""
+		for _, imp := range file.Imports {
+			if path, _ := strconv.Unquote(imp.Path.Value); path == lambdaPackage {
+				alias = "lambda"
+				if imp.Name != nil {
+					alias = imp.Name.Name
+				}
+			}
+		}
+		if alias == "" || alias == "_" {
+			continue
+		}
+
+		found := false
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || found {
+				return !found
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && lambdaStartFuncs[sel.Sel.Name] {
+				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == alias {
+					found = true
+				}
+			}
+			return !found
+		})
+		if found {
+			return filePath
+		}
+	}
+	return ""
+}
@@ -0,0 +1,144 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Table names changed by schema statements (CREATE/ALTER/DROP/TRUNCATE TABLE, indexes, renames)
+var (
+	migrationTableRegex  = regexp.MustCompile(`(?is)\b(?:CREATE|ALTER|DROP|TRUNCATE)\s+(?:(?:GLOBAL|LOCAL)\s+)?(?:TEMP(?:ORARY)?\s+|UNLOGGED\s+)?TABLE\s+(?:IF\s+(?:NOT\s+)?EXISTS\s+)?(?:ONLY\s+)?([\w."` + "`" + `]+)`)
+	migrationIndexRegex  = regexp.MustCompile(`(?is)\bCREATE\s+(?:UNIQUE\s+)?INDEX\b.*?\bON\s+(?:ONLY\s+)?([\w."` + "`" + `]+)`)
+	migrationRenameRegex = regexp.MustCompile(`(?is)\bRENAME\s+TO\s+([\w."` + "`" + `]+)`)
+)
+
+// queryTableRegex matches the tables a query reads or writes (FROM, JOIN, INTO, UPDATE)
+// Tables listed after a comma in a FROM clause are not matched
+var queryTableRegex = regexp.MustCompile(`(?is)\b(?:FROM|JOIN|INTO|UPDATE)\s+(?:ONLY\s+)?([\w."` + "`" + `]+)`)
+
+// tableName normalizes a table reference: quotes and the schema qualifier are removed, and the name is lowercased
+func tableName(ref string) string {
+	ref = strings.NewReplacer(`"`, "", "`", "").Replace(ref)
+	if i := strings.LastIndex(ref, "."); i >= 0 {
+		ref = ref[i+1:]
+	}
+	return strings.ToLower(ref)
+}
+
+// migrationTables returns the tables changed by the statements of a migration covering any of the lines
+// If lines is nil, the tables of all statements are returned
+func migrationTables(content []byte, lines []int) []string {
+	var tables []string
+	line := 1
+	for _, stmt := range strings.SplitAfter(string(content), ";") {
+		// Statements start after the newlines following the previous one
+		trimmed := strings.TrimLeft(stmt, " \t\r\n")
+		start, end := line+strings.Count(stmt[:len(stmt)-len(trimmed)], "\n"), line+strings.Count(stmt, "\n")
+		line = end
+		if !linesInRange(lines, start, end) {
+			continue
+		}
+		stmt = stripSQLComments(stmt)
+		for _, re := range []*regexp.Regexp{migrationTableRegex, migrationIndexRegex, migrationRenameRegex} {
+			for _, m := range re.FindAllStringSubmatch(stmt, -1) {
+				tables = append(tables, tableName(m[1]))
+			}
+		}
+	}
+	return uniqueStrings(tables)
+}
+
+// linesInRange checks if any of the lines is within [start, end]; nil lines match every range
+func linesInRange(lines []int, start, end int) bool {
+	if lines == nil {
+		return true
+	}
+	for _, l := range lines {
+		if l >= start && l <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// stripSQLComments removes "--" line comments, so that commented-out statements do not count
+func stripSQLComments(sql string) string {
+	lines := strings.Split(sql, "\n")
+	for i, l := range lines {
+		if idx := strings.Index(l, "--"); idx >= 0 {
+			lines[i] = l[:idx]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// sqlcQueriesByTable maps the tables referenced by the queries of a sqlc package to the query names
+func (a *Analyzer) sqlcQueriesByTable(pkg *sqlcPackage) map[string][]string {
+	byTable := make(map[string][]string)
+	for _, q := range pkg.Queries {
+		absPath := filepath.Join(a.config.ProjectRoot, filepath.FromSlash(q))
+		files := []string{absPath}
+		if entries, err := a.fs.ReadDir(absPath); err == nil {
+			files = files[:0]
+			for _, entry := range entries {
+				if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".sql") {
+					files = append(files, filepath.Join(absPath, entry.Name()))
+				}
+			}
+		}
+
+		for _, file := range files {
+			content, err := a.fs.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			current := ""
+			var body strings.Builder
+			flush := func() {
+				if current == "" {
+					return
+				}
+				for _, m := range queryTableRegex.FindAllStringSubmatch(stripSQLComments(body.String()), -1) {
+					table := tableName(m[1])
+					byTable[table] = append(byTable[table], current)
+				}
+			}
+			for _, line := range strings.Split(string(content), "\n") {
+				if m := sqlcQueryNameRegex.FindStringSubmatch(line); m != nil {
+					flush()
+					current = m[1]
+					body.Reset()
+					continue
+				}
+				body.WriteString(line + "\n")
+			}
+			flush()
+		}
+	}
+	for table := range byTable {
+		byTable[table] = uniqueStrings(byTable[table])
+	}
+	return byTable
+}
+
+// migrationQueries returns a parser of changed migration files for the sqlc package:
+// the queries referencing the tables changed by the migration
+func (a *Analyzer) migrationQueries(pkg *sqlcPackage) func(content []byte, lines []int) []string {
+	return func(content []byte, lines []int) []string {
+		tables := migrationTables(content, lines)
+		if len(tables) == 0 {
+			return nil
+		}
+		byTable := a.sqlcQueriesByTable(pkg)
+		var names []string
+		for _, table := range tables {
+			names = append(names, byTable[table]...)
+		}
+		sort.Strings(names)
+		a.config.Logger.Debug("migration changes tables", "package", path.Clean(pkg.Out), "tables", tables, "queries", names)
+		return uniqueStrings(names)
+	}
+}
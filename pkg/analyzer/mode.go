@@ -0,0 +1,38 @@
+package analyzer
+
+// AnalysisMode is a preset trading the precision of the impact analysis for speed
+type AnalysisMode string
+
+const (
+	// ModeFast affects every resource depending on a changed package, without checking symbol usage
+	// or verifying intermediate packages and chain links
+	ModeFast AnalysisMode = "fast"
+	// ModeBalanced checks the usage of changed symbols through intermediate packages (default)
+	ModeBalanced AnalysisMode = "balanced"
+	// ModeThorough is ModeBalanced with conservative settings: symbols reached via reflection widen the impact, and files
+	// without line-level diff information affect their whole package
+	ModeThorough AnalysisMode = "thorough"
+)
+
+// IsValid checks if the mode is one of the known modes
+func (m AnalysisMode) IsValid() bool {
+	return m == ModeFast || m == ModeBalanced || m == ModeThorough
+}
+
+// applyDefaults sets the settings of the mode left empty in the configuration
+// Explicit settings (e.g., -reflection off with the thorough mode) take precedence
+func (m AnalysisMode) applyDefaults(cfg *Config) {
+	switch m {
+	case ModeFast:
+		if cfg.FallbackPolicy == "" {
+			cfg.FallbackPolicy = FallbackWholePackage
+		}
+	case ModeThorough:
+		if cfg.FallbackPolicy == "" {
+			cfg.FallbackPolicy = FallbackWholePackage
+		}
+		if cfg.Reflection == "" {
+			cfg.Reflection = ReflectionConservative
+		}
+	}
+}
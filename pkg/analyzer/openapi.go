@@ -0,0 +1,279 @@
+package analyzer
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// OpenAPISpec links an OpenAPI spec to the Go package generated from it (oapi-codegen, ogen)
+// A change to the spec only affects resources implementing or calling the changed operations
+type OpenAPISpec struct {
+	// Spec is the path of the spec (YAML or pretty-printed JSON) relative to the project root
+	Spec string `json:"spec"`
+	// Package is the directory of the generated package relative to the project root
+	Package string `json:"package"`
+}
+
+// openAPIMethods are the HTTP methods of the operations of a path item
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+var (
+	// openAPIKeyRegex matches a mapping key of a YAML or JSON line (e.g., `get:` or `"get": {`)
+	openAPIKeyRegex = regexp.MustCompile(`^(?:"([^"]*)"|'([^']*)'|([^\s"'#{}\[\],-][^:]*?))\s*:(?:\s|$)`)
+	// openAPIOperationIDRegex matches the operationId of an operation
+	openAPIOperationIDRegex = regexp.MustCompile(`^"?operationId"?\s*:\s*["']?([^"',\s]+)`)
+	// openAPIRefRegex matches local references to reusable objects (OpenAPI 3 components, Swagger 2 definitions)
+	openAPIRefRegex = regexp.MustCompile(`#/(components/[\w.-]+|definitions|parameters|responses)/([\w.-]+)`)
+)
+
+// openAPIOperation is an operation of an OpenAPI spec
+type openAPIOperation struct {
+	path   string
+	method string
+	id     string
+	// refs are the reusable objects referenced by the operation (e.g., "#/components/schemas/User")
+	refs []string
+}
+
+// name returns the Go method name generated for the operation:
+// the operationId in PascalCase, or the method and path like oapi-codegen does without an operationId
+func (op *openAPIOperation) name() string {
+	if op.id != "" {
+		return pascalCase(op.id)
+	}
+	return pascalCase(op.method + "-" + op.path)
+}
+
+// pascalCase joins the alphanumeric words of s, capitalizing their first letter (e.g., "get-user" -> "GetUser")
+func pascalCase(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
+
+// openAPIOutline is the structure of a spec needed to map changed lines to operations
+type openAPIOutline struct {
+	operations []*openAPIOperation
+	// components maps reusable objects to the objects they reference
+	components map[string][]string
+	// owners are the operation ("op"), path item ("path") or reusable object ("ref") each line belongs to
+	owners []openAPIOwner
+}
+
+// openAPIOwner is the part of a spec a line belongs to
+type openAPIOwner struct {
+	kind string // "op", "path", "ref" or empty
+	key  string // "path method", path or reference
+}
+
+// parseOpenAPIOutline reads the operations and reusable objects of a spec from the indentation of its keys
+// Only block-style YAML and pretty-printed JSON are understood
+func parseOpenAPIOutline(content []byte) *openAPIOutline {
+	outline := &openAPIOutline{components: make(map[string][]string)}
+	operations := make(map[string]*openAPIOperation)
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level
+
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimRight(rawLine, " \t\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			outline.owners = append(outline.owners, openAPIOwner{})
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		key := ""
+		m := openAPIKeyRegex.FindStringSubmatch(trimmed)
+		if m != nil {
+			key = m[1] + m[2] + m[3]
+		}
+		// A key or closing bracket ends the keys of the same indentation; other lines
+		// (e.g., "- item") continue the enclosing key
+		closes := m != nil || strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]")
+		for len(stack) > 0 && (stack[len(stack)-1].indent > indent || (closes && stack[len(stack)-1].indent == indent)) {
+			stack = stack[:len(stack)-1]
+		}
+		if m != nil {
+			stack = append(stack, level{indent: indent, key: key})
+		}
+
+		keys := make([]string, len(stack))
+		for i, l := range stack {
+			keys[i] = l.key
+		}
+		owner := openAPIOwnerOf(keys)
+		outline.owners = append(outline.owners, owner)
+
+		refs := openAPIRefRegex.FindAllStringSubmatch(trimmed, -1)
+		switch owner.kind {
+		case "op":
+			op := operations[owner.key]
+			if op == nil {
+				op = &openAPIOperation{path: keys[1], method: strings.ToLower(keys[2])}
+				operations[owner.key] = op
+				outline.operations = append(outline.operations, op)
+			}
+			if len(keys) == 4 {
+				if id := openAPIOperationIDRegex.FindStringSubmatch(trimmed); id != nil {
+					op.id = id[1]
+				}
+			}
+			for _, ref := range refs {
+				op.refs = append(op.refs, "#/"+ref[1]+"/"+ref[2])
+			}
+		case "ref":
+			for _, ref := range refs {
+				outline.components[owner.key] = append(outline.components[owner.key], "#/"+ref[1]+"/"+ref[2])
+			}
+		}
+	}
+	return outline
+}
+
+// openAPIOwnerOf returns the part of a spec a line with the given enclosing keys belongs to
+func openAPIOwnerOf(keys []string) openAPIOwner {
+	switch {
+	case len(keys) >= 3 && keys[0] == "paths" && openAPIMethods[strings.ToLower(keys[2])]:
+		return openAPIOwner{kind: "op", key: keys[1] + " " + strings.ToLower(keys[2])}
+	case len(keys) >= 2 && keys[0] == "paths":
+		return openAPIOwner{kind: "path", key: keys[1]}
+	case len(keys) >= 3 && keys[0] == "components":
+		return openAPIOwner{kind: "ref", key: "#/components/" + keys[1] + "/" + keys[2]}
+	case len(keys) >= 2 && (keys[0] == "definitions" || keys[0] == "parameters" || keys[0] == "responses"):
+		return openAPIOwner{kind: "ref", key: "#/" + keys[0] + "/" + keys[1]}
+	}
+	return openAPIOwner{}
+}
+
+// openAPIOperationNames returns the Go method names of the operations of a spec changed by any of the lines
+// Operations are changed by their own lines, the lines of their path item (e.g., shared parameters)
+// and the reusable objects they reference, directly or through other objects
+// If lines is nil, all operations are returned
+func openAPIOperationNames(content []byte, lines []int) []string {
+	outline := parseOpenAPIOutline(content)
+
+	changedOps := make(map[*openAPIOperation]bool)
+	changedPaths := make(map[string]bool)
+	changedRefs := make(map[string]bool)
+	for i, owner := range outline.owners {
+		if !linesInRange(lines, i+1, i+1) {
+			continue
+		}
+		switch owner.kind {
+		case "op":
+			for _, op := range outline.operations {
+				if op.path+" "+op.method == owner.key {
+					changedOps[op] = true
+				}
+			}
+		case "path":
+			changedPaths[owner.key] = true
+		case "ref":
+			changedRefs[owner.key] = true
+		}
+	}
+
+	// Objects referencing a changed object are changed as well
+	for grown := true; grown; {
+		grown = false
+		for component, refs := range outline.components {
+			if changedRefs[component] {
+				continue
+			}
+			for _, ref := range refs {
+				if changedRefs[ref] {
+					changedRefs[component] = true
+					grown = true
+					break
+				}
+			}
+		}
+	}
+
+	var names []string
+	for _, op := range outline.operations {
+		changed := changedOps[op] || changedPaths[op.path]
+		for _, ref := range op.refs {
+			changed = changed || changedRefs[ref]
+		}
+		if changed {
+			names = append(names, op.name())
+		}
+	}
+	sort.Strings(names)
+	return uniqueStrings(names)
+}
+
+// specsForOpenAPIFile returns the OpenAPI specs of a file (relative to the project root)
+func (a *Analyzer) specsForOpenAPIFile(relPath string) []OpenAPISpec {
+	var specs []OpenAPISpec
+	for _, spec := range a.config.OpenAPISpecs {
+		if strings.TrimPrefix(path.Clean(filepath.ToSlash(spec.Spec)), "./") == relPath {
+			specs = append(specs, spec)
+		}
+	}
+	return specs
+}
+
+// openAPIInterfaceMethods maps changed operations to the methods of the interfaces of the generated package:
+// server interfaces (ServerInterface, StrictServerInterface, Handler) and clients (ClientInterface, Invoker),
+// whose oapi-codegen variants add WithBody and WithResponse suffixes
+// Implementations of the methods count as usage, so that handlers of the changed operations are affected
+func (a *Analyzer) openAPIInterfaceMethods(spec OpenAPISpec, operations []string) []InterfaceMethodRange {
+	if len(operations) == 0 {
+		return nil
+	}
+
+	candidates := make(map[string]bool)
+	for _, op := range operations {
+		for _, suffix := range []string{"", "WithBody", "WithResponse", "WithBodyWithResponse"} {
+			candidates[op+suffix] = true
+		}
+	}
+
+	var methods []InterfaceMethodRange
+	pkgDir := filepath.Join(a.config.ProjectRoot, filepath.FromSlash(spec.Package))
+	if entries, err := a.fs.ReadDir(pkgDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+			ranges, err := a.symbolAnalyzer.ExtractInterfaceMethodRanges(filepath.Join(pkgDir, entry.Name()))
+			if err != nil {
+				continue
+			}
+			for _, m := range ranges {
+				if candidates[m.MethodName] {
+					methods = append(methods, InterfaceMethodRange{InterfaceName: m.InterfaceName, MethodName: m.MethodName, MatchImplementations: true})
+				}
+			}
+		}
+	}
+
+	// The package may not be generated yet (e.g., generated in CI): assume the oapi-codegen server interface
+	if len(methods) == 0 {
+		for _, op := range operations {
+			methods = append(methods, InterfaceMethodRange{InterfaceName: "ServerInterface", MethodName: op, MatchImplementations: true})
+		}
+	}
+	a.config.Logger.Debug("openapi spec changes operations", "spec", spec.Spec, "operations", operations)
+	return methods
+}
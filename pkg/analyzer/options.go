@@ -0,0 +1,135 @@
+package analyzer
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrInvalidConfig indicates a configuration the Analyzer cannot run with
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Option is a function that configures the Analyzer, complementing Config
+type Option func(*Config)
+
+// WithCmdDir sets the directory containing CLI command definitions
+func WithCmdDir(dir string) Option {
+	return func(c *Config) {
+		c.CmdDir = dir
+	}
+}
+
+// WithInfrastructureFiles adds files that should be treated as infrastructure files
+func WithInfrastructureFiles(files ...string) Option {
+	return func(c *Config) {
+		c.InfrastructureFiles = append(c.InfrastructureFiles, files...)
+	}
+}
+
+// WithLogger sets the logger receiving progress and warning messages
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.Logger = logger
+	}
+}
+
+// WithCache sets the cache shared with other Analyzers (e.g., in a long-running process)
+func WithCache(cache Cache) Option {
+	return func(c *Config) {
+		c.Cache = cache
+	}
+}
+
+// NewAnalyzerWithOptions applies the options to the config, validates it and creates a new Analyzer
+func NewAnalyzerWithOptions(cfg Config, opts ...Option) (*Analyzer, error) {
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return NewAnalyzer(cfg), nil
+}
+
+// Validate reports settings the Analyzer cannot run with, such as an empty module path or a missing project root
+// Errors wrap ErrInvalidConfig
+func (c Config) Validate() error {
+	if c.ModulePath == "" {
+		return fmt.Errorf("%w: module path is empty", ErrInvalidConfig)
+	}
+	if c.ProjectRoot == "" {
+		return fmt.Errorf("%w: project root is empty", ErrInvalidConfig)
+	}
+
+	fsys := c.FileSystem
+	if fsys == nil {
+		fsys = NewFileSystem()
+	}
+	info, err := fsys.Stat(c.ProjectRoot)
+	if err != nil {
+		return fmt.Errorf("%w: project root: %v", ErrInvalidConfig, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%w: project root %s is not a directory", ErrInvalidConfig, c.ProjectRoot)
+	}
+
+	if filepath.IsAbs(c.CmdDir) {
+		return fmt.Errorf("%w: command directory %s must be relative to the project root", ErrInvalidConfig, c.CmdDir)
+	}
+	if c.Mode != "" && !c.Mode.IsValid() {
+		return fmt.Errorf("%w: unknown analysis mode %q", ErrInvalidConfig, c.Mode)
+	}
+	if c.FallbackPolicy != "" && !c.FallbackPolicy.IsValid() {
+		return fmt.Errorf("%w: unknown fallback policy %q", ErrInvalidConfig, c.FallbackPolicy)
+	}
+	if c.Reflection != "" && !c.Reflection.IsValid() {
+		return fmt.Errorf("%w: unknown reflection mode %q", ErrInvalidConfig, c.Reflection)
+	}
+	if c.ConfidenceDecayHops < 0 {
+		return fmt.Errorf("%w: negative confidence decay hops %d", ErrInvalidConfig, c.ConfidenceDecayHops)
+	}
+	if c.Concurrency < 0 {
+		return fmt.Errorf("%w: negative concurrency %d", ErrInvalidConfig, c.Concurrency)
+	}
+	targets := make(map[string]string)
+	for name, alias := range c.Aliases {
+		if name == "" || alias == "" {
+			return fmt.Errorf("%w: alias %q -> %q has an empty name", ErrInvalidConfig, name, alias)
+		}
+		if other, ok := targets[alias]; ok {
+			return fmt.Errorf("%w: resources %s and %s are both aliased to %s", ErrInvalidConfig, min(name, other), max(name, other), alias)
+		}
+		targets[alias] = name
+	}
+	for _, prefix := range c.CollapsePrefixes {
+		if strings.Trim(prefix, "./") == "" {
+			return fmt.Errorf("%w: collapse prefix %q matches every package", ErrInvalidConfig, prefix)
+		}
+	}
+	for _, table := range c.RegistrationTables {
+		if table.Type == "" {
+			return fmt.Errorf("%w: registration table needs a type", ErrInvalidConfig)
+		}
+		if table.PackageField == "" {
+			return fmt.Errorf("%w: registration table %s needs a package_field", ErrInvalidConfig, table.Type)
+		}
+		if !table.ResourceType.IsValid() {
+			return fmt.Errorf("%w: unknown resource type %q of registration table %s", ErrInvalidConfig, table.ResourceType, table.Type)
+		}
+		if filepath.IsAbs(table.Dir) {
+			return fmt.Errorf("%w: registration table directory %s must be relative to the project root", ErrInvalidConfig, table.Dir)
+		}
+	}
+	for _, rule := range c.SeverityRules {
+		if !rule.Severity.IsValid() {
+			return fmt.Errorf("%w: unknown severity %q for %s", ErrInvalidConfig, rule.Severity, rule.Pattern)
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("%w: invalid severity pattern %q: %v", ErrInvalidConfig, rule.Pattern, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package analyzer
+
+import (
+	"runtime"
+	"sync"
+)
+
+// forEachParallel calls fn for the indexes 0..n-1 with at most limit concurrent calls
+// A limit of 0 or less uses GOMAXPROCS
+func forEachParallel(limit, n int, fn func(i int)) {
+	if limit <= 0 {
+		limit = runtime.GOMAXPROCS(0)
+	}
+	if limit == 1 || n <= 1 {
+		for i := 0; i < n; i++ {
+			fn(i)
+		}
+		return
+	}
+
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,193 @@
+package analyzer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FilePatch is the diff of one file of a patch
+type FilePatch struct {
+	// Path is the path of the file after the patch (before it for deleted files), relative to the git root
+	Path string
+	// New reports a file created by the patch
+	New bool
+	// diff is the part of the patch changing the file, headers included
+	diff string
+}
+
+// ParsePatch splits a git patch (git diff, git format-patch or the .diff of a pull request) by file
+// Text before the first "diff --git" header (e.g., the mail headers of format-patch) is skipped
+func ParsePatch(patch string) ([]FilePatch, error) {
+	var files []FilePatch
+	var current []string
+	flush := func() error {
+		if current == nil {
+			return nil
+		}
+		fp, err := parseFilePatch(current)
+		if err != nil {
+			return err
+		}
+		files = append(files, fp)
+		return nil
+	}
+	for _, line := range strings.Split(patch, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+			current = []string{}
+		}
+		if current != nil {
+			current = append(current, line)
+		}
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no file diffs found (want the output of git diff)")
+	}
+	return files, nil
+}
+
+// parseFilePatch reads the path of a file diff from its headers
+// The +++ and --- headers name the file unambiguously; diffs without them (renames, mode changes, binary
+// files) are named by their rename header or the b/ side of the diff --git line
+func parseFilePatch(lines []string) (FilePatch, error) {
+	fp := FilePatch{diff: strings.Join(lines, "\n") + "\n"}
+	var oldPath, newPath, renamed string
+headers:
+	for _, line := range lines[1:] {
+		switch {
+		case strings.HasPrefix(line, "@@"):
+			// Hunks follow the headers
+			break headers
+		case strings.HasPrefix(line, "new file mode"):
+			fp.New = true
+		case strings.HasPrefix(line, "rename to "):
+			renamed = patchPath(strings.TrimPrefix(line, "rename to "), "")
+		case strings.HasPrefix(line, "--- "):
+			oldPath = patchPath(strings.TrimPrefix(line, "--- "), "a/")
+		case strings.HasPrefix(line, "+++ "):
+			newPath = patchPath(strings.TrimPrefix(line, "+++ "), "b/")
+		}
+	}
+	switch {
+	case newPath != "" && newPath != "/dev/null":
+		fp.Path = newPath
+	case oldPath != "" && oldPath != "/dev/null":
+		fp.Path = oldPath
+	case renamed != "":
+		fp.Path = renamed
+	default:
+		header := strings.TrimPrefix(lines[0], "diff --git ")
+		if i := strings.LastIndex(header, " b/"); i >= 0 {
+			fp.Path = header[i+len(" b/"):]
+		}
+	}
+	if oldPath == "/dev/null" {
+		fp.New = true
+	}
+	if fp.Path == "" {
+		return FilePatch{}, fmt.Errorf("malformed patch: no file name in %q", truncateDiffLine(lines[0]))
+	}
+	return fp, nil
+}
+
+// patchPath returns the path of a ---/+++ header without its a/ or b/ prefix
+// git quotes paths with special characters as Go-like strings
+func patchPath(name, prefix string) string {
+	// Some tools append a tab and a timestamp
+	name, _, _ = strings.Cut(name, "\t")
+	if unquoted, err := strconv.Unquote(name); err == nil {
+		name = unquoted
+	}
+	if name == "/dev/null" {
+		return name
+	}
+	return strings.TrimPrefix(name, prefix)
+}
+
+// patchGitClient is a GitClient reporting the changes of a patch instead of the git diff
+// The root directory and the content of files at the base branch are read by the client of the base branch
+type patchGitClient struct {
+	*execGitClient
+	files map[string]FilePatch
+	paths []string
+}
+
+// NewPatchGitClient creates a GitClient whose changes are the files of a patch, e.g., to analyze a pull
+// request of a merge-queue batch on its own while the whole batch is checked out
+// The line numbers of the patch are taken as they are, so that lines moved by other changes to the same
+// files (such as other pull requests of the batch) may be attributed to neighboring symbols
+// Files matching the excludes are left out as with NewGitClientWithExcludes
+func NewPatchGitClient(projectDir, baseBranch string, excludes []string, files []FilePatch) GitClient {
+	return newPatchGitClient(projectDir, baseBranch, excludes, files)
+}
+
+// newPatchGitClient creates the patch client of NewPatchGitClient
+func newPatchGitClient(projectDir, baseBranch string, excludes []string, files []FilePatch) *patchGitClient {
+	g := &patchGitClient{
+		execGitClient: &execGitClient{projectDir: projectDir, baseBranch: baseBranch, excludes: excludes},
+		files:         make(map[string]FilePatch),
+	}
+	g.addFiles(files)
+	return g
+}
+
+// addFiles adds the files of a patch which do not match the excludes (a later diff of a file replaces an earlier one)
+func (g *patchGitClient) addFiles(files []FilePatch) {
+	for _, fp := range files {
+		if g.excluded(fp.Path) {
+			continue
+		}
+		if _, ok := g.files[fp.Path]; !ok {
+			g.paths = append(g.paths, fp.Path)
+		}
+		g.files[fp.Path] = fp
+	}
+}
+
+// GetChangedFiles returns the files of the patch
+func (g *patchGitClient) GetChangedFiles(string) ([]string, error) {
+	return append([]string(nil), g.paths...), nil
+}
+
+// GetChangedLines returns the lines of a file added by the patch
+func (g *patchGitClient) GetChangedLines(filePath string) ([]int, error) {
+	fp, ok := g.file(filePath)
+	if !ok {
+		return nil, nil
+	}
+	return parseUnifiedDiff(fp.diff)
+}
+
+// GetChangedLinesWithDeleted returns the lines of a file added and removed by the patch
+func (g *patchGitClient) GetChangedLinesWithDeleted(filePath string) (*DiffResult, error) {
+	fp, ok := g.file(filePath)
+	if !ok {
+		return &DiffResult{}, nil
+	}
+	return parseUnifiedDiffWithDeleted(fp.diff)
+}
+
+// IsNewFile reports whether the patch creates a file
+func (g *patchGitClient) IsNewFile(filePath string) (bool, error) {
+	fp, ok := g.file(filePath)
+	return ok && fp.New, nil
+}
+
+// file returns the diff of a file given like the files of the git diff
+func (g *patchGitClient) file(filePath string) (FilePatch, bool) {
+	if fp, ok := g.files[filePath]; ok {
+		return fp, true
+	}
+	_, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return FilePatch{}, false
+	}
+	fp, ok := g.files[gitRelPath]
+	return fp, ok
+}
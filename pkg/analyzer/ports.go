@@ -14,6 +14,8 @@ type GitClient interface {
 	GetRootDir() (string, error)
 	// GetFileContentAtBase returns the content of a file at the base branch
 	GetFileContentAtBase(filePath string) ([]byte, error)
+	// IsNewFile reports whether a file does not exist on the base branch
+	IsNewFile(filePath string) (bool, error)
 }
 
 // GoListClient abstracts go list command for testability
@@ -26,6 +28,16 @@ type GoListClient interface {
 type PackageInfo struct {
 	ImportPath string
 	Imports    []string
+	// TestImports are the imports of the package's _test.go files (including external tests)
+	TestImports []string
+	// ToolImports are the imports of the package's tools files (//go:build tools), which go list excludes
+	ToolImports []string
+	// Dir is the directory containing the package sources
+	Dir string
+	// EmbedFiles are files matched by //go:embed directives, relative to Dir
+	EmbedFiles []string
+	// Error is set when the package could not be loaded completely (e.g., syntax errors)
+	Error string
 }
 
 // FileSystem abstracts file system operations for testability
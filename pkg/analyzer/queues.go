@@ -0,0 +1,106 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/token"
+	"path/filepath"
+	"strconv"
+)
+
+// queueRef is a queue or topic name passed to RunWorkerPool: a string literal or a string constant
+type queueRef struct {
+	// value is the name given as a literal
+	value string
+	// pkgPath and constant identify a constant (pkgPath is empty for constants of the command directory)
+	pkgPath  string
+	constant string
+}
+
+// extractQueuesFromRunE returns the string literals and constants passed to RunWorkerPool calls of RunE,
+// including the elements of slice literals (e.g., []string{"emails", events.TopicUserCreated})
+func (e *ResourceExtractor) extractQueuesFromRunE(lit *ast.CompositeLit, importMap map[string]string) []queueRef {
+	var refs []queueRef
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "RunE" {
+			continue
+		}
+
+		ast.Inspect(kv.Value, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok || sel.Sel.Name != "RunWorkerPool" {
+				return true
+			}
+			for _, arg := range call.Args {
+				args := []ast.Expr{arg}
+				if slice, ok := arg.(*ast.CompositeLit); ok {
+					args = slice.Elts
+				}
+				for _, expr := range args {
+					if ref, ok := queueRefOf(expr, importMap); ok {
+						refs = append(refs, ref)
+					}
+				}
+			}
+			return false
+		})
+	}
+	return refs
+}
+
+// queueRefOf returns the queue reference of an argument, if it is a string literal or may be a constant
+func queueRefOf(expr ast.Expr, importMap map[string]string) (queueRef, bool) {
+	switch x := expr.(type) {
+	case *ast.BasicLit:
+		if x.Kind != token.STRING {
+			return queueRef{}, false
+		}
+		value, err := strconv.Unquote(x.Value)
+		return queueRef{value: value}, err == nil && value != ""
+	case *ast.Ident:
+		return queueRef{constant: x.Name}, true
+	case *ast.SelectorExpr:
+		pkg, ok := x.X.(*ast.Ident)
+		if !ok || importMap[pkg.Name] == "" {
+			return queueRef{}, false
+		}
+		return queueRef{pkgPath: importMap[pkg.Name], constant: x.Sel.Name}, true
+	}
+	return queueRef{}, false
+}
+
+// resolveQueues sets the queues of the worker resources from the references found in their commands
+// References that are not string constants (e.g., variables or numbers) are skipped
+func (a *Analyzer) resolveQueues() {
+	if a.commands == nil {
+		return
+	}
+	cmdDir := filepath.Join(a.config.ProjectRoot, a.config.CmdDir)
+	for _, node := range a.commands.nodes {
+		if node.index < 0 || a.resources[node.index].Type != ResourceTypeWorker {
+			continue
+		}
+		var queues []string
+		for _, ref := range node.queues {
+			if ref.constant == "" {
+				queues = append(queues, ref.value)
+				continue
+			}
+			dir := cmdDir
+			if ref.pkgPath != "" {
+				dir = a.symbolAnalyzer.GetPackageDir(ref.pkgPath)
+			}
+			if value, ok := a.symbolAnalyzer.GetStringConstant(dir, ref.constant); ok && value != "" {
+				queues = append(queues, value)
+			}
+		}
+		a.resources[node.index].Queues = uniqueStrings(queues)
+	}
+}
@@ -0,0 +1,147 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FindReexportedValues returns the exported constants and variables of a package whose value refers to
+// the given symbols of the target package (e.g., const X = b.Y)
+func (s *SymbolAnalyzer) FindReexportedValues(pkgDir string, targetPkgPath string, names []string) ([]string, error) {
+	nameSet := make(map[string]bool)
+	for _, name := range names {
+		nameSet[name] = true
+	}
+
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var values []string
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, 0)
+		if err != nil {
+			continue
+		}
+
+		alias := ""
+		for _, imp := range file.Imports {
+			if strings.Trim(imp.Path.Value, `"`) != targetPkgPath {
+				continue
+			}
+			alias = filepath.Base(targetPkgPath)
+			if imp.Name != nil {
+				alias = imp.Name.Name
+			}
+			break
+		}
+		if alias == "" || alias == "_" {
+			continue
+		}
+
+		refersToTarget := func(exprs []ast.Expr) bool {
+			for _, expr := range exprs {
+				if alias == "." {
+					if len(findDotImportedUses(expr, nameSet)) > 0 {
+						return true
+					}
+					continue
+				}
+				found := false
+				ast.Inspect(expr, func(n ast.Node) bool {
+					if sel, ok := n.(*ast.SelectorExpr); ok {
+						if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == alias && nameSet[sel.Sel.Name] {
+							found = true
+						}
+					}
+					return !found
+				})
+				if found {
+					return true
+				}
+			}
+			return false
+		}
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || (gen.Tok != token.CONST && gen.Tok != token.VAR) {
+				continue
+			}
+			// Constants without a value repeat the previous expression of their group
+			var previous []ast.Expr
+			for _, spec := range gen.Specs {
+				vs := spec.(*ast.ValueSpec)
+				exprs := vs.Values
+				if len(exprs) == 0 && gen.Tok == token.CONST {
+					exprs = previous
+				}
+				previous = exprs
+				if !refersToTarget(exprs) {
+					continue
+				}
+				for _, name := range vs.Names {
+					if isExported(name.Name) {
+						values = append(values, name.Name)
+					}
+				}
+			}
+		}
+	}
+
+	return uniqueStrings(values), nil
+}
+
+// findReexportedValues follows constants and variables re-exporting the changed symbols through importers,
+// transitively (c.Z = a.X = b.Y)
+// The result maps each package path to its re-exported values
+func (a *Analyzer) findReexportedValues(pkgPath string, symbols []string) map[string][]string {
+	type item struct {
+		pkgPath string
+		names   []string
+	}
+
+	result := make(map[string][]string)
+	seen := make(map[string]bool)
+	queue := []item{{pkgPath: pkgPath, names: symbols}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, importer := range a.graph.GetImporters(current.pkgPath) {
+			values, err := a.symbolAnalyzer.FindReexportedValues(a.getPkgDir(importer), current.pkgPath, current.names)
+			if err != nil {
+				continue
+			}
+
+			var newValues []string
+			for _, value := range values {
+				key := importer + "." + value
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				newValues = append(newValues, value)
+			}
+			if len(newValues) == 0 {
+				continue
+			}
+			result[importer] = append(result[importer], newValues...)
+			queue = append(queue, item{pkgPath: importer, names: newValues})
+		}
+	}
+
+	for importer := range result {
+		sort.Strings(result[importer])
+	}
+	return result
+}
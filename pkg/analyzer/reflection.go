@@ -0,0 +1,344 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ReflectionMode selects how symbols reachable only through reflection are handled
+type ReflectionMode string
+
+const (
+	// ReflectionOff ignores reflection (default)
+	ReflectionOff ReflectionMode = "off"
+	// ReflectionConservative widens the impact to resources whose packages access changed symbols via reflection
+	ReflectionConservative ReflectionMode = "conservative"
+)
+
+// IsValid checks if the mode is one of the known modes
+func (m ReflectionMode) IsValid() bool {
+	return m == ReflectionOff || m == ReflectionConservative
+}
+
+// reflectionValueFuncs are functions that inspect arbitrary values through reflection
+var reflectionValueFuncs = map[string]map[string]bool{
+	"reflect":       {"ValueOf": true, "TypeOf": true},
+	"encoding/json": {"Marshal": true, "MarshalIndent": true, "Unmarshal": true, "NewEncoder": true, "NewDecoder": true},
+}
+
+// reflectionStreamMethods are the methods of the encoders and decoders of encoding/json inspecting values
+var reflectionStreamMethods = map[string]bool{
+	"Encode": true,
+	"Decode": true,
+}
+
+// reflectionNameFuncs are methods looking up fields or methods by name
+var reflectionNameFuncs = map[string]bool{
+	"MethodByName": true,
+	"FieldByName":  true,
+}
+
+// ReflectionAccess describes how a package uses reflection
+type ReflectionAccess struct {
+	// Values maps imported package paths to their symbols (types, variables) whose values are inspected with
+	// reflect.ValueOf/TypeOf or encoding/json (struct tags), directly or as fields of the package's own types
+	Values map[string][]string
+	// Names are string literals passed to MethodByName or FieldByName
+	Names []string
+}
+
+// FindReflectionAccess detects reflection-based access in a package directory
+// The values passed to reflection are matched syntactically: composite literals, conversions and new() of
+// imported types, and variables, parameters and results declared with them. Results are cached per directory
+func (s *SymbolAnalyzer) FindReflectionAccess(pkgDir string) (*ReflectionAccess, error) {
+	s.mu.RLock()
+	access, ok := s.reflection[pkgDir]
+	s.mu.RUnlock()
+	if ok {
+		return access, nil
+	}
+
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, 0)
+		if err != nil {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	// Types and package-level variables of the package -> their type expressions
+	localTypes := make(map[string]typeRef)
+	globals := make(map[string]typeRef)
+	for _, file := range files {
+		imports := importAliases(file)
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					localTypes[spec.Name.Name] = typeRef{spec.Type, imports}
+				case *ast.ValueSpec:
+					for i, name := range spec.Names {
+						globals[name.Name] = valueTypeRef(spec, i, imports)
+					}
+				}
+			}
+		}
+	}
+
+	access = &ReflectionAccess{Values: make(map[string][]string)}
+	for _, file := range files {
+		imports := importAliases(file)
+		// Import alias -> reflection package path
+		aliases := make(map[string]string)
+		for alias, impPath := range imports {
+			if _, ok := reflectionValueFuncs[impPath]; ok {
+				aliases[alias] = impPath
+			}
+		}
+
+		for _, decl := range file.Decls {
+			vars := declaredVars(decl, imports)
+			ast.Inspect(decl, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+
+				inspects := reflectionStreamMethods[sel.Sel.Name] && len(aliases) > 0
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					if impPath, ok := aliases[ident.Name]; ok && reflectionValueFuncs[impPath][sel.Sel.Name] {
+						inspects = true
+					}
+				}
+				if inspects {
+					refs := &reflectedTypes{localTypes: localTypes, seen: make(map[string]bool), values: access.Values}
+					for _, arg := range call.Args {
+						refs.addValue(arg, imports, vars, globals)
+					}
+				}
+
+				if reflectionNameFuncs[sel.Sel.Name] && len(call.Args) == 1 {
+					if lit, ok := call.Args[0].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+						if name, err := strconv.Unquote(lit.Value); err == nil {
+							access.Names = append(access.Names, name)
+						}
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	for pkgPath, symbols := range access.Values {
+		access.Values[pkgPath] = uniqueStrings(symbols)
+	}
+	access.Names = uniqueStrings(access.Names)
+	s.mu.Lock()
+	s.reflection[pkgDir] = access
+	s.mu.Unlock()
+	return access, nil
+}
+
+// typeRef is a type expression with the imports of its file
+type typeRef struct {
+	expr    ast.Expr
+	imports map[string]string
+}
+
+// importAliases returns the import alias -> package path mapping of a file
+func importAliases(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+	for _, imp := range file.Imports {
+		impPath := strings.Trim(imp.Path.Value, `"`)
+		alias := filepath.Base(impPath)
+		if imp.Name != nil {
+			alias = imp.Name.Name
+		}
+		imports[alias] = impPath
+	}
+	return imports
+}
+
+// valueTypeRef returns the type of the i-th name of a var declaration: its declared type, or its value
+func valueTypeRef(spec *ast.ValueSpec, i int, imports map[string]string) typeRef {
+	if spec.Type != nil {
+		return typeRef{spec.Type, imports}
+	}
+	if i < len(spec.Values) {
+		return typeRef{spec.Values[i], imports}
+	}
+	return typeRef{}
+}
+
+// declaredVars returns the variables of a function declaration (receiver, parameters, results, var
+// declarations and short variable declarations) with the expressions telling their type
+func declaredVars(decl ast.Decl, imports map[string]string) map[string]typeRef {
+	vars := make(map[string]typeRef)
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok {
+		return vars
+	}
+	for _, fields := range []*ast.FieldList{fn.Recv, fn.Type.Params, fn.Type.Results} {
+		if fields == nil {
+			continue
+		}
+		for _, field := range fields.List {
+			for _, name := range field.Names {
+				vars[name.Name] = typeRef{field.Type, imports}
+			}
+		}
+	}
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			for i, name := range n.Names {
+				vars[name.Name] = valueTypeRef(n, i, imports)
+			}
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE && len(n.Lhs) == len(n.Rhs) {
+				for i, lhs := range n.Lhs {
+					if ident, ok := lhs.(*ast.Ident); ok {
+						vars[ident.Name] = typeRef{n.Rhs[i], imports}
+					}
+				}
+			}
+		}
+		return true
+	})
+	return vars
+}
+
+// reflectedTypes collects the imported symbols of the values passed to reflection
+type reflectedTypes struct {
+	localTypes map[string]typeRef
+	seen       map[string]bool
+	values     map[string][]string
+}
+
+// addValue adds the imported symbols of a value: those of its type if it is a variable, or of the
+// composite literals, conversions and new() calls of the expression
+func (r *reflectedTypes) addValue(expr ast.Expr, imports map[string]string, vars, globals map[string]typeRef) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SelectorExpr:
+			r.addType(n, imports)
+			return false
+		case *ast.Ident:
+			ref, ok := vars[n.Name]
+			if !ok {
+				ref, ok = globals[n.Name]
+			}
+			if ok && ref.expr != nil && !r.seen["var "+n.Name] {
+				r.seen["var "+n.Name] = true
+				r.addType(ref.expr, ref.imports)
+			} else if !ok {
+				r.addType(n, imports)
+			}
+		}
+		return true
+	})
+}
+
+// addType adds the imported symbols referenced by a type expression, following the package's own types
+func (r *reflectedTypes) addType(expr ast.Expr, imports map[string]string) {
+	ast.Inspect(expr, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.SelectorExpr:
+			if ident, ok := n.X.(*ast.Ident); ok {
+				if impPath, ok := imports[ident.Name]; ok {
+					r.values[impPath] = append(r.values[impPath], n.Sel.Name)
+				}
+			}
+			return false
+		case *ast.Ident:
+			if ref, ok := r.localTypes[n.Name]; ok && !r.seen[n.Name] {
+				r.seen[n.Name] = true
+				r.addType(ref.expr, ref.imports)
+			}
+		}
+		return true
+	})
+}
+
+// isResourceAffectedByReflection checks if a package in the resource's dependencies may reach the changed
+// symbols through reflection: by looking them up by name, or by inspecting values of the changed package
+func (a *Analyzer) isResourceAffectedByReflection(resource *Resource, changedPkgPath string, info changedSymbolsInfo) bool {
+	if a.config.Reflection != ReflectionConservative {
+		return false
+	}
+
+	names := make(map[string]bool)
+	for _, sym := range info.symbols {
+		names[sym] = true
+	}
+	for _, m := range info.interfaceMethods {
+		names[m.MethodName] = true
+	}
+	if len(names) == 0 {
+		return false
+	}
+
+	candidates := append([]string{resource.Package}, a.graph.GetAllDeps(resource.Package)...)
+	for _, pkg := range uniqueStrings(candidates) {
+		if pkg == changedPkgPath {
+			continue
+		}
+		importsChanged := contains(a.graph.GetDirectDeps(pkg), changedPkgPath)
+		if !importsChanged && !contains(a.graph.GetAllDeps(pkg), changedPkgPath) {
+			continue
+		}
+
+		access, err := a.symbolAnalyzer.FindReflectionAccess(a.getPkgDir(pkg))
+		if err != nil {
+			continue
+		}
+		if importsChanged {
+			for _, sym := range access.Values[changedPkgPath] {
+				if names[sym] || reflectedMember(sym, info) {
+					return true
+				}
+			}
+		}
+		for _, name := range access.Names {
+			if names[name] {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// reflectedMember checks if a changed symbol is a member (field or method) of a type whose values are inspected
+func reflectedMember(typeName string, info changedSymbolsInfo) bool {
+	for _, sym := range info.symbols {
+		if strings.HasPrefix(sym, typeName+".") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,194 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// RegistrationTable describes resources registered as the elements of slice or map literals instead of
+// cobra commands, e.g., var jobs = []JobDef{{Name: "update-price", Run: updateprice.Run}}
+type RegistrationTable struct {
+	// Dir is the directory of the files declaring the tables, relative to the project root (default: CmdDir)
+	Dir string `json:"dir"`
+	// Type is the element type of the tables, e.g., "JobDef" or "jobs.Def"; pointer elements match too
+	Type string `json:"type"`
+	// ResourceType is the type of the registered resources
+	ResourceType ResourceType `json:"resource_type"`
+	// NameField is the string field naming the resource; map tables default to their string keys
+	NameField string `json:"name_field"`
+	// PackageField is the field whose value references the package run by the resource
+	// (e.g., updateprice.Run, updateprice.New() or a function literal calling it)
+	PackageField string `json:"package_field"`
+	// DescriptionField is the string field describing the resource (optional)
+	DescriptionField string `json:"description_field"`
+}
+
+// extractRegistrations returns a resource for each element of the tables of Config.RegistrationTables
+// Names already taken by other resources are skipped. Elements whose package field references no imported
+// package (e.g., a function of the table's own package) run the package declaring the table, with a warning
+func (a *Analyzer) extractRegistrations(taken map[string]bool) ([]Resource, []*AnalysisError) {
+	var resources []Resource
+	var warnings []*AnalysisError
+	for _, table := range a.config.RegistrationTables {
+		dir := table.Dir
+		if dir == "" {
+			dir = a.config.CmdDir
+		}
+		for _, r := range a.extractor.extractRegistrationTable(filepath.Join(a.config.ProjectRoot, dir), table) {
+			if taken[r.Name] {
+				continue
+			}
+			if r.Package == "" {
+				r.Package = a.symbolAnalyzer.FileToPackagePath(r.SourceFile)
+				warnings = append(warnings, &AnalysisError{Kind: ErrParse, File: a.projectRelPath(r.SourceFile),
+					Err: fmt.Errorf("field %s of registration %s references no imported package, using the package of the table %s", table.PackageField, r.Name, r.Package)})
+			}
+			taken[r.Name] = true
+			resources = append(resources, r)
+		}
+	}
+	return resources, warnings
+}
+
+// extractRegistrationTable extracts the resources registered in the tables of a directory
+func (e *ResourceExtractor) extractRegistrationTable(dir string, table RegistrationTable) []Resource {
+	entries, err := e.fs.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".go") && !strings.HasSuffix(name, "_test.go") {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	sort.Strings(files)
+
+	var resources []Resource
+	for _, filePath := range files {
+		content, err := e.fs.ReadFile(filePath)
+		if err != nil {
+			continue
+		}
+		file, err := parser.ParseFile(e.fset, filePath, content, parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		importMap := e.buildImportMap(file)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			lit, ok := n.(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			var isMap bool
+			switch t := lit.Type.(type) {
+			case *ast.ArrayType:
+				if !matchesTypeName(t.Elt, table.Type) {
+					return true
+				}
+			case *ast.MapType:
+				if !matchesTypeName(t.Value, table.Type) {
+					return true
+				}
+				isMap = true
+			default:
+				return true
+			}
+
+			for _, elt := range lit.Elts {
+				var key string
+				if kv, ok := elt.(*ast.KeyValueExpr); ok {
+					if isMap {
+						key, _ = stringLiteral(kv.Key)
+					}
+					elt = kv.Value
+				}
+				if r := e.registeredResource(elt, key, table, importMap, filePath); r != nil {
+					resources = append(resources, *r)
+				}
+			}
+			return false
+		})
+	}
+	return resources
+}
+
+// registeredResource extracts the resource of a table element: T{...}, &T{...} or {...} with the type elided
+func (e *ResourceExtractor) registeredResource(elt ast.Expr, key string, table RegistrationTable, importMap map[string]string, sourceFile string) *Resource {
+	if unary, ok := elt.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		elt = unary.X
+	}
+	lit, ok := elt.(*ast.CompositeLit)
+	if !ok || (lit.Type != nil && !matchesTypeName(lit.Type, table.Type)) {
+		return nil
+	}
+
+	r := &Resource{Type: table.ResourceType, SourceFile: sourceFile}
+	if table.NameField == "" {
+		r.Name = key
+	}
+	for _, field := range lit.Elts {
+		kv, ok := field.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		ident, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch ident.Name {
+		case table.NameField:
+			r.Name, _ = stringLiteral(kv.Value)
+		case table.DescriptionField:
+			r.Description, _ = stringLiteral(kv.Value)
+		case table.PackageField:
+			r.Package = referencedPackage(kv.Value, importMap)
+		}
+	}
+	if r.Name == "" {
+		return nil
+	}
+	return r
+}
+
+// matchesTypeName checks if a type expression is the named type, or a pointer to it
+// A name without a package qualifier matches the type of any package
+func matchesTypeName(expr ast.Expr, name string) bool {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	switch t := expr.(type) {
+	case *ast.Ident:
+		return t.Name == name
+	case *ast.SelectorExpr:
+		x, ok := t.X.(*ast.Ident)
+		return ok && (t.Sel.Name == name || x.Name+"."+t.Sel.Name == name)
+	}
+	return false
+}
+
+// referencedPackage returns the first imported package referenced by an expression (e.g., pkg.Run)
+func referencedPackage(expr ast.Expr, importMap map[string]string) string {
+	var pkg string
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if pkg != "" {
+			return false
+		}
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		if ident, ok := sel.X.(*ast.Ident); ok {
+			pkg = importMap[ident.Name]
+		}
+		return pkg == ""
+	})
+	return pkg
+}
@@ -0,0 +1,214 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ResourceType represents the type of resource
+type ResourceType string
+
+const (
+	ResourceTypeAPI    ResourceType = "api"
+	ResourceTypeJob    ResourceType = "job"
+	ResourceTypeWorker ResourceType = "worker"
+	// ResourceTypeFunction is a serverless function (e.g., an AWS Lambda handler started with lambda.Start)
+	ResourceTypeFunction ResourceType = "function"
+	// ResourceTypeExternal is the default type of resources reported by external analyzers
+	ResourceTypeExternal ResourceType = "external"
+)
+
+// IsValid checks if the resource type is one of the known types
+func (t ResourceType) IsValid() bool {
+	return t == ResourceTypeAPI || t == ResourceTypeJob || t == ResourceTypeWorker || t == ResourceTypeFunction || t == ResourceTypeExternal
+}
+
+// Severity represents how critical a resource is
+type Severity string
+
+const (
+	SeverityCritical Severity = "critical"
+	SeverityNormal   Severity = "normal"
+	SeverityLow      Severity = "low"
+)
+
+// Rank returns the ordering of the severity (higher is more critical)
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 2
+	case SeverityLow:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// IsValid checks if the severity is one of the known tiers
+func (s Severity) IsValid() bool {
+	return s == SeverityCritical || s == SeverityNormal || s == SeverityLow
+}
+
+// SeverityRule assigns a severity to resources whose name matches Pattern
+type SeverityRule struct {
+	// Pattern is a resource name or a glob pattern (e.g., "payment-*")
+	Pattern string `json:"pattern"`
+	// Severity is the severity assigned to matching resources
+	Severity Severity `json:"severity"`
+}
+
+// matchSeverity returns the severity of the first rule matching the resource name
+func matchSeverity(rules []SeverityRule, name string) Severity {
+	for _, rule := range rules {
+		if rule.Pattern == name {
+			return rule.Severity
+		}
+		if matched, err := path.Match(rule.Pattern, name); err == nil && matched {
+			return rule.Severity
+		}
+	}
+	return SeverityNormal
+}
+
+// Resource represents a CLI command (service/job/worker) or a serverless function
+type Resource struct {
+	Name         string            `json:"name"`                   // Command name (e.g., "api-gateway", "update-price")
+	Type         ResourceType      `json:"type"`                   // "api", "job", "worker", "function"
+	Package      string            `json:"package"`                // Direct dependency package (e.g., "github.com/.../job/update-price")
+	SourceFile   string            `json:"source_file"`            // Source file where defined
+	Description  string            `json:"description"`            // Command description (Short)
+	Severity     Severity          `json:"severity"`               // "critical", "normal", "low"
+	Image        string            `json:"image,omitempty"`        // Container image built for the resource
+	Applications []string          `json:"applications,omitempty"` // CD applications deploying the resource (e.g., Argo CD Applications)
+	Labels       map[string]string `json:"labels,omitempty"`       // Arbitrary metadata (e.g., helm release, pager rotation)
+	Schedule     string            `json:"schedule,omitempty"`     // Cron schedule of a scheduled job (e.g., "0 3 * * *")
+	Queues       []string          `json:"queues,omitempty"`       // Queues or topics consumed by a worker (passed to RunWorkerPool)
+}
+
+// AffectedResource represents information about an affected resource
+type AffectedResource struct {
+	Resource
+	Reason           string      `json:"reason"`                       // Reason for being affected
+	AffectedPackage  string      `json:"affected_package"`             // Package causing the impact
+	DependencyChain  []string    `json:"dependency_chain"`             // Dependency chain
+	ChainLinks       []ChainLink `json:"chain_links,omitempty"`        // Symbols linking each hop of the chain
+	Breaking         bool        `json:"breaking,omitempty"`           // Uses a public API changed incompatibly
+	UnknownImpact    bool        `json:"unknown_impact,omitempty"`     // Changes could not be determined (strict mode)
+	WireFormatChange bool        `json:"wire_format_change,omitempty"` // Only affected by struct tag changes
+	Confidence       Confidence  `json:"confidence,omitempty"`         // How certain the impact is
+	NextRun          string      `json:"next_run,omitempty"`           // Next run of a scheduled job (RFC 3339)
+	TestsToRun       []string    `json:"tests_to_run,omitempty"`       // Test packages verifying the resource (go test arguments)
+}
+
+// Confidence describes how certain the analysis is about an affected resource
+type Confidence string
+
+const (
+	// ConfidenceHigh means the resource uses a changed symbol or is mapped to a changed file
+	ConfidenceHigh Confidence = "high"
+	// ConfidenceMedium means the impact comes from a heuristic (reflection, whole-package impact, rule plugins)
+	ConfidenceMedium Confidence = "medium"
+	// ConfidenceLow means the changes could not be determined
+	ConfidenceLow Confidence = "low"
+)
+
+// IsValid checks if the confidence is one of the known levels
+func (c Confidence) IsValid() bool {
+	return c == ConfidenceHigh || c == ConfidenceMedium || c == ConfidenceLow
+}
+
+// lower returns the confidence lowered by a number of levels, down to ConfidenceLow
+func (c Confidence) lower(levels int) Confidence {
+	for ; levels > 0 && c != ConfidenceLow; levels-- {
+		if c == ConfidenceHigh {
+			c = ConfidenceMedium
+		} else {
+			c = ConfidenceLow
+		}
+	}
+	return c
+}
+
+// LabelRule attaches labels to resources whose name matches Pattern
+type LabelRule struct {
+	// Pattern is a resource name or a glob pattern (e.g., "api-*")
+	Pattern string `json:"pattern"`
+	// Labels are the key-value pairs attached to matching resources
+	Labels map[string]string `json:"labels"`
+}
+
+// matchLabels merges the labels of all rules matching the resource name (later rules override earlier ones)
+func matchLabels(rules []LabelRule, name string) map[string]string {
+	var labels map[string]string
+	for _, rule := range rules {
+		matched := rule.Pattern == name
+		if !matched {
+			matched, _ = path.Match(rule.Pattern, name)
+		}
+		if !matched {
+			continue
+		}
+		if labels == nil {
+			labels = make(map[string]string)
+		}
+		for k, v := range rule.Labels {
+			labels[k] = v
+		}
+	}
+	return labels
+}
+
+// applyAliases renames resources from their command names to canonical names
+// Two resources ending up with the same name could no longer be told apart, so this is an error
+func applyAliases(resources []Resource, aliases map[string]string) error {
+	seen := make(map[string]string)
+	for i := range resources {
+		name := resources[i].Name
+		if alias, ok := aliases[name]; ok {
+			resources[i].Name = alias
+		}
+		if other, ok := seen[resources[i].Name]; ok {
+			return fmt.Errorf("resources %s and %s are both named %s after aliasing", other, name, resources[i].Name)
+		}
+		seen[resources[i].Name] = name
+	}
+	return nil
+}
+
+// resolveImage returns the container image of a resource
+// An explicit mapping takes precedence over the template ({name} and {type} are replaced)
+func resolveImage(images map[string]string, template string, r Resource) string {
+	if image, ok := images[r.Name]; ok {
+		return image
+	}
+	if template == "" {
+		return ""
+	}
+	return strings.NewReplacer("{name}", r.Name, "{type}", string(r.Type)).Replace(template)
+}
+
+// ApplicationsToSync returns the sorted, de-duplicated CD applications deploying the affected resources
+func ApplicationsToSync(resources []AffectedResource) []string {
+	var apps []string
+	for _, r := range resources {
+		apps = append(apps, r.Applications...)
+	}
+	apps = uniqueStrings(apps)
+	sort.Strings(apps)
+	return apps
+}
+
+// ImagesToRebuild returns the sorted, de-duplicated container images of the affected resources
+func ImagesToRebuild(resources []AffectedResource) []string {
+	var images []string
+	for _, r := range resources {
+		if r.Image != "" {
+			images = append(images, r.Image)
+		}
+	}
+	images = uniqueStrings(images)
+	sort.Strings(images)
+	return images
+}
@@ -0,0 +1,82 @@
+package analyzer
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+)
+
+// ReviewProvider reads the changes of a pull request (or merge request) from the API of a code review
+// system instead of local git, so that neither the head of the request nor the base branch needs to be
+// fetched to diff them: only the packages parsed by the analysis are read from the checkout
+type ReviewProvider interface {
+	// ChangedFiles returns the diffs of the files of the request, relative to the repository root
+	ChangedFiles() ([]FilePatch, error)
+	// FileContentAtBase returns the content of a file, relative to the repository root, at the base of the request
+	FileContentAtBase(path string) ([]byte, error)
+}
+
+// NewFilePatch creates the diff of a file from the hunks a review API returns for it, without file headers
+// Empty hunks (e.g., of binary files or of files too large for the API) make the analysis fall back to
+// the symbols of the whole file
+func NewFilePatch(path string, isNew bool, hunks string) FilePatch {
+	return FilePatch{Path: path, New: isNew, diff: hunks}
+}
+
+// reviewGitClient is a GitClient reading the changes and the base content of files from a ReviewProvider
+type reviewGitClient struct {
+	*patchGitClient
+	provider ReviewProvider
+
+	// base caches the content of files at the base, which the analysis reads several times per file
+	mu   sync.Mutex
+	base map[string]baseContent
+}
+
+// baseContent is the result of a FileContentAtBase request
+type baseContent struct {
+	data []byte
+	err  error
+}
+
+// NewReviewGitClient creates a GitClient whose changes are those of a review provider
+// Files matching the excludes are left out as with NewGitClientWithExcludes. Without a git work tree
+// (e.g., an extracted source archive), projectDir is taken for the root of the repository
+func NewReviewGitClient(projectDir, baseBranch string, excludes []string, provider ReviewProvider) (GitClient, error) {
+	files, err := provider.ChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	g := &reviewGitClient{patchGitClient: newPatchGitClient(projectDir, baseBranch, nil, nil), provider: provider, base: make(map[string]baseContent)}
+	g.rootOnce.Do(func() {
+		g.rootDir, g.rootErr = g.findRootDir()
+		if g.rootErr != nil {
+			g.rootDir, g.rootErr = filepath.Abs(projectDir)
+		}
+	})
+	// Excludes are resolved against the root, so the files are added once it is known
+	g.excludes = excludes
+	g.addFiles(files)
+	return g, nil
+}
+
+// GetFileContentAtBase returns the content of a file at the base of the request
+func (g *reviewGitClient) GetFileContentAtBase(filePath string) ([]byte, error) {
+	_, gitRelPath, err := g.resolveGitPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+	if fp, ok := g.files[gitRelPath]; ok && fp.New {
+		return nil, fmt.Errorf("%s is new in the request", gitRelPath)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	c, ok := g.base[gitRelPath]
+	if !ok {
+		c.data, c.err = g.provider.FileContentAtBase(gitRelPath)
+		g.base[gitRelPath] = c
+	}
+	return c.data, c.err
+}
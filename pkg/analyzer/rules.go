@@ -0,0 +1,212 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"plugin"
+	"strings"
+)
+
+// RulePlugin is a user-provided rule that can add or remove affected resources
+// The ABI is JSON: Apply receives a RuleInput and returns a RuleOutput
+type RulePlugin interface {
+	// Name identifies the plugin in reasons and warnings
+	Name() string
+	// Apply evaluates the rule on a JSON-encoded RuleInput and returns a JSON-encoded RuleOutput
+	Apply(input []byte) ([]byte, error)
+}
+
+// RuleInput is the data passed to rule plugins
+type RuleInput struct {
+	ChangedFiles []string `json:"changed_files"`
+	// ChangedSymbols maps package paths to their changed symbols (interface methods as "Interface.Method")
+	ChangedSymbols map[string][]string `json:"changed_symbols"`
+	Graph          *GraphExport        `json:"graph"`
+	Affected       []AffectedResource  `json:"affected"`
+}
+
+// RuleOutput is the decision of a rule plugin
+type RuleOutput struct {
+	// Add lists resources to report as affected
+	Add []RuleAddition `json:"add"`
+	// Remove lists names of resources not to report
+	Remove []string `json:"remove"`
+}
+
+// RuleAddition is a resource added by a rule plugin
+type RuleAddition struct {
+	Name   string `json:"name"`
+	Reason string `json:"reason"`
+	// Confidence of the impact (default: medium)
+	Confidence Confidence `json:"confidence,omitempty"`
+	// AffectedPackage is the package carrying the impact (default: the package of the resource)
+	AffectedPackage string `json:"affected_package,omitempty"`
+}
+
+// wasmRuntime is the WASI runtime running WASM rule plugins
+const wasmRuntime = "wasmtime"
+
+// LoadRulePlugin loads a rule plugin
+// Files ending with .so are loaded as Go plugins exporting "func Apply([]byte) ([]byte, error)", and files
+// ending with .wasm are WASI modules run with wasmtime; any other file is run as an executable
+// WASM modules and executables read the input on stdin and write the output on stdout
+func LoadRulePlugin(path string) (RulePlugin, error) {
+	if strings.HasSuffix(path, ".wasm") {
+		runtime, err := exec.LookPath(wasmRuntime)
+		if err != nil {
+			return nil, fmt.Errorf("WASM rule %s needs %s on the PATH: %w", path, wasmRuntime, err)
+		}
+		return &execRulePlugin{path: runtime, args: []string{"run", path}, name: filepath.Base(path)}, nil
+	}
+	if !strings.HasSuffix(path, ".so") {
+		return &execRulePlugin{path: path, name: filepath.Base(path)}, nil
+	}
+
+	p, err := plugin.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open plugin %s: %w", path, err)
+	}
+	sym, err := p.Lookup("Apply")
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up Apply in %s: %w", path, err)
+	}
+	apply, ok := sym.(func([]byte) ([]byte, error))
+	if !ok {
+		return nil, fmt.Errorf("plugin %s: Apply must be func([]byte) ([]byte, error), got %T", path, sym)
+	}
+	return &goRulePlugin{name: filepath.Base(path), apply: apply}, nil
+}
+
+// goRulePlugin is a rule loaded from a Go plugin
+type goRulePlugin struct {
+	name  string
+	apply func([]byte) ([]byte, error)
+}
+
+// Name returns the file name of the plugin
+func (p *goRulePlugin) Name() string {
+	return p.name
+}
+
+// Apply calls the Apply function of the plugin
+func (p *goRulePlugin) Apply(input []byte) ([]byte, error) {
+	return p.apply(input)
+}
+
+// execRulePlugin is a rule run as an external executable, or a WASM module run by the WASI runtime
+type execRulePlugin struct {
+	path string
+	args []string
+	name string
+}
+
+// Name returns the file name of the executable or WASM module
+func (p *execRulePlugin) Name() string {
+	return p.name
+}
+
+// Apply runs the executable with the input on stdin
+func (p *execRulePlugin) Apply(input []byte) ([]byte, error) {
+	return runJSONExecutable(p.path, input, p.args...)
+}
+
+// runJSONExecutable runs an executable with the input on stdin and returns its stdout
+// The stderr output is included in the error when the executable fails
+func runJSONExecutable(path string, input []byte, args ...string) ([]byte, error) {
+	cmd := exec.Command(path, args...)
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+// applyRulePlugins runs the configured rule plugins in order; failing plugins are reported as warnings
+func (a *Analyzer) applyRulePlugins(changedFiles []string, changedSymbols map[string][]string, affected []AffectedResource, warnings *warningCollector) []AffectedResource {
+	if len(a.config.RulePlugins) == 0 {
+		return affected
+	}
+
+	graph := a.exportGraph()
+	for _, p := range a.config.RulePlugins {
+		input, err := json.Marshal(RuleInput{
+			ChangedFiles:   changedFiles,
+			ChangedSymbols: changedSymbols,
+			Graph:          graph,
+			Affected:       affected,
+		})
+		if err != nil {
+			warnings.add(ErrRulePlugin, p.Name(), err)
+			continue
+		}
+
+		raw, err := p.Apply(input)
+		if err != nil {
+			warnings.add(ErrRulePlugin, p.Name(), err)
+			continue
+		}
+		var output RuleOutput
+		if err := json.Unmarshal(raw, &output); err != nil {
+			warnings.add(ErrRulePlugin, p.Name(), fmt.Errorf("invalid output: %w", err))
+			continue
+		}
+
+		kept := []AffectedResource{}
+		for _, r := range affected {
+			if !contains(output.Remove, r.Name) {
+				kept = append(kept, r)
+			}
+		}
+		affected = kept
+
+		for _, add := range output.Add {
+			resource := a.getResourceByName(add.Name)
+			if resource == nil {
+				warnings.add(ErrRulePlugin, p.Name(), fmt.Errorf("unknown resource %q", add.Name))
+				continue
+			}
+			exists := false
+			for _, r := range affected {
+				exists = exists || r.Name == add.Name
+			}
+			if exists {
+				continue
+			}
+			reason := add.Reason
+			if reason == "" {
+				reason = "added by rule " + p.Name()
+			}
+			confidence := add.Confidence
+			if confidence == "" {
+				confidence = ConfidenceMedium
+			} else if !confidence.IsValid() {
+				warnings.add(ErrRulePlugin, p.Name(), fmt.Errorf("unknown confidence %q of resource %s", confidence, add.Name))
+				confidence = ConfidenceMedium
+			}
+			affectedPackage := add.AffectedPackage
+			if affectedPackage == "" {
+				affectedPackage = resource.Package
+			}
+			affected = append(affected, AffectedResource{
+				Resource:        *resource,
+				Reason:          reason,
+				AffectedPackage: affectedPackage,
+				DependencyChain: []string{},
+				Confidence:      confidence,
+			})
+		}
+	}
+
+	SortAffectedResources(affected)
+	return affected
+}
@@ -0,0 +1,361 @@
+package analyzer
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// cronDescriptors are the predefined schedules of cron implementations (e.g., robfig/cron)
+var cronDescriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// cronField is the range and names of a field of a cron expression
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	cronSeconds = cronField{min: 0, max: 59}
+	cronMinutes = cronField{min: 0, max: 59}
+	cronHours   = cronField{min: 0, max: 23}
+	cronDays    = cronField{min: 1, max: 31}
+	cronMonths  = cronField{min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// Day 7 is Sunday, like day 0
+	cronWeekdays = cronField{min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// cronSchedule is a parsed cron expression
+type cronSchedule struct {
+	// Bit sets of the matching values of each field
+	seconds, minutes, hours, days, months, weekdays uint64
+	// A restricted day of month and day of week match when either does, like cron
+	anyDay, anyWeekday bool
+	// every is the interval of "@every <duration>" schedules
+	every    time.Duration
+	location *time.Location
+}
+
+// parseSchedule parses a cron expression: 5 fields (minute, hour, day of month, month, day of week),
+// 6 fields starting with seconds, a descriptor (@daily, @hourly, ...) or "@every <duration>"
+// A CRON_TZ= or TZ= prefix sets the time zone (default: UTC)
+func parseSchedule(spec string) (*cronSchedule, error) {
+	s := &cronSchedule{location: time.UTC}
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "CRON_TZ=") || strings.HasPrefix(spec, "TZ=") {
+		tz, rest, _ := strings.Cut(spec, " ")
+		loc, err := time.LoadLocation(tz[strings.Index(tz, "=")+1:])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone in schedule %q: %w", spec, err)
+		}
+		s.location = loc
+		spec = strings.TrimSpace(rest)
+	}
+
+	if d, ok := strings.CutPrefix(spec, "@every "); ok {
+		every, err := time.ParseDuration(strings.TrimSpace(d))
+		if err != nil || every <= 0 {
+			return nil, fmt.Errorf("invalid interval in schedule %q", spec)
+		}
+		s.every = every
+		return s, nil
+	}
+	if expr, ok := cronDescriptors[spec]; ok {
+		spec = expr
+	}
+
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 5:
+		fields = append([]string{"0"}, fields...)
+	case 6:
+	default:
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 or 6 fields", spec)
+	}
+
+	var err error
+	targets := []*uint64{&s.seconds, &s.minutes, &s.hours, &s.days, &s.months, &s.weekdays}
+	for i, field := range []cronField{cronSeconds, cronMinutes, cronHours, cronDays, cronMonths, cronWeekdays} {
+		if *targets[i], err = field.parse(fields[i]); err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+	}
+	if s.weekdays&(1<<7) != 0 {
+		s.weekdays |= 1
+	}
+	s.anyDay = fields[3] == "*" || fields[3] == "?"
+	s.anyWeekday = fields[5] == "*" || fields[5] == "?"
+	return s, nil
+}
+
+// parse returns the bit set of the values matched by a field: lists of values, ranges and steps (e.g., "1-5,*/15")
+func (f cronField) parse(expr string) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(expr, ",") {
+		rangeExpr, stepExpr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepExpr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", part)
+			}
+			step = n
+		}
+
+		lo, hi := f.min, f.max
+		if rangeExpr != "*" && rangeExpr != "?" {
+			first, last, isRange := strings.Cut(rangeExpr, "-")
+			var err error
+			if lo, err = f.value(first); err != nil {
+				return 0, err
+			}
+			hi = lo
+			if isRange {
+				if hi, err = f.value(last); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				hi = f.max
+			}
+			if hi < lo {
+				return 0, fmt.Errorf("invalid range %q", part)
+			}
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// value parses a number or a name (e.g., "mon") of the field
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid value %q (want %d-%d)", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// next returns the first time after t matching the schedule, or the zero time if none within 5 years
+func (s *cronSchedule) next(t time.Time) time.Time {
+	if s.every > 0 {
+		return t.Truncate(time.Second).Add(s.every)
+	}
+
+	t = t.In(s.location).Truncate(time.Second).Add(time.Second)
+	limit := t.AddDate(5, 0, 0)
+	for t.Before(limit) {
+		y, mo, d := t.Date()
+		h, mi, sec := t.Clock()
+		switch {
+		case s.months&(1<<uint(mo)) == 0:
+			t = time.Date(y, mo+1, 1, 0, 0, 0, 0, s.location)
+		case !s.matchDay(t):
+			t = time.Date(y, mo, d+1, 0, 0, 0, 0, s.location)
+		case s.hours&(1<<uint(h)) == 0:
+			t = time.Date(y, mo, d, h+1, 0, 0, 0, s.location)
+		case s.minutes&(1<<uint(mi)) == 0:
+			t = time.Date(y, mo, d, h, mi+1, 0, 0, s.location)
+		case s.seconds&(1<<uint(sec)) == 0:
+			t = time.Date(y, mo, d, h, mi, sec+1, 0, s.location)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// matchDay checks the day of month and the day of week of t
+func (s *cronSchedule) matchDay(t time.Time) bool {
+	day := s.days&(1<<uint(t.Day())) != 0
+	weekday := s.weekdays&(1<<uint(t.Weekday())) != 0
+	if s.anyDay || s.anyWeekday {
+		return day && weekday
+	}
+	return day || weekday
+}
+
+// NextRun returns the next time a cron schedule runs after from
+func NextRun(schedule string, from time.Time) (time.Time, error) {
+	s, err := parseSchedule(schedule)
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := s.next(from)
+	if next.IsZero() {
+		return next, fmt.Errorf("schedule %q never runs", schedule)
+	}
+	return next, nil
+}
+
+// isScheduleName checks if a flag or annotation name holds a schedule (e.g., "schedule", "cron")
+func isScheduleName(name string) bool {
+	name = strings.ToLower(name)
+	return strings.Contains(name, "schedule") || strings.Contains(name, "cron")
+}
+
+// isSchedule checks if a string is a valid cron schedule
+func isSchedule(spec string) bool {
+	_, err := parseSchedule(spec)
+	return err == nil
+}
+
+// schedule returns the cron schedule of a command: an annotation of its literal (Annotations: {"schedule": ...})
+// or the default value of a schedule flag, including the persistent flags of its ancestors
+func (n *commandNode) schedule() string {
+	for _, elt := range n.lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if key, ok := kv.Key.(*ast.Ident); !ok || key.Name != "Annotations" {
+			continue
+		}
+		annotations, ok := kv.Value.(*ast.CompositeLit)
+		if !ok {
+			continue
+		}
+		for _, a := range annotations.Elts {
+			entry, ok := a.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+			if name, ok := stringLiteral(entry.Key); ok && isScheduleName(name) {
+				if spec, ok := stringLiteral(entry.Value); ok && isSchedule(spec) {
+					return spec
+				}
+			}
+		}
+	}
+
+	flags := n.flags
+	seen := map[*commandNode]bool{n: true}
+	for p := n.parent; p != nil && !seen[p]; p = p.parent {
+		seen[p] = true
+		for _, flag := range p.flags {
+			if flag.persistent {
+				flags = append(flags, flag)
+			}
+		}
+	}
+	for _, flag := range flags {
+		if spec, ok := scheduleFlagDefault(flag.call); ok {
+			return spec
+		}
+	}
+	return ""
+}
+
+// scheduleFlagDefault returns the default value of a string flag named like a schedule
+// (String, StringP, StringVar and StringVarP definitions)
+func scheduleFlagDefault(call *ast.CallExpr) (string, bool) {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !strings.HasPrefix(sel.Sel.Name, "String") {
+		return "", false
+	}
+	method := strings.TrimPrefix(sel.Sel.Name, "String")
+	nameIndex := 0
+	if strings.HasPrefix(method, "Var") {
+		nameIndex = 1
+		method = strings.TrimPrefix(method, "Var")
+	}
+	valueIndex := nameIndex + 1
+	switch method {
+	case "":
+	case "P":
+		valueIndex++
+	default:
+		// StringSlice, StringToString, ... hold other values
+		return "", false
+	}
+	if valueIndex >= len(call.Args) {
+		return "", false
+	}
+
+	name, ok := stringLiteral(call.Args[nameIndex])
+	if !ok || !isScheduleName(name) {
+		return "", false
+	}
+	spec, ok := stringLiteral(call.Args[valueIndex])
+	if !ok || !isSchedule(spec) {
+		return "", false
+	}
+	return spec, true
+}
+
+// stringLiteral returns the value of a string literal expression
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// scheduleRegistrationFuncs are the methods registering a job on a scheduler with its cron schedule
+// as first argument (robfig/cron AddFunc and AddJob, gocron Cron)
+var scheduleRegistrationFuncs = map[string]bool{
+	"AddFunc": true,
+	"AddJob":  true,
+	"Cron":    true,
+}
+
+// findScheduleRegistration returns the schedule of the first registration call in a package directory
+// (e.g., c.AddFunc("0 3 * * *", run)), or an empty string
+func (a *Analyzer) findScheduleRegistration(pkgDir string) string {
+	entries, err := a.fs.ReadDir(pkgDir)
+	if err != nil {
+		return ""
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), filepath.Join(pkgDir, entry.Name()), nil, 0)
+		if err != nil {
+			continue
+		}
+
+		spec := ""
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || spec != "" {
+				return spec == ""
+			}
+			if sel, ok := call.Fun.(*ast.SelectorExpr); ok && scheduleRegistrationFuncs[sel.Sel.Name] && len(call.Args) > 0 {
+				if value, ok := stringLiteral(call.Args[0]); ok && isSchedule(value) {
+					spec = value
+				}
+			}
+			return spec == ""
+		})
+		if spec != "" {
+			return spec
+		}
+	}
+	return ""
+}
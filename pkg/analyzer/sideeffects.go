@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// pureBuiltins are builtin functions that cannot have side effects in a variable initializer
+var pureBuiltins = map[string]bool{
+	"append": true, "cap": true, "complex": true, "imag": true, "len": true,
+	"make": true, "max": true, "min": true, "new": true, "real": true,
+}
+
+// registrationPrefixes are prefixes of the names of functions and methods registering into global state
+// (e.g., sql.Register, prometheus.MustRegister, http.HandleFunc, expvar.Publish)
+var registrationPrefixes = []string{"Register", "MustRegister", "Handle", "Publish"}
+
+// registrationPackages are packages whose functions called at package level register into global state
+// (flags of the default flag set, expvar variables, promauto metrics)
+var registrationPackages = map[string]bool{"flag": true, "expvar": true, "promauto": true}
+
+// HasSideEffectChanges checks if changed lines touch package initialization with side effects:
+// a func init(), a blank variable initialized by a call (var _ = f()), or a package-level variable whose
+// initializer registers into global state (see registrationPrefixes and registrationPackages)
+// Other initializers (e.g., var ErrNotFound = errors.New(...)) only matter to the users of the variable
+// Such changes affect every importer regardless of symbol usage; nil changedLines means the whole file
+func (s *SymbolAnalyzer) HasSideEffectChanges(content []byte, changedLines []int) (bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, 0)
+	if err != nil {
+		return false, err
+	}
+
+	changedLineSet := make(map[int]bool)
+	for _, line := range changedLines {
+		changedLineSet[line] = true
+	}
+	isChanged := func(node ast.Node) bool {
+		if changedLines == nil {
+			return true
+		}
+		start, end := fset.Position(node.Pos()).Line, fset.Position(node.End()).Line
+		for line := start; line <= end; line++ {
+			if changedLineSet[line] {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil && d.Name.Name == "init" && isChanged(d) {
+				return true, nil
+			}
+		case *ast.GenDecl:
+			if d.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range d.Specs {
+				vs, ok := spec.(*ast.ValueSpec)
+				if !ok || !isChanged(vs) {
+					continue
+				}
+				blank := len(vs.Names) > 0
+				for _, name := range vs.Names {
+					blank = blank && name.Name == "_"
+				}
+				for _, value := range vs.Values {
+					if callsFunction(value, blank) {
+						return true, nil
+					}
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// callsFunction checks if an expression calls a function with side effects when evaluated: any function
+// but the pure builtins if anyCall is true, otherwise a registration function
+// Function literals are not evaluated, so calls inside them are ignored
+func callsFunction(expr ast.Expr, anyCall bool) bool {
+	found := false
+	ast.Inspect(expr, func(n ast.Node) bool {
+		if found {
+			return false
+		}
+		switch node := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.CallExpr:
+			if ident, ok := node.Fun.(*ast.Ident); ok && pureBuiltins[ident.Name] {
+				return true
+			}
+			found = anyCall || isRegistrationCall(node)
+			return !found
+		}
+		return true
+	})
+	return found
+}
+
+// isRegistrationCall checks if a call registers into global state, by the name of the function or method
+// or by its package
+func isRegistrationCall(call *ast.CallExpr) bool {
+	var name string
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		name = fun.Name
+	case *ast.SelectorExpr:
+		if pkg, ok := fun.X.(*ast.Ident); ok && registrationPackages[pkg.Name] {
+			return true
+		}
+		name = fun.Sel.Name
+	case *ast.IndexExpr:
+		return isRegistrationCall(&ast.CallExpr{Fun: fun.X})
+	}
+	for _, prefix := range registrationPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SymbolRef identifies an exported symbol of a package
+type SymbolRef struct {
+	// Package is the full package path (e.g., "github.com/org/repo/pkg/service")
+	Package string
+	// Symbol is the exported symbol name (function, type, variable or constant)
+	Symbol string
+	// Method is the method name when the reference is Type.Method (optional)
+	Method string
+}
+
+// String returns the reference in pkg.Symbol[.Method] form
+func (r SymbolRef) String() string {
+	if r.Method != "" {
+		return r.Package + "." + r.Symbol + "." + r.Method
+	}
+	return r.Package + "." + r.Symbol
+}
+
+// ParseSymbolRef parses a symbol reference of the form pkg.Symbol or pkg.Type.Method
+// The package may be a full package path or a path relative to the module (e.g., "pkg/service.GetUser")
+func (a *Analyzer) ParseSymbolRef(ref string) (SymbolRef, error) {
+	ref = strings.TrimSpace(ref)
+
+	// The symbol part starts after the last path separator
+	slash := strings.LastIndex(ref, "/")
+	dot := strings.Index(ref[slash+1:], ".")
+	if dot < 0 {
+		return SymbolRef{}, fmt.Errorf("invalid symbol reference %q: expected pkg.Symbol", ref)
+	}
+	dot += slash + 1
+
+	pkgPath := ref[:dot]
+	parts := strings.Split(ref[dot+1:], ".")
+	if len(parts) > 2 || parts[0] == "" {
+		return SymbolRef{}, fmt.Errorf("invalid symbol reference %q: expected pkg.Symbol or pkg.Type.Method", ref)
+	}
+
+	symbolRef := SymbolRef{Package: a.ResolvePackagePath(pkgPath), Symbol: parts[0]}
+	if len(parts) == 2 {
+		symbolRef.Method = parts[1]
+	}
+	return symbolRef, nil
+}
+
+// ResolvePackagePath converts a module-relative package path (e.g., "pkg/service") to a full package path
+// Full package paths within the module are returned as is
+func (a *Analyzer) ResolvePackagePath(pkgPath string) string {
+	pkgPath = strings.TrimSuffix(strings.TrimPrefix(pkgPath, "./"), "/")
+	if a.graph.isProjectPackage(pkgPath) {
+		return pkgPath
+	}
+	if pkgPath == "" || pkgPath == "." {
+		return a.config.ModulePath
+	}
+	return a.config.ModulePath + "/" + strings.TrimPrefix(pkgPath, "/")
+}
+
+// SimulateChange reports the resources that would be affected if the given symbols changed
+// No diff is needed, which makes it possible to estimate the impact of planned refactors
+func (a *Analyzer) SimulateChange(refs []SymbolRef) (*Impact, error) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	changes := make(map[string]*packageChanges)
+	for _, ref := range refs {
+		if !a.graph.HasPackage(ref.Package) {
+			return nil, fmt.Errorf("package %s not found in dependency graph", ref.Package)
+		}
+
+		pc, ok := changes[ref.Package]
+		if !ok {
+			pc = &packageChanges{}
+			changes[ref.Package] = pc
+		}
+
+		if ref.Method == "" {
+			pc.info.symbols = append(pc.info.symbols, ref.Symbol)
+			continue
+		}
+
+		// Type.Method: treat like a changed method implementation, which marks
+		// both the method name and the interface method as changed
+		pc.info.symbols = append(pc.info.symbols, ref.Method)
+		pc.info.interfaceMethods = append(pc.info.interfaceMethods, InterfaceMethodRange{
+			InterfaceName: ref.Symbol,
+			MethodName:    ref.Method,
+		})
+	}
+	// Simulated symbols of Config.IgnoreSymbols affect no resource, as they would in a diff
+	a.removeIgnoredChanges(changes)
+	packages := make([]string, 0, len(changes))
+	for pkgPath := range changes {
+		packages = append(packages, pkgPath)
+	}
+	sort.Strings(packages)
+
+	warnings := &warningCollector{warnings: append([]*AnalysisError(nil), a.warnings...)}
+	changedByPackage := make(map[string][]string)
+	timings := newImpactTimings()
+	start := time.Now()
+	affectedMap := make(map[string]*AffectedResource)
+	for _, pkgPath := range packages {
+		pkgStart := time.Now()
+		pc := changes[pkgPath]
+		pc.info.symbols = uniqueStrings(pc.info.symbols)
+		pc.info.interfaceMethods = uniqueInterfaceMethods(pc.info.interfaceMethods)
+		a.collectAffectedResources(pkgPath, pc.info, affectedMap, timings)
+		changedByPackage[pkgPath] = pc.symbolNames()
+		timings.addPackage(pkgPath, pkgStart)
+	}
+	timings.phase("check resources", start)
+
+	affected := a.finalizeAffectedResources(affectedMap)
+	affected = a.finishImpact(nil, changes, changedByPackage, affected, warnings, timings)
+	return &Impact{
+		Affected:       affected,
+		Warnings:       warnings.warnings,
+		ChangedSymbols: changedByPackage,
+		Summary:        Summarize(0, changedByPackage, affected, len(a.resources)),
+		Timings:        timings.timings(),
+	}, nil
+}
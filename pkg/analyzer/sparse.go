@@ -0,0 +1,254 @@
+package analyzer
+
+import (
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sparseGitClient is implemented by GitClients that can tell whether the work tree is a sparse checkout
+type sparseGitClient interface {
+	// IsSparseCheckout reports whether only part of the files of the work tree are checked out
+	IsSparseCheckout() bool
+}
+
+// IsSparseCheckout reports whether the work tree is a sparse checkout (git sparse-checkout)
+func (g *execGitClient) IsSparseCheckout() bool {
+	cmd := exec.Command("git", "config", "--bool", "core.sparseCheckout")
+	cmd.Dir = g.projectDir
+	out, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// importsMissingPackage checks if a package imports a project package go list did not find, as it does
+// when the imported package is left out of a sparse checkout
+func (a *Analyzer) importsMissingPackage(pkgPath string) bool {
+	for _, dep := range a.graph.GetDirectDeps(pkgPath) {
+		if !a.graph.HasPackage(dep) {
+			return true
+		}
+	}
+	return false
+}
+
+// SparseCheckoutDirs returns the directories, relative to the git root, that an analysis of changed files
+// needs in a sparse checkout, according to the graph exported by a previous analysis (e.g., of the base branch):
+//   - the directories of the changed files and of the configured non-Go inputs (sqlc configuration, queries
+//     and schema, OpenAPI specs, feature flag files)
+//   - the directories of the packages importing the changed packages transitively, as affected resources
+//     are found through all the dependencies of their entry packages, and the packages changed through
+//     non-Go inputs (sqlc and OpenAPI generated packages, feature flag constants, embedding packages)
+//   - the directories of the files embedded by those packages, without which go list fails
+//
+// The Analyzer only needs its configuration, as Analyze is run after the checkout. Returns false if a change
+// affects every package of the module (go.mod, go.work, ...), which needs a full checkout
+func (a *Analyzer) SparseCheckoutDirs(graph *GraphExport, changedFiles []string) ([]string, bool) {
+	pathPrefix := strings.Trim(path.Clean("/"+a.config.PathPrefix), "/")
+	repoDir := func(relDir string) string {
+		if dir := path.Join(pathPrefix, relDir); dir != "." {
+			return dir
+		}
+		return ""
+	}
+	pkgDir := func(pkgPath string) (string, bool) {
+		if pkgPath == graph.Module {
+			return pathPrefix, true
+		}
+		rel, ok := strings.CutPrefix(pkgPath, graph.Module+"/")
+		if !ok {
+			return "", false
+		}
+		return path.Join(pathPrefix, rel), true
+	}
+
+	importers := make(map[string][]string)
+	for _, edge := range graph.Edges {
+		importers[edge.To] = append(importers[edge.To], edge.From)
+	}
+	embedDirs := make(map[string][]string)
+	for _, node := range graph.Nodes {
+		embedDirs[node.Package] = node.EmbedDirs
+	}
+
+	dirs := make(map[string]bool)
+	var queue []string
+	visited := make(map[string]bool)
+	seed := func(pkgPath string) {
+		if !visited[pkgPath] {
+			visited[pkgPath] = true
+			queue = append(queue, pkgPath)
+		}
+	}
+
+	var relFiles []string
+	for _, file := range changedFiles {
+		if moduleFiles[path.Base(file)] {
+			return nil, false
+		}
+		dir := path.Dir(file)
+		if dir == "." {
+			dir = ""
+		}
+		dirs[dir] = true
+		rel, ok := strings.CutPrefix(dir+"/", pathPrefix+"/")
+		if pathPrefix == "" {
+			rel, ok = dir, true
+		}
+		if !ok {
+			continue
+		}
+		rel = strings.Trim(rel, "/")
+		relFiles = append(relFiles, path.Join(rel, path.Base(file)))
+		seed(strings.TrimSuffix(graph.Module+"/"+rel, "/"))
+	}
+
+	for _, input := range a.sparseInputs(graph, relFiles) {
+		dirs[repoDir(input.dir)] = true
+		if input.changed && input.pkgPath != "" {
+			seed(input.pkgPath)
+		}
+	}
+
+	for len(queue) > 0 {
+		pkgPath := queue[0]
+		queue = queue[1:]
+		if dir, ok := pkgDir(pkgPath); ok {
+			dirs[dir] = true
+		}
+		for _, dir := range embedDirs[pkgPath] {
+			dirs[repoDir(dir)] = true
+		}
+		for _, importer := range importers[pkgPath] {
+			seed(importer)
+		}
+	}
+
+	result := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		result = append(result, dir)
+	}
+	sort.Strings(result)
+	return result, true
+}
+
+// sparseInput is a directory of non-Go files read by the analysis, relative to the project root
+type sparseInput struct {
+	dir string
+	// pkgPath is the package changed when one of the files changes, if any
+	pkgPath string
+	// changed reports whether one of the changed files is among the files
+	changed bool
+}
+
+// sparseInputs returns the directories of the non-Go files the configuration makes the analysis read
+// relFiles are the changed files relative to the project root
+func (a *Analyzer) sparseInputs(graph *GraphExport, relFiles []string) []sparseInput {
+	var inputs []sparseInput
+	matches := func(pattern string) bool {
+		for _, file := range relFiles {
+			if matchGlob(pattern, file) {
+				return true
+			}
+		}
+		return false
+	}
+	// inputDir is the directory of a file, or the part of a glob pattern before its first wildcard
+	inputDir := func(pattern string) string {
+		if i := strings.IndexAny(pattern, "*?["); i >= 0 {
+			pattern = pattern[:i] + "x"
+		}
+		return path.Dir(pattern)
+	}
+
+	sqlcConfig := a.config.SqlcConfig
+	if sqlcConfig == "" {
+		sqlcConfig = sqlcConfigFiles[0]
+	}
+	inputs = append(inputs, sparseInput{dir: path.Dir(filepath.ToSlash(sqlcConfig))})
+	if cfg := a.loadSqlcConfig(a.config.SqlcConfig); cfg != nil {
+		for _, pkg := range cfg.packages {
+			paths := append(append([]string(nil), pkg.Queries...), pkg.Schema...)
+			changed := false
+			for _, file := range relFiles {
+				changed = changed || containsSQLPath(paths, file)
+			}
+			for _, p := range paths {
+				dir := p
+				if path.Ext(p) != "" {
+					dir = path.Dir(p)
+				}
+				inputs = append(inputs, sparseInput{dir: dir, pkgPath: a.ResolvePackagePath(pkg.Out), changed: changed})
+			}
+		}
+	}
+
+	for _, spec := range a.config.OpenAPISpecs {
+		inputs = append(inputs, sparseInput{
+			dir:     path.Dir(spec.Spec),
+			pkgPath: a.ResolvePackagePath(spec.Package),
+			changed: matches(spec.Spec),
+		})
+	}
+
+	for _, def := range a.config.FeatureFlags {
+		pkgPath := a.ResolvePackagePath(def.Constant)
+		if ref, err := a.ParseSymbolRef(def.Constant); err == nil {
+			pkgPath = ref.Package
+		}
+		for _, pattern := range def.Files {
+			inputs = append(inputs, sparseInput{dir: inputDir(pattern), pkgPath: pkgPath, changed: matches(pattern)})
+		}
+	}
+
+	// Embedded files may live in subdirectories of their package
+	for _, node := range graph.Nodes {
+		for _, dir := range node.EmbedDirs {
+			inputs = append(inputs, sparseInput{dir: dir, pkgPath: node.Package, changed: matches(dir + "/*")})
+		}
+	}
+	return inputs
+}
+
+// SparseCheckout restricts the work tree of the project to the files directly in the given directories,
+// relative to the git root ("" is the root), with a non-cone git sparse-checkout
+// Subdirectories are left out unless listed, so that a package does not bring in its subpackages.
+// A nil dirs disables the sparse checkout, checking out every file
+func SparseCheckout(projectDir string, dirs []string) error {
+	g := &execGitClient{projectDir: projectDir}
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return err
+	}
+
+	args := []string{"sparse-checkout", "disable"}
+	var patterns strings.Builder
+	if dirs != nil {
+		args = []string{"sparse-checkout", "set", "--no-cone", "--stdin"}
+		// Later patterns take precedence, so a directory is listed after its parent excluding it
+		sorted := append([]string(nil), dirs...)
+		sort.Strings(sorted)
+		for _, dir := range sorted {
+			dir = strings.Trim(dir, "/")
+			if dir != "" {
+				dir = "/" + dir
+			}
+			patterns.WriteString(dir + "/*\n!" + dir + "/*/\n")
+		}
+	}
+
+	cmd := exec.Command("git", args...)
+	cmd.Dir = gitRoot
+	cmd.Stdin = strings.NewReader(patterns.String())
+	if _, err := cmd.Output(); err != nil {
+		return gitError(err)
+	}
+	// A clone made with --no-checkout has an empty index, which sparse-checkout leaves empty
+	cmd = exec.Command("git", "read-tree", "-mu", "HEAD")
+	cmd.Dir = gitRoot
+	if _, err := cmd.Output(); err != nil {
+		return gitError(err)
+	}
+	return nil
+}
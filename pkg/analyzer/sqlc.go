@@ -0,0 +1,487 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// sqlcConfigFiles are the file names sqlc looks for, in order
+var sqlcConfigFiles = []string{"sqlc.yaml", "sqlc.yml", "sqlc.json"}
+
+// sqlcQueryNameRegex matches the query annotation of sqlc (e.g., "-- name: GetUser :one")
+var sqlcQueryNameRegex = regexp.MustCompile(`--\s*name:\s*(\w+)\s+:\w+`)
+
+// sqlcPackage is a Go package generated by sqlc
+type sqlcPackage struct {
+	// Queries are the query files or directories, relative to the project root
+	Queries []string
+	// Schema are the schema files or migration directories, relative to the project root
+	Schema []string
+	// Out is the output directory of the generated package, relative to the project root
+	Out string
+}
+
+// sqlcConfig holds the sqlc packages of the project
+type sqlcConfig struct {
+	packages []sqlcPackage
+}
+
+// loadSqlcConfig reads the sqlc configuration (sqlc.yaml / sqlc.yml / sqlc.json)
+// configPath is relative to the project root; if empty, the default file names are tried
+// Returns nil if no configuration is found
+func (a *Analyzer) loadSqlcConfig(configPath string) *sqlcConfig {
+	candidates := sqlcConfigFiles
+	if configPath != "" {
+		candidates = []string{configPath}
+	}
+
+	for _, candidate := range candidates {
+		absPath := filepath.Join(a.config.ProjectRoot, candidate)
+		content, err := a.fs.ReadFile(absPath)
+		if err != nil {
+			continue
+		}
+
+		var packages []sqlcPackage
+		if strings.HasSuffix(candidate, ".json") {
+			packages = parseSqlcJSON(content)
+		} else {
+			packages = parseSqlcYAML(content)
+		}
+
+		// Paths in the sqlc configuration are relative to the configuration file
+		baseDir := path.Dir(filepath.ToSlash(candidate))
+		for i := range packages {
+			packages[i].Out = path.Join(baseDir, packages[i].Out)
+			for j := range packages[i].Queries {
+				packages[i].Queries[j] = path.Join(baseDir, packages[i].Queries[j])
+			}
+			for j := range packages[i].Schema {
+				packages[i].Schema[j] = path.Join(baseDir, packages[i].Schema[j])
+			}
+		}
+
+		return &sqlcConfig{packages: packages}
+	}
+
+	return nil
+}
+
+// parseSqlcJSON parses a sqlc.json configuration (version 1 and 2)
+func parseSqlcJSON(content []byte) []sqlcPackage {
+	var raw struct {
+		SQL []struct {
+			Queries json.RawMessage `json:"queries"`
+			Schema  json.RawMessage `json:"schema"`
+			Gen     struct {
+				Go struct {
+					Out string `json:"out"`
+				} `json:"go"`
+			} `json:"gen"`
+		} `json:"sql"`
+		Packages []struct {
+			Path    string          `json:"path"`
+			Queries json.RawMessage `json:"queries"`
+			Schema  json.RawMessage `json:"schema"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(content, &raw); err != nil {
+		return nil
+	}
+
+	// queries and schema can be a string or a list of strings
+	parseQueries := func(msg json.RawMessage) []string {
+		var single string
+		if err := json.Unmarshal(msg, &single); err == nil {
+			return []string{single}
+		}
+		var list []string
+		_ = json.Unmarshal(msg, &list)
+		return list
+	}
+
+	var packages []sqlcPackage
+	for _, s := range raw.SQL {
+		if s.Gen.Go.Out == "" {
+			continue
+		}
+		packages = append(packages, sqlcPackage{Queries: parseQueries(s.Queries), Schema: parseQueries(s.Schema), Out: s.Gen.Go.Out})
+	}
+	for _, p := range raw.Packages {
+		packages = append(packages, sqlcPackage{Queries: parseQueries(p.Queries), Schema: parseQueries(p.Schema), Out: p.Path})
+	}
+	return packages
+}
+
+// parseSqlcYAML extracts the query paths and output directories from a sqlc.yaml configuration
+// It only understands the subset of YAML used by sqlc configurations:
+// entries of the "sql" (version 2) or "packages" (version 1) lists with "queries" and "schema" keys, and the
+// output directory in "gen: go: out:" (version 2) or "path" (version 1)
+// Keys are matched by their path in the entry, like parseOpenAPIOutline, so that the "out" of other
+// generators (e.g., gen: kotlin: out:) is not taken for the Go package
+func parseSqlcYAML(content []byte) []sqlcPackage {
+	var packages []sqlcPackage
+	var current *sqlcPackage
+
+	type level struct {
+		indent int
+		key    string
+	}
+	var stack []level // keys of the current entry enclosing the line
+
+	entryIndent := -1
+	inEntries := false
+	version2 := false
+	listKey := "" // key whose value continues as a "- item" list on the following lines
+	listIndent := 0
+
+	for _, rawLine := range strings.Split(string(content), "\n") {
+		line := strings.TrimRight(rawLine, " \t\r")
+		if idx := strings.Index(line, " #"); idx >= 0 {
+			line = line[:idx]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		// Top-level keys
+		if indent == 0 {
+			inEntries = trimmed == "sql:" || trimmed == "packages:"
+			version2 = trimmed == "sql:"
+			entryIndent = -1
+			listKey = ""
+			continue
+		}
+		if !inEntries {
+			continue
+		}
+
+		// Continuation of a block list (e.g., queries:\n  - a.sql)
+		if listKey != "" && indent > listIndent && strings.HasPrefix(trimmed, "- ") {
+			if current != nil {
+				item := yamlScalar(strings.TrimPrefix(trimmed, "- "))
+				if listKey == "queries" {
+					current.Queries = append(current.Queries, item)
+				} else {
+					current.Schema = append(current.Schema, item)
+				}
+			}
+			continue
+		}
+		listKey = ""
+
+		// A new entry of the sql/packages list
+		if strings.HasPrefix(trimmed, "- ") && (entryIndent < 0 || indent == entryIndent) {
+			entryIndent = indent
+			if current != nil {
+				packages = append(packages, *current)
+			}
+			current = &sqlcPackage{}
+			stack = nil
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+			indent += 2
+		} else if strings.HasPrefix(trimmed, "- ") {
+			// An item of a nested list (e.g., overrides), whose keys are indented past the dash
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+			indent += 2
+		}
+		if current == nil {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, level{indent: indent, key: key})
+		keys := make([]string, len(stack))
+		for i, l := range stack {
+			keys[i] = l.key
+		}
+		keyPath := strings.Join(keys, ".")
+
+		switch {
+		case keyPath == "queries" || keyPath == "schema":
+			if value == "" {
+				listKey = key
+				listIndent = indent
+				continue
+			}
+			if key == "queries" {
+				current.Queries = append(current.Queries, yamlList(value)...)
+			} else {
+				current.Schema = append(current.Schema, yamlList(value)...)
+			}
+		case version2 && keyPath == "gen.go.out", !version2 && keyPath == "path":
+			if value != "" {
+				current.Out = yamlScalar(value)
+			}
+		}
+	}
+
+	if current != nil {
+		packages = append(packages, *current)
+	}
+
+	var result []sqlcPackage
+	for _, p := range packages {
+		if p.Out != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// yamlScalar unquotes a YAML scalar value
+func yamlScalar(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// yamlList parses a YAML scalar or flow sequence ([a, b]) into a list
+func yamlList(value string) []string {
+	if !strings.HasPrefix(value, "[") || !strings.HasSuffix(value, "]") {
+		return []string{yamlScalar(value)}
+	}
+	var items []string
+	for _, item := range strings.Split(value[1:len(value)-1], ",") {
+		if item = yamlScalar(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// packageForQueryFile returns the sqlc package generated from a query file (relative to the project root)
+func (c *sqlcConfig) packageForQueryFile(relPath string) *sqlcPackage {
+	for i := range c.packages {
+		if containsSQLPath(c.packages[i].Queries, relPath) {
+			return &c.packages[i]
+		}
+	}
+	return nil
+}
+
+// packagesForSchemaFile returns the sqlc packages whose schema includes a file (relative to the project root)
+func (c *sqlcConfig) packagesForSchemaFile(relPath string) []*sqlcPackage {
+	var packages []*sqlcPackage
+	for i := range c.packages {
+		if containsSQLPath(c.packages[i].Schema, relPath) {
+			packages = append(packages, &c.packages[i])
+		}
+	}
+	return packages
+}
+
+// containsSQLPath checks if a file is one of the paths or inside one of the directories (relative to the project root)
+func containsSQLPath(paths []string, relPath string) bool {
+	for _, p := range paths {
+		p = strings.TrimPrefix(path.Clean(p), "./")
+		if relPath == p || strings.HasPrefix(relPath, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// isGeneratedQueryFile checks if a file (relative to the project root) is a sqlc-generated query file
+func (c *sqlcConfig) isGeneratedQueryFile(relPath string) bool {
+	if !strings.HasSuffix(relPath, ".sql.go") {
+		return false
+	}
+	dir := path.Dir(relPath)
+	for _, p := range c.packages {
+		if dir == strings.TrimPrefix(path.Clean(p.Out), "./") {
+			return true
+		}
+	}
+	return false
+}
+
+// sqlcQueryNamesFromSQL returns the names of the queries in SQL content whose blocks contain any of the lines
+// If lines is nil, all query names are returned
+func sqlcQueryNamesFromSQL(content []byte, lines []int) []string {
+	lineSet := make(map[int]bool)
+	for _, l := range lines {
+		lineSet[l] = true
+	}
+
+	var names []string
+	current := ""
+	for i, line := range strings.Split(string(content), "\n") {
+		if m := sqlcQueryNameRegex.FindStringSubmatch(line); m != nil {
+			current = m[1]
+		}
+		if current != "" && (lines == nil || lineSet[i+1]) {
+			names = append(names, current)
+		}
+	}
+	return uniqueStrings(names)
+}
+
+// sqlcQueryNamesFromGo returns the queries of a sqlc-generated Go file whose declarations contain any of the lines
+// Query methods, their SQL constants ("-- name: X") and their Params/Row types are mapped to the query name
+// If lines is nil, all query names are returned
+func sqlcQueryNamesFromGo(content []byte, lines []int) []string {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, 0)
+	if err != nil {
+		return nil
+	}
+
+	covers := func(n ast.Node) bool {
+		if lines == nil {
+			return true
+		}
+		start, end := fset.Position(n.Pos()).Line, fset.Position(n.End()).Line
+		for _, l := range lines {
+			if l >= start && l <= end {
+				return true
+			}
+		}
+		return false
+	}
+
+	var names []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && isExported(d.Name.Name) && covers(d) {
+				names = append(names, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if !covers(spec) {
+					continue
+				}
+				switch sp := spec.(type) {
+				case *ast.ValueSpec:
+					for _, v := range sp.Values {
+						if lit, ok := v.(*ast.BasicLit); ok {
+							if m := sqlcQueryNameRegex.FindStringSubmatch(lit.Value); m != nil {
+								names = append(names, m[1])
+							}
+						}
+					}
+				case *ast.TypeSpec:
+					name := sp.Name.Name
+					for _, suffix := range []string{"Params", "Row"} {
+						if strings.HasSuffix(name, suffix) && len(name) > len(suffix) {
+							names = append(names, strings.TrimSuffix(name, suffix))
+						}
+					}
+				}
+			}
+		}
+	}
+	return uniqueStrings(names)
+}
+
+// getChangedNames returns the names changed in a file (sqlc queries, OpenAPI operations)
+// parse extracts names from file content for the given lines (nil means all names)
+func (a *Analyzer) getChangedNames(absPath, origPath string, parse func(content []byte, lines []int) []string) []string {
+	diffResult, err := a.diffAnalyzer.GetChangedLinesWithDeleted(origPath)
+	if err != nil || diffResult == nil || (len(diffResult.AddedLines) == 0 && len(diffResult.DeletedLines) == 0) {
+		// No line-level information: every name in the file may have changed
+		content, err := a.fs.ReadFile(absPath)
+		if err != nil {
+			return nil
+		}
+		return parse(content, nil)
+	}
+
+	var names []string
+	if len(diffResult.AddedLines) > 0 {
+		if content, err := a.fs.ReadFile(absPath); err == nil {
+			names = append(names, parse(content, diffResult.AddedLines)...)
+		}
+	}
+	if len(diffResult.DeletedLines) > 0 {
+		if oldContent, err := a.config.GitClient.GetFileContentAtBase(origPath); err == nil {
+			names = append(names, parse(oldContent, diffResult.DeletedLines)...)
+		}
+	}
+	return uniqueStrings(names)
+}
+
+// sqlcQueryMethods converts query names to the methods of the generated package in pkgDir
+// The methods are named after the interface declaring them (Querier by default, emit_interface) or, without
+// an interface, after their receiver (Queries); "Querier" is assumed when the package is not generated yet
+func (a *Analyzer) sqlcQueryMethods(pkgDir string, names []string) []InterfaceMethodRange {
+	if len(names) == 0 {
+		return nil
+	}
+	owners := make(map[string]string)
+	for _, name := range names {
+		owners[name] = ""
+	}
+
+	if entries, err := a.fs.ReadDir(pkgDir); err == nil {
+		for _, entry := range entries {
+			if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+				continue
+			}
+			filePath := filepath.Join(pkgDir, entry.Name())
+			ranges, err := a.symbolAnalyzer.ExtractInterfaceMethodRanges(filePath)
+			if err == nil {
+				for _, m := range ranges {
+					// Interfaces take precedence over the receivers implementing them
+					if owner, ok := owners[m.MethodName]; ok && (owner == "" || strings.HasPrefix(owner, "*")) {
+						owners[m.MethodName] = m.InterfaceName
+					}
+				}
+			}
+			for name, receiver := range sqlcReceivers(a.fs, filePath) {
+				if owner, ok := owners[name]; ok && owner == "" {
+					owners[name] = "*" + receiver
+				}
+			}
+		}
+	}
+
+	methods := make([]InterfaceMethodRange, 0, len(names))
+	for _, name := range names {
+		owner := strings.TrimPrefix(owners[name], "*")
+		if owner == "" {
+			owner = "Querier"
+		}
+		methods = append(methods, InterfaceMethodRange{InterfaceName: owner, MethodName: name})
+	}
+	return methods
+}
+
+// sqlcReceivers returns the methods declared in a generated file with the name of their receiver type
+func sqlcReceivers(fs FileSystem, filePath string) map[string]string {
+	content, err := fs.ReadFile(filePath)
+	if err != nil {
+		return nil
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), filePath, content, parser.SkipObjectResolution)
+	if err != nil {
+		return nil
+	}
+	receivers := make(map[string]string)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && len(fn.Recv.List) > 0 {
+			receivers[fn.Name.Name] = extractTypeName(fn.Recv.List[0].Type)
+		}
+	}
+	return receivers
+}
@@ -0,0 +1,166 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// serializationPackages are import path prefixes of packages encoding values according to struct tags
+var serializationPackages = []string{
+	"encoding/json",
+	"encoding/xml",
+	"database/sql",
+	"github.com/jmoiron/sqlx",
+	"github.com/jackc/pgx",
+	"google.golang.org/protobuf",
+	"github.com/golang/protobuf",
+	"gopkg.in/yaml",
+	"sigs.k8s.io/yaml",
+}
+
+// FindTagOnlyChanges returns the struct types whose fields differ between two versions of a file only in their tags
+func (s *SymbolAnalyzer) FindTagOnlyChanges(oldContent, newContent []byte) ([]string, error) {
+	oldStructs, err := parseStructTypes(oldContent)
+	if err != nil {
+		return nil, err
+	}
+	newStructs, err := parseStructTypes(newContent)
+	if err != nil {
+		return nil, err
+	}
+
+	var types []string
+	for name, newSt := range newStructs {
+		oldSt, ok := oldStructs[name]
+		if !ok {
+			continue
+		}
+		if tagOnlyDifference(oldSt, newSt) {
+			types = append(types, name)
+		}
+	}
+	return types, nil
+}
+
+// parsedStruct is a struct type with the file set needed to render it
+type parsedStruct struct {
+	fset *token.FileSet
+	st   *ast.StructType
+}
+
+// parseStructTypes returns the struct types declared at package level, keyed by type name
+func parseStructTypes(content []byte) (map[string]parsedStruct, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", content, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	structs := make(map[string]parsedStruct)
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if st, ok := ts.Type.(*ast.StructType); ok {
+				structs[ts.Name.Name] = parsedStruct{fset: fset, st: st}
+			}
+		}
+	}
+	return structs, nil
+}
+
+// tagOnlyDifference checks if two structs have the same fields and differ in at least one tag
+func tagOnlyDifference(a, b parsedStruct) bool {
+	if len(a.st.Fields.List) != len(b.st.Fields.List) {
+		return false
+	}
+
+	tagChanged := false
+	for i, fa := range a.st.Fields.List {
+		fb := b.st.Fields.List[i]
+		if fieldNames(fa) != fieldNames(fb) || renderNode(a.fset, fa.Type) != renderNode(b.fset, fb.Type) {
+			return false
+		}
+		if fieldTag(fa) != fieldTag(fb) {
+			tagChanged = true
+		}
+	}
+	return tagChanged
+}
+
+// fieldNames joins the names of a struct field
+func fieldNames(field *ast.Field) string {
+	names := make([]string, 0, len(field.Names))
+	for _, name := range field.Names {
+		names = append(names, name.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+// fieldTag returns the raw tag of a struct field
+func fieldTag(field *ast.Field) string {
+	if field.Tag == nil {
+		return ""
+	}
+	return field.Tag.Value
+}
+
+// UsesSerialization checks if a package imports a serialization package or one of the given packages
+func (s *SymbolAnalyzer) UsesSerialization(pkgDir string, extraPackages []string) bool {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, parser.ImportsOnly)
+		if err != nil {
+			continue
+		}
+
+		for _, imp := range file.Imports {
+			impPath := strings.Trim(imp.Path.Value, `"`)
+			if contains(extraPackages, impPath) {
+				return true
+			}
+			for _, prefix := range serializationPackages {
+				if impPath == prefix || strings.HasPrefix(impPath, prefix+"/") {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// isResourceSerializing checks if a package of the resource depending on the changed package serializes values
+// (encoding/json, database/sql, protobuf or a sqlc-generated package)
+func (a *Analyzer) isResourceSerializing(resource *Resource, changedPkgPath string) bool {
+	var sqlcPackages []string
+	if a.sqlc != nil {
+		for _, p := range a.sqlc.packages {
+			sqlcPackages = append(sqlcPackages, a.dirToPackage(p.Out))
+		}
+	}
+
+	candidates := append([]string{resource.Package}, a.graph.GetAllDeps(resource.Package)...)
+	for _, pkg := range uniqueStrings(candidates) {
+		if pkg != changedPkgPath && !contains(a.graph.GetAllDeps(pkg), changedPkgPath) {
+			continue
+		}
+		if a.symbolAnalyzer.UsesSerialization(a.getPkgDir(pkg), sqlcPackages) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,207 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// gitlinkMode is the git tree mode of submodule pointers
+const gitlinkMode = "160000"
+
+// SubmoduleChange is a submodule whose pointer changed between the base branch and HEAD
+type SubmoduleChange struct {
+	// Path is the path of the submodule relative to the superproject root
+	Path string
+	// Dir is the checkout directory of the submodule
+	Dir string
+	// OldCommit and NewCommit are the commits the pointer moved from and to
+	// OldCommit is empty for added submodules, NewCommit for removed ones
+	OldCommit string
+	NewCommit string
+}
+
+// ChangedSubmodules returns the submodules of the repository of projectDir whose pointer changed relative to baseBranch
+func ChangedSubmodules(projectDir, baseBranch string) ([]SubmoduleChange, error) {
+	g := &execGitClient{projectDir: projectDir, baseBranch: baseBranch}
+	return g.changedSubmodules()
+}
+
+// SubmoduleChangedFiles returns the files changed inside a submodule between its old and new pointer commits,
+// relative to the submodule, without those matching the excludes (glob patterns relative to projectDir as
+// for NewGitClientWithExcludes)
+// The submodule must be checked out at the new commit, whose work tree the analysis reads
+func SubmoduleChangedFiles(projectDir string, excludes []string, change SubmoduleChange) ([]string, error) {
+	cmd := exec.Command("git", "rev-parse", "HEAD")
+	cmd.Dir = change.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the checkout: %w", gitError(err))
+	}
+	if head := strings.TrimSpace(string(out)); head != change.NewCommit {
+		return nil, fmt.Errorf("checked out at %s instead of %s (run git submodule update)", head, change.NewCommit)
+	}
+
+	files, err := change.changedFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to diff: %w", gitError(err))
+	}
+	g := &execGitClient{projectDir: projectDir, excludes: excludes}
+	var result []string
+	for _, file := range files {
+		if !g.excluded(change.Path + "/" + file) {
+			result = append(result, file)
+		}
+	}
+	return result, nil
+}
+
+// changedSubmodules returns the submodules whose pointer changed, listed once per client
+func (g *execGitClient) changedSubmodules() ([]SubmoduleChange, error) {
+	g.submodulesOnce.Do(func() {
+		g.submodules, g.submodulesErr = g.listChangedSubmodules()
+	})
+	return g.submodules, g.submodulesErr
+}
+
+// listChangedSubmodules reads the submodule pointer changes from the raw diff against the base branch
+func (g *execGitClient) listChangedSubmodules() ([]SubmoduleChange, error) {
+	gitRoot, err := g.GetRootDir()
+	if err != nil {
+		return nil, err
+	}
+	// Repositories without .gitmodules have no submodules: skip the diff
+	if _, err := os.Stat(filepath.Join(gitRoot, ".gitmodules")); err != nil {
+		return nil, nil
+	}
+
+	cmd := exec.Command("git", "diff", "--raw", "--no-abbrev", "--no-renames", g.baseBranch+"...HEAD")
+	cmd.Dir = gitRoot
+	out, err := cmd.Output()
+	if err != nil {
+		cmd = exec.Command("git", "diff", "--raw", "--no-abbrev", "--no-renames", g.baseBranch)
+		cmd.Dir = gitRoot
+		if out, err = cmd.Output(); err != nil {
+			return nil, err
+		}
+	}
+
+	var changes []SubmoduleChange
+	for _, line := range strings.Split(string(out), "\n") {
+		// :<old mode> <new mode> <old object> <new object> <status>\t<path>
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(strings.TrimPrefix(meta, ":"))
+		if !ok || len(fields) < 5 || (fields[0] != gitlinkMode && fields[1] != gitlinkMode) {
+			continue
+		}
+		change := SubmoduleChange{Path: path, Dir: filepath.Join(gitRoot, filepath.FromSlash(path))}
+		if fields[0] == gitlinkMode {
+			change.OldCommit = fields[2]
+		}
+		if fields[1] == gitlinkMode {
+			change.NewCommit = fields[3]
+		}
+		changes = append(changes, change)
+	}
+	return changes, nil
+}
+
+// submoduleOf returns the changed submodule containing a file (relative to the git root)
+// and the path of the file relative to the submodule
+func (g *execGitClient) submoduleOf(gitRelPath string) (SubmoduleChange, string, bool) {
+	changes, err := g.changedSubmodules()
+	if err != nil {
+		return SubmoduleChange{}, "", false
+	}
+	gitRelPath = filepath.ToSlash(gitRelPath)
+	for _, change := range changes {
+		if rel, ok := strings.CutPrefix(gitRelPath, change.Path+"/"); ok {
+			return change, rel, true
+		}
+	}
+	return SubmoduleChange{}, "", false
+}
+
+// expandSubmodules replaces the changed submodule pointers among the changed files by the files changed
+// inside the submodules, so that they are analyzed like files of the superproject
+// Pointers whose commits are not available (e.g., submodules not updated) are kept as they are
+func (g *execGitClient) expandSubmodules(files []string) []string {
+	changes, err := g.changedSubmodules()
+	if err != nil || len(changes) == 0 {
+		return files
+	}
+	byPath := make(map[string]SubmoduleChange)
+	for _, change := range changes {
+		byPath[change.Path] = change
+	}
+
+	var result []string
+	for _, file := range files {
+		change, ok := byPath[file]
+		if !ok {
+			result = append(result, file)
+			continue
+		}
+		subFiles, err := change.changedFiles()
+		if err != nil {
+			result = append(result, file)
+			continue
+		}
+		for _, subFile := range subFiles {
+			if path := change.Path + "/" + subFile; !g.excluded(path) {
+				result = append(result, path)
+			}
+		}
+	}
+	return result
+}
+
+// changedFiles returns the files changed between the old and new commits, relative to the submodule
+// All files of added (or removed) submodules are changed
+func (s SubmoduleChange) changedFiles() ([]string, error) {
+	args := []string{"diff", "--name-only", s.OldCommit, s.NewCommit}
+	switch {
+	case s.OldCommit == "":
+		args = []string{"ls-tree", "-r", "--name-only", s.NewCommit}
+	case s.NewCommit == "":
+		args = []string{"ls-tree", "-r", "--name-only", s.OldCommit}
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = s.Dir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// lineDiff returns the git diff -U0 of a file: against the base branch, or between the old and new
+// pointer commits for files of a changed submodule
+// Like GetChangedFiles, it falls back to a plain diff against the base branch without a merge base
+// (e.g., shallow clones), and reports git failures instead of an empty diff
+func (g *execGitClient) lineDiff(gitRoot, gitRelPath string) ([]byte, error) {
+	if sub, rel, ok := g.submoduleOf(gitRelPath); ok && sub.OldCommit != "" && sub.NewCommit != "" {
+		cmd := exec.Command("git", "diff", "-U0", sub.OldCommit, sub.NewCommit, "--", rel)
+		cmd.Dir = sub.Dir
+		out, err := cmd.Output()
+		return out, gitError(err)
+	}
+	cmd := exec.Command("git", "diff", "-U0", g.baseBranch+"...HEAD", "--", gitRelPath)
+	cmd.Dir = gitRoot
+	out, err := cmd.Output()
+	if err != nil {
+		cmd = exec.Command("git", "diff", "-U0", g.baseBranch, "--", gitRelPath)
+		cmd.Dir = gitRoot
+		out, err = cmd.Output()
+	}
+	return out, gitError(err)
+}
@@ -0,0 +1,47 @@
+package analyzer
+
+// Summary aggregates the statistics of an impact analysis
+type Summary struct {
+	ChangedFiles    int `json:"changed_files"`
+	ChangedPackages int `json:"changed_packages"`
+	ChangedSymbols  int `json:"changed_symbols"`
+	// AffectedByType counts the affected resources of each resource type
+	AffectedByType map[ResourceType]int `json:"affected_by_type"`
+	// AffectedPercent is the share of affected resources among all resources (0-100)
+	// Resources reported by external analyzers are not part of the project's resources and are left out
+	AffectedPercent float64 `json:"affected_percent"`
+	// MaxDepth is the longest dependency chain of an affected resource, in import edges
+	MaxDepth int `json:"max_depth"`
+}
+
+// Summarize computes the summary of an analysis from the number of changed files, the changed symbols of
+// each changed package and the affected resources out of the total number of resources
+func Summarize(changedFiles int, changedSymbols map[string][]string, affected []AffectedResource, totalResources int) *Summary {
+	summary := SummarizeAffected(affected, totalResources)
+	summary.ChangedFiles = changedFiles
+	for _, symbols := range changedSymbols {
+		summary.ChangedPackages++
+		summary.ChangedSymbols += len(uniqueStrings(symbols))
+	}
+	return summary
+}
+
+// SummarizeAffected computes the statistics of affected resources out of the total number of resources
+// Counts of changed files, packages and symbols are left to the caller
+func SummarizeAffected(affected []AffectedResource, totalResources int) *Summary {
+	summary := &Summary{AffectedByType: make(map[ResourceType]int)}
+	projectAffected := 0
+	for _, r := range affected {
+		if r.Type != ResourceTypeExternal {
+			projectAffected++
+		}
+		summary.AffectedByType[r.Type]++
+		if len(r.DependencyChain) > 1 {
+			summary.MaxDepth = max(summary.MaxDepth, len(r.DependencyChain)-1)
+		}
+	}
+	if totalResources > 0 {
+		summary.AffectedPercent = float64(projectAffected) * 100 / float64(totalResources)
+	}
+	return summary
+}
@@ -4,8 +4,11 @@ import (
 	"go/ast"
 	"go/parser"
 	"go/token"
+	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -14,12 +17,20 @@ type SymbolAnalyzer struct {
 	fset       *token.FileSet
 	modulePath string
 	projectDir string
+	// mu guards the caches below
+	mu sync.RWMutex
 	// File path -> exported symbols (functions, types, variables, constants)
 	fileSymbols map[string][]string
 	// Package path -> file paths within the package
 	packageFiles map[string][]string
+	// Package directory and imported package path -> usage of the imported package
+	usages map[string]*packageUsage
+	// Package directory -> reflection access
+	reflection map[string]*ReflectionAccess
 	// FileSystem for file operations
 	fs FileSystem
+	// cache shares exported symbols with other Analyzers (optional)
+	cache Cache
 }
 
 // NewSymbolAnalyzer creates a new SymbolAnalyzer
@@ -30,6 +41,8 @@ func NewSymbolAnalyzer(modulePath, projectDir string) *SymbolAnalyzer {
 		projectDir:   projectDir,
 		fileSymbols:  make(map[string][]string),
 		packageFiles: make(map[string][]string),
+		usages:       make(map[string]*packageUsage),
+		reflection:   make(map[string]*ReflectionAccess),
 		fs:           NewFileSystem(),
 	}
 }
@@ -42,6 +55,8 @@ func NewSymbolAnalyzerWithFS(modulePath, projectDir string, fs FileSystem) *Symb
 		projectDir:   projectDir,
 		fileSymbols:  make(map[string][]string),
 		packageFiles: make(map[string][]string),
+		usages:       make(map[string]*packageUsage),
+		reflection:   make(map[string]*ReflectionAccess),
 		fs:           fs,
 	}
 }
@@ -49,17 +64,36 @@ func NewSymbolAnalyzerWithFS(modulePath, projectDir string, fs FileSystem) *Symb
 // ExtractExportedSymbols extracts exported symbols from a Go file
 func (s *SymbolAnalyzer) ExtractExportedSymbols(filePath string) ([]string, error) {
 	// Check cache
-	if symbols, ok := s.fileSymbols[filePath]; ok {
+	s.mu.RLock()
+	symbols, ok := s.fileSymbols[filePath]
+	s.mu.RUnlock()
+	if ok {
 		return symbols, nil
 	}
 
-	file, err := parser.ParseFile(s.fset, filePath, nil, 0)
+	// src stays nil without a cache so that the parser reads the file
+	var src any
+	var key string
+	if s.cache != nil {
+		data, err := s.fs.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		src = data
+		key = contentKey("exported", data)
+		if symbols, ok := s.cache.Get(key); ok {
+			s.mu.Lock()
+			s.fileSymbols[filePath] = symbols
+			s.mu.Unlock()
+			return symbols, nil
+		}
+	}
+
+	file, err := parser.ParseFile(s.fset, filePath, src, 0)
 	if err != nil {
 		return nil, err
 	}
 
-	var symbols []string
-
 	ast.Inspect(file, func(n ast.Node) bool {
 		switch decl := n.(type) {
 		case *ast.FuncDecl:
@@ -88,7 +122,12 @@ func (s *SymbolAnalyzer) ExtractExportedSymbols(filePath string) ([]string, erro
 		return true
 	})
 
+	s.mu.Lock()
 	s.fileSymbols[filePath] = symbols
+	s.mu.Unlock()
+	if s.cache != nil {
+		s.cache.Set(key, symbols)
+	}
 	return symbols, nil
 }
 
@@ -312,21 +351,56 @@ func (s *SymbolAnalyzer) CheckSymbolUsage(pkgDir string, targetPkgPath string, s
 	if len(symbols) == 0 {
 		return false, nil
 	}
-
-	// Build symbol set for quick lookup
-	symbolSet := make(map[string]bool)
+	usage, err := s.packageUsage(pkgDir, targetPkgPath)
+	if err != nil {
+		return false, err
+	}
 	for _, sym := range symbols {
-		symbolSet[sym] = true
+		if usage.selectors[sym] || usage.dotIdents[sym] {
+			return true, nil
+		}
 	}
+	return false, nil
+}
 
-	// Get target package alias from its path
-	targetPkgName := filepath.Base(targetPkgPath)
+// packageUsage is what the files of a package use of an imported package
+// It is read once per pair of packages, as the same pairs are checked for many symbols (e.g., chain links)
+type packageUsage struct {
+	// selectors are the names selected on the import (pkg.Name)
+	selectors map[string]bool
+	// dotIdents are the identifiers of the files dot-importing the package, other than field and method names
+	dotIdents map[string]bool
+	// calls are the names of the methods called (x.Method(...)) in the files importing the package
+	calls map[string]bool
+	// receivers are the names of the methods declared in the package by receiver type, if one of its files
+	// imports the target (implementations of the interfaces of a package not imported anywhere cannot be passed to it)
+	receivers map[string]map[string]bool
+}
+
+// packageUsage returns the usage of an imported package by the package in a directory
+func (s *SymbolAnalyzer) packageUsage(pkgDir string, targetPkgPath string) (*packageUsage, error) {
+	key := pkgDir + "\x00" + targetPkgPath
+	s.mu.RLock()
+	usage, ok := s.usages[key]
+	s.mu.RUnlock()
+	if ok {
+		return usage, nil
+	}
 
-	// Parse all Go files in the package directory
 	entries, err := s.fs.ReadDir(pkgDir)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
+	usage = &packageUsage{
+		selectors: make(map[string]bool),
+		dotIdents: make(map[string]bool),
+		calls:     make(map[string]bool),
+		receivers: make(map[string]map[string]bool),
+	}
+	// Get target package alias from its path
+	targetPkgName := filepath.Base(targetPkgPath)
+	declared := make(map[string]map[string]bool)
+	imported := false
 
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
@@ -339,6 +413,17 @@ func (s *SymbolAnalyzer) CheckSymbolUsage(pkgDir string, targetPkgPath string, s
 			continue
 		}
 
+		// Implementations may live in files without the import (e.g., handlers with only built-in parameter types)
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil && len(fn.Recv.List) > 0 {
+				recv := extractTypeName(fn.Recv.List[0].Type)
+				if declared[recv] == nil {
+					declared[recv] = make(map[string]bool)
+				}
+				declared[recv][fn.Name.Name] = true
+			}
+		}
+
 		// Find the import alias for the target package
 		importAlias := ""
 		for _, imp := range file.Imports {
@@ -353,71 +438,92 @@ func (s *SymbolAnalyzer) CheckSymbolUsage(pkgDir string, targetPkgPath string, s
 			}
 		}
 
+		// Only files importing the target package use it
+		// This prevents false positives from methods with the same name on different interfaces
 		if importAlias == "" || importAlias == "_" {
 			continue
 		}
-
-		// Check if any of the symbols are used
-		found := false
-		ast.Inspect(file, func(n ast.Node) bool {
-			if found {
-				return false
-			}
-
-			sel, ok := n.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
-
-			ident, ok := sel.X.(*ast.Ident)
-			if !ok {
-				return true
-			}
-
-			// Check if it's accessing the target package
-			if ident.Name == importAlias {
-				// Check if the accessed symbol is in our list
-				if symbolSet[sel.Sel.Name] {
-					found = true
+		imported = true
+
+		var inspect func(n ast.Node) bool
+		inspect = func(n ast.Node) bool {
+			switch x := n.(type) {
+			case *ast.CallExpr:
+				// Method calls on variables are assumed to be related to the imported package
+				if sel, ok := x.Fun.(*ast.SelectorExpr); ok {
+					usage.calls[sel.Sel.Name] = true
+				}
+			case *ast.SelectorExpr:
+				if ident, ok := x.X.(*ast.Ident); ok && ident.Name == importAlias {
+					usage.selectors[x.Sel.Name] = true
+				}
+				if importAlias == "." {
+					// x.Sel is a field or method name, not a package-level symbol
+					ast.Inspect(x.X, inspect)
 					return false
 				}
+			case *ast.Ident:
+				// Dot imports make symbols accessible without a qualifier
+				if importAlias == "." {
+					usage.dotIdents[x.Name] = true
+				}
 			}
-
 			return true
-		})
-
-		if found {
-			return true, nil
 		}
+		ast.Inspect(file, inspect)
+	}
+	if imported {
+		usage.receivers = declared
 	}
 
-	return false, nil
+	s.mu.Lock()
+	s.usages[key] = usage
+	s.mu.Unlock()
+	return usage, nil
 }
 
 // GetPackageDir returns the directory for a package path
 func (s *SymbolAnalyzer) GetPackageDir(pkgPath string) string {
 	// Remove module path prefix to get relative path
-	relPath := strings.TrimPrefix(pkgPath, s.modulePath)
-	relPath = strings.TrimPrefix(relPath, "/")
-	return filepath.Join(s.projectDir, relPath)
+	relPath := pkgPath
+	if pkgPath == s.modulePath || strings.HasPrefix(pkgPath, s.modulePath+"/") {
+		relPath = strings.TrimPrefix(pkgPath[len(s.modulePath):], "/")
+	}
+	return filepath.FromSlash(path.Join(slashPath(s.projectDir), relPath))
 }
 
 // FileToPackagePath converts a file path to its package path
+// Returns an empty string for files outside the project
 func (s *SymbolAnalyzer) FileToPackagePath(filePath string) string {
 	// Get directory
-	dir := filepath.Dir(filePath)
+	dir := path.Dir(slashPath(filePath))
 
 	// Convert to relative path from project root
-	relDir, err := filepath.Rel(s.projectDir, dir)
+	relDir, err := filepath.Rel(filepath.FromSlash(slashPath(s.projectDir)), filepath.FromSlash(dir))
 	if err != nil {
 		return ""
 	}
+	relDir = filepath.ToSlash(relDir)
+	if relDir == ".." || strings.HasPrefix(relDir, "../") {
+		return ""
+	}
 
 	if relDir == "." {
 		return s.modulePath
 	}
 
-	return s.modulePath + "/" + filepath.ToSlash(relDir)
+	return s.modulePath + "/" + relDir
+}
+
+// slashPath cleans a file path with forward slashes, whatever the OS it comes from: Windows paths
+// (C:\project\pkg) may reach the analyzer through diffs, configuration or another OS's output
+// The drive letter is upper-cased, as Windows paths are case-insensitive
+func slashPath(p string) string {
+	p = strings.ReplaceAll(p, `\`, "/")
+	if len(p) >= 2 && p[1] == ':' {
+		p = strings.ToUpper(p[:1]) + p[1:]
+	}
+	return path.Clean(p)
 }
 
 // FunctionRange represents the line range of a function or method
@@ -603,6 +709,9 @@ type InterfaceMethodRange struct {
 	MethodName    string
 	StartLine     int
 	EndLine       int
+	// MatchImplementations also counts declarations of the method as usage
+	// (e.g., handlers implementing a generated server interface)
+	MatchImplementations bool
 }
 
 // ExtractInterfaceMethodRanges extracts all interface method ranges from a Go file
@@ -727,94 +836,53 @@ func (s *SymbolAnalyzer) CheckMethodCallUsage(pkgDir string, targetPkgPath strin
 	if len(methods) == 0 {
 		return false, nil
 	}
-
-	// Build method set for quick lookup (just method names, since we check calls)
-	methodSet := make(map[string]bool)
-	for _, m := range methods {
-		methodSet[m.MethodName] = true
-	}
-
-	// Get target package alias from its path
-	targetPkgName := filepath.Base(targetPkgPath)
-
-	// Parse all Go files in the package directory
-	entries, err := s.fs.ReadDir(pkgDir)
+	usage, err := s.packageUsage(pkgDir, targetPkgPath)
 	if err != nil {
 		return false, err
 	}
+	for _, m := range methods {
+		if usage.calls[m.MethodName] || (m.MatchImplementations && s.implementsMethod(usage, targetPkgPath, m)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
+// implementsMethod checks if a type of a package declares the method of an interface of the target package
+// along with all the other methods of the interface; without the interface (e.g., a generated package not
+// generated yet), declaring a method of the same name is enough
+func (s *SymbolAnalyzer) implementsMethod(usage *packageUsage, targetPkgPath string, m InterfaceMethodRange) bool {
+	var required []string
+	targetDir := s.GetPackageDir(targetPkgPath)
+	entries, _ := s.fs.ReadDir(targetDir)
 	for _, entry := range entries {
 		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
 			continue
 		}
-
-		filePath := filepath.Join(pkgDir, entry.Name())
-		file, err := parser.ParseFile(s.fset, filePath, nil, 0)
+		ranges, err := s.ExtractInterfaceMethodRanges(filepath.Join(targetDir, entry.Name()))
 		if err != nil {
 			continue
 		}
-
-		// Find the import alias for the target package
-		importAlias := ""
-		for _, imp := range file.Imports {
-			impPath := strings.Trim(imp.Path.Value, `"`)
-			if impPath == targetPkgPath {
-				if imp.Name != nil {
-					importAlias = imp.Name.Name
-				} else {
-					importAlias = targetPkgName
-				}
-				break
+		for _, r := range ranges {
+			if r.InterfaceName == m.InterfaceName {
+				required = append(required, r.MethodName)
 			}
 		}
+	}
 
-		// Only check files that import the target package
-		// This prevents false positives from methods with the same name on different interfaces
-		if importAlias == "" || importAlias == "_" {
+	for _, declared := range usage.receivers {
+		if !declared[m.MethodName] {
 			continue
 		}
-
-		// Check for method calls
-		found := false
-		ast.Inspect(file, func(n ast.Node) bool {
-			if found {
-				return false
-			}
-
-			// Check for method call: obj.MethodName(...)
-			callExpr, ok := n.(*ast.CallExpr)
-			if !ok {
-				return true
-			}
-
-			sel, ok := callExpr.Fun.(*ast.SelectorExpr)
-			if !ok {
-				return true
-			}
-
-			// Check if calling a method that matches our changed methods
-			if methodSet[sel.Sel.Name] {
-				// Direct package access: pkg.MethodName(...)
-				if ident, ok := sel.X.(*ast.Ident); ok && ident.Name == importAlias {
-					found = true
-					return false
-				}
-				// Method call on variable (interface implementations)
-				// Since we confirmed this file imports the target package,
-				// we can assume the method call is related to that package
-				found = true
-				return false
-			}
-
+		implements := true
+		for _, name := range required {
+			implements = implements && declared[name]
+		}
+		if implements {
 			return true
-		})
-
-		if found {
-			return true, nil
 		}
 	}
-
-	return false, nil
+	return false
 }
 
 // ChangedSymbolInfo contains information about changed symbols including interface methods
@@ -1017,6 +1085,14 @@ func (s *SymbolAnalyzer) CheckSymbolUsesSymbols(pkgDir string, targetPkgPath str
 			continue
 		}
 
+		// Dot imports make symbols accessible without a qualifier
+		if importAlias == "." {
+			if len(findDotImportedUses(symbolNode, symbolSet)) > 0 {
+				return true, nil
+			}
+			continue
+		}
+
 		// Check if the symbol uses any of the target symbols
 		found := false
 		ast.Inspect(symbolNode, func(n ast.Node) bool {
@@ -1255,3 +1331,99 @@ func (s *SymbolAnalyzer) CheckSymbolUsesInterfaceMethods(pkgDir string, targetPk
 
 	return false, nil
 }
+
+// GetStringConstant returns the value of a string constant declared in a package directory
+func (s *SymbolAnalyzer) GetStringConstant(pkgDir string, name string) (string, bool) {
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return "", false
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		filePath := filepath.Join(pkgDir, entry.Name())
+		file, err := parser.ParseFile(s.fset, filePath, nil, 0)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, ident := range valueSpec.Names {
+					if ident.Name != name || i >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						return "", false
+					}
+					value, err := strconv.Unquote(lit.Value)
+					if err != nil {
+						return "", false
+					}
+					return value, true
+				}
+			}
+		}
+	}
+
+	return "", false
+}
+
+// GetStringConstants returns the values of the exported string constants declared in a package directory
+func (s *SymbolAnalyzer) GetStringConstants(pkgDir string) map[string]string {
+	constants := make(map[string]string)
+	entries, err := s.fs.ReadDir(pkgDir)
+	if err != nil {
+		return constants
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(s.fset, filepath.Join(pkgDir, entry.Name()), nil, 0)
+		if err != nil {
+			continue
+		}
+
+		for _, decl := range file.Decls {
+			genDecl, ok := decl.(*ast.GenDecl)
+			if !ok || genDecl.Tok != token.CONST {
+				continue
+			}
+			for _, spec := range genDecl.Specs {
+				valueSpec, ok := spec.(*ast.ValueSpec)
+				if !ok {
+					continue
+				}
+				for i, ident := range valueSpec.Names {
+					if !ident.IsExported() || i >= len(valueSpec.Values) {
+						continue
+					}
+					lit, ok := valueSpec.Values[i].(*ast.BasicLit)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					if value, err := strconv.Unquote(lit.Value); err == nil {
+						constants[ident.Name] = value
+					}
+				}
+			}
+		}
+	}
+
+	return constants
+}
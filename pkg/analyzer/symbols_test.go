@@ -0,0 +1,62 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestGetPackageDir(t *testing.T) {
+	tests := []struct {
+		name       string
+		projectDir string
+		pkgPath    string
+		want       string
+	}{
+		{"unix", "/src/project", "example.com/mod/pkg/util", "/src/project/pkg/util"},
+		{"unix root package", "/src/project", "example.com/mod", "/src/project"},
+		{"unix trailing slash", "/src/project/", "example.com/mod/pkg", "/src/project/pkg"},
+		{"module path prefix of another module", "/src/project", "example.com/module/pkg", "/src/project/example.com/module/pkg"},
+		{"windows", `C:\src\project`, "example.com/mod/pkg/util", "C:/src/project/pkg/util"},
+		{"windows root package", `C:\src\project`, "example.com/mod", "C:/src/project"},
+		{"windows lower-case drive", `c:\src\project`, "example.com/mod/pkg", "C:/src/project/pkg"},
+		{"windows mixed separators", `C:\src/project\`, "example.com/mod/pkg", "C:/src/project/pkg"},
+		{"relative", `..\project`, "example.com/mod/pkg", "../project/pkg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSymbolAnalyzer("example.com/mod", tt.projectDir)
+			if got, want := s.GetPackageDir(tt.pkgPath), filepath.FromSlash(tt.want); got != want {
+				t.Errorf("GetPackageDir(%q) = %q, want %q", tt.pkgPath, got, want)
+			}
+		})
+	}
+}
+
+func TestFileToPackagePath(t *testing.T) {
+	tests := []struct {
+		name       string
+		projectDir string
+		filePath   string
+		want       string
+	}{
+		{"unix", "/src/project", "/src/project/pkg/util/util.go", "example.com/mod/pkg/util"},
+		{"unix root package", "/src/project", "/src/project/main.go", "example.com/mod"},
+		{"unix outside the project", "/src/project", "/src/other/pkg/a.go", ""},
+		{"unix sibling with the project as prefix", "/src/project", "/src/project2/a.go", ""},
+		{"windows", `C:\src\project`, `C:\src\project\pkg\util\util.go`, "example.com/mod/pkg/util"},
+		{"windows root package", `C:\src\project`, `C:\src\project\main.go`, "example.com/mod"},
+		{"windows lower-case drive", `C:\src\project`, `c:\src\project\pkg\a.go`, "example.com/mod/pkg"},
+		{"windows forward slashes", `C:\src\project`, "C:/src/project/pkg/a.go", "example.com/mod/pkg"},
+		{"windows other drive", `C:\src\project`, `D:\src\project\pkg\a.go`, ""},
+		{"windows outside the project", `C:\src\project`, `C:\src\other\a.go`, ""},
+		{"relative", "project", `project\cmd\api\main.go`, "example.com/mod/cmd/api"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSymbolAnalyzer("example.com/mod", tt.projectDir)
+			if got := s.FileToPackagePath(tt.filePath); got != tt.want {
+				t.Errorf("FileToPackagePath(%q) = %q, want %q", tt.filePath, got, tt.want)
+			}
+		})
+	}
+}
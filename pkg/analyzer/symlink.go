@@ -0,0 +1,92 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// addSymlinkedPackages adds the project packages imported through symlinked directories to the graph
+// go list ./... skips symlinks to directories, so that such a package (e.g., gen/api linking to generated/api)
+// is only known from the imports of its importers, and changes to its files (which git lists under the
+// target directory) would never reach them. The packages are listed by import path, and each is made an
+// alias of the package of its target directory
+func (a *Analyzer) addSymlinkedPackages() {
+	root, err := filepath.Abs(a.config.ProjectRoot)
+	if err != nil {
+		return
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return
+	}
+
+	tried := make(map[string]bool)
+	for {
+		var unlisted []string
+		for _, pkgPath := range a.graph.unlistedImports() {
+			if !tried[pkgPath] {
+				tried[pkgPath] = true
+				unlisted = append(unlisted, pkgPath)
+			}
+		}
+		if len(unlisted) == 0 {
+			return
+		}
+
+		packages, err := a.config.GoListClient.ListPackages(a.config.ProjectRoot, unlisted...)
+		if err != nil {
+			a.config.Logger.Debug("failed to list imported packages", "packages", unlisted, "error", err)
+			return
+		}
+		// Other unlisted imports are missing or excluded by build constraints: go list ./... reports their importers
+		var symlinked []PackageInfo
+		for _, pkg := range packages {
+			if pkg.Dir == "" || !tried[pkg.ImportPath] {
+				continue
+			}
+			realDir, err := filepath.EvalSymlinks(pkg.Dir)
+			if err != nil || realDir == filepath.Clean(pkg.Dir) {
+				continue
+			}
+			symlinked = append(symlinked, pkg)
+			if rel, err := filepath.Rel(realRoot, realDir); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				a.graph.addAlias(pkg.ImportPath, a.dirToPackage(rel))
+			}
+		}
+		if len(symlinked) == 0 {
+			return
+		}
+		a.graph.addPackages(symlinked)
+	}
+}
+
+// unlistedImports returns the project packages imported by packages of the graph but missing from it
+func (g *DependencyGraph) unlistedImports() []string {
+	seen := make(map[string]bool)
+	var unlisted []string
+	for _, deps := range g.deps {
+		for _, dep := range deps {
+			if _, ok := g.deps[dep]; !ok && !seen[dep] {
+				seen[dep] = true
+				unlisted = append(unlisted, dep)
+			}
+		}
+	}
+	sort.Strings(unlisted)
+	return unlisted
+}
+
+// addAlias records that two packages are built from the same directory
+func (g *DependencyGraph) addAlias(pkgPath, alias string) {
+	if pkgPath == alias || contains(g.aliases[pkgPath], alias) {
+		return
+	}
+	g.aliases[pkgPath] = append(g.aliases[pkgPath], alias)
+	g.aliases[alias] = append(g.aliases[alias], pkgPath)
+}
+
+// GetAliases returns the packages built from the same directory as a package through symlinks
+func (g *DependencyGraph) GetAliases(pkgPath string) []string {
+	return g.aliases[pkgPath]
+}
@@ -0,0 +1,48 @@
+package analyzer
+
+import (
+	"sort"
+	"strings"
+)
+
+// testsToRun returns the test packages of a resource, passed to go test to verify it
+// Config.TestPackages sets them by resource name; otherwise they are the resource package
+// and its subpackages that have _test.go files
+func (a *Analyzer) testsToRun(r Resource) []string {
+	if patterns, ok := a.config.TestPackages[r.Name]; ok {
+		tests := make([]string, 0, len(patterns))
+		for _, pattern := range patterns {
+			tests = append(tests, a.ResolvePackagePath(pattern))
+		}
+		return tests
+	}
+	if r.Package == "" || !a.graph.isProjectPackage(r.Package) {
+		return nil
+	}
+
+	var tests []string
+	for _, pkgPath := range a.graph.GetAllPackages() {
+		if pkgPath != r.Package && !strings.HasPrefix(pkgPath, r.Package+"/") {
+			continue
+		}
+		if a.hasTestFiles(a.getPkgDir(pkgPath)) {
+			tests = append(tests, pkgPath)
+		}
+	}
+	sort.Strings(tests)
+	return tests
+}
+
+// hasTestFiles checks if a package directory contains _test.go files
+func (a *Analyzer) hasTestFiles(pkgDir string) bool {
+	entries, err := a.fs.ReadDir(pkgDir)
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_test.go") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,149 @@
+package analyzer
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Timing is the time spent on a phase, a changed package or a resource
+type Timing struct {
+	Name     string        `json:"name"`
+	Duration time.Duration `json:"duration_ns"`
+}
+
+// Timings records how long the analysis took, to find the packages and resources dominating the runtime
+type Timings struct {
+	// Phases of Analyze or of an impact analysis, in execution order
+	Phases []Timing `json:"phases"`
+	// Packages are the changed packages by time spent checking their dependent resources, slowest first
+	Packages []Timing `json:"packages"`
+	// Resources are the resources by time spent checking their symbol usage, slowest first
+	Resources []Timing `json:"resources"`
+}
+
+// timingRecorder collects the timings of Analyze; GetTimings may read them concurrently, so it has its own lock
+type timingRecorder struct {
+	mu            sync.Mutex
+	analyzePhases []Timing
+}
+
+// newTimingRecorder creates an empty recorder
+func newTimingRecorder() *timingRecorder {
+	return &timingRecorder{}
+}
+
+// startAnalyze discards the timings of the previous Analyze
+func (t *timingRecorder) startAnalyze() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.analyzePhases = nil
+}
+
+// analyzePhase records a phase of Analyze that started at start
+func (t *timingRecorder) analyzePhase(name string, start time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.analyzePhases = append(t.analyzePhases, Timing{Name: name, Duration: time.Since(start)})
+}
+
+// impactTimings collects the timings of one impact analysis, returned in its Impact
+// Each analysis has its own, so that concurrent analyses on the same Analyzer neither mix nor discard their timings
+type impactTimings struct {
+	phases        []Timing
+	packageTimes  map[string]time.Duration
+	resourceTimes map[string]time.Duration
+}
+
+// newImpactTimings creates the timings of an impact analysis
+func newImpactTimings() *impactTimings {
+	return &impactTimings{
+		packageTimes:  make(map[string]time.Duration),
+		resourceTimes: make(map[string]time.Duration),
+	}
+}
+
+// phase records a phase of the impact analysis that started at start
+func (t *impactTimings) phase(name string, start time.Time) {
+	t.phases = append(t.phases, Timing{Name: name, Duration: time.Since(start)})
+}
+
+// addPackage adds the time since start to a changed package
+func (t *impactTimings) addPackage(pkgPath string, start time.Time) {
+	t.packageTimes[pkgPath] += time.Since(start)
+}
+
+// addResource adds the time since start to a resource
+func (t *impactTimings) addResource(name string, start time.Time) {
+	t.resourceTimes[name] += time.Since(start)
+}
+
+// timings returns the recorded timings
+func (t *impactTimings) timings() *Timings {
+	return &Timings{
+		Phases:    append([]Timing{}, t.phases...),
+		Packages:  slowestFirst(t.packageTimes),
+		Resources: slowestFirst(t.resourceTimes),
+	}
+}
+
+// GetTimings returns the timings of Analyze; those of impact analyses are in Impact.Timings
+func (a *Analyzer) GetTimings() *Timings {
+	t := a.timings
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return &Timings{
+		Phases:    append([]Timing{}, t.analyzePhases...),
+		Packages:  []Timing{},
+		Resources: []Timing{},
+	}
+}
+
+// MergeTimings combines timings, e.g. those of Analyze and of the impact analyses of a run
+// Phases of the same name are added up in the order they first appear, and so are packages and resources
+func MergeTimings(timings ...*Timings) *Timings {
+	var phases []Timing
+	phaseIndex := make(map[string]int)
+	packageTimes := make(map[string]time.Duration)
+	resourceTimes := make(map[string]time.Duration)
+	for _, t := range timings {
+		if t == nil {
+			continue
+		}
+		for _, p := range t.Phases {
+			if i, ok := phaseIndex[p.Name]; ok {
+				phases[i].Duration += p.Duration
+				continue
+			}
+			phaseIndex[p.Name] = len(phases)
+			phases = append(phases, p)
+		}
+		for _, p := range t.Packages {
+			packageTimes[p.Name] += p.Duration
+		}
+		for _, r := range t.Resources {
+			resourceTimes[r.Name] += r.Duration
+		}
+	}
+	return &Timings{
+		Phases:    append([]Timing{}, phases...),
+		Packages:  slowestFirst(packageTimes),
+		Resources: slowestFirst(resourceTimes),
+	}
+}
+
+// slowestFirst converts durations by name into timings sorted by duration (descending), then by name
+func slowestFirst(durations map[string]time.Duration) []Timing {
+	timings := make([]Timing, 0, len(durations))
+	for name, d := range durations {
+		timings = append(timings, Timing{Name: name, Duration: d})
+	}
+	sort.Slice(timings, func(i, j int) bool {
+		if timings[i].Duration != timings[j].Duration {
+			return timings[i].Duration > timings[j].Duration
+		}
+		return timings[i].Name < timings[j].Name
+	})
+	return timings
+}
@@ -0,0 +1,52 @@
+package analyzer
+
+import (
+	"go/ast"
+	"go/build/constraint"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+)
+
+// isToolsFile checks if a file is excluded from builds unless the tools tag is set (//go:build tools)
+// Such files pin the versions of tools (e.g., code generators) with blank imports and are never compiled into binaries
+func isToolsFile(file *ast.File) bool {
+	for _, group := range file.Comments {
+		if group.Pos() >= file.Package {
+			break
+		}
+		for _, c := range group.List {
+			if !constraint.IsGoBuild(c.Text) {
+				continue
+			}
+			expr, err := constraint.Parse(c.Text)
+			if err != nil {
+				return false
+			}
+			withTools := expr.Eval(func(tag string) bool { return tag == "tools" })
+			withoutTags := expr.Eval(func(string) bool { return false })
+			return withTools && !withoutTags
+		}
+	}
+	return false
+}
+
+// toolImports returns the imports of the tools files among the files excluded from the build of a package
+func toolImports(dir string, ignoredFiles []string) []string {
+	fset := token.NewFileSet()
+	var imports []string
+	for _, name := range ignoredFiles {
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.ImportsOnly|parser.ParseComments)
+		if err != nil || !isToolsFile(file) {
+			continue
+		}
+		for _, imp := range file.Imports {
+			imports = append(imports, strings.Trim(imp.Path.Value, `"`))
+		}
+	}
+	return uniqueStrings(imports)
+}
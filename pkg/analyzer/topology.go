@@ -0,0 +1,159 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// ServiceDefinition declares the gRPC methods a resource serves and consumes over the network
+// Methods are written as "package.Service/Method" and may use glob patterns (e.g., "user.v1.UserService/*")
+type ServiceDefinition struct {
+	// Resource is the name of the resource
+	Resource string `json:"resource"`
+	// Serves are the gRPC methods the resource implements
+	Serves []string `json:"serves"`
+	// Consumes are the gRPC methods the resource calls
+	Consumes []string `json:"consumes"`
+}
+
+// TopicDefinition declares the resources publishing to and consuming from a message-queue topic
+type TopicDefinition struct {
+	// Topic is the topic name
+	Topic string `json:"topic"`
+	// Constant is a Go string constant holding the topic name (e.g., "pkg/events.TopicUserCreated")
+	// When set, the topic name is read from the source, keeping the configuration in sync with the code
+	Constant string `json:"constant,omitempty"`
+	// Publishers are the resources producing messages on the topic
+	Publishers []string `json:"publishers"`
+	// Consumers are the resources consuming messages from the topic
+	Consumers []string `json:"consumers"`
+}
+
+// matchMethod checks if two gRPC method specifications overlap
+func matchMethod(a, b string) bool {
+	if a == b {
+		return true
+	}
+	if matched, err := path.Match(a, b); err == nil && matched {
+		return true
+	}
+	if matched, err := path.Match(b, a); err == nil && matched {
+		return true
+	}
+	return false
+}
+
+// addNetworkConsumers adds resources that call gRPC methods served by affected resources
+// and resources consuming topics published by affected resources
+// Compile-time dependencies cannot see these edges, so they come from Config.Services and Config.Topics
+// An affected server is assumed to affect all the methods it serves; propagation is transitive
+func (a *Analyzer) addNetworkConsumers(affectedMap map[string]*AffectedResource) {
+	if len(a.config.Services) == 0 && len(a.topics) == 0 {
+		return
+	}
+
+	// Resources to propagate from, sorted so that a consumer reached from several servers
+	// gets the same reason on every run
+	queue := make([]string, 0, len(affectedMap))
+	for name := range affectedMap {
+		queue = append(queue, name)
+	}
+	sort.Strings(queue)
+
+	for len(queue) > 0 {
+		serverName := queue[0]
+		queue = queue[1:]
+		server := affectedMap[serverName]
+
+		for _, topic := range a.topics {
+			if !contains(topic.Publishers, serverName) {
+				continue
+			}
+			for _, consumerName := range topic.Consumers {
+				if _, exists := affectedMap[consumerName]; exists {
+					continue
+				}
+				resource := a.getResourceByName(consumerName)
+				if resource == nil {
+					continue
+				}
+				affectedMap[consumerName] = &AffectedResource{
+					Resource:        *resource,
+					Reason:          fmt.Sprintf("consumes topic %s published by %s", topic.Topic, serverName),
+					AffectedPackage: server.AffectedPackage,
+					DependencyChain: []string{},
+				}
+				queue = append(queue, consumerName)
+			}
+		}
+
+		for _, serverDef := range a.config.Services {
+			if serverDef.Resource != serverName {
+				continue
+			}
+
+			for _, consumerDef := range a.config.Services {
+				if _, exists := affectedMap[consumerDef.Resource]; exists {
+					continue
+				}
+
+				method := ""
+				for _, served := range serverDef.Serves {
+					for _, consumed := range consumerDef.Consumes {
+						if matchMethod(served, consumed) {
+							// Prefer the concrete method name for the reason
+							method = consumed
+							if strings.ContainsAny(consumed, "*?[") {
+								method = served
+							}
+							break
+						}
+					}
+					if method != "" {
+						break
+					}
+				}
+				if method == "" {
+					continue
+				}
+
+				resource := a.getResourceByName(consumerDef.Resource)
+				if resource == nil {
+					continue
+				}
+
+				affectedMap[consumerDef.Resource] = &AffectedResource{
+					Resource:        *resource,
+					Reason:          fmt.Sprintf("calls %s served by %s", method, serverName),
+					AffectedPackage: server.AffectedPackage,
+					DependencyChain: []string{},
+				}
+				queue = append(queue, consumerDef.Resource)
+			}
+		}
+	}
+}
+
+// resolveTopics resolves topic names declared through Go constants
+// Topics whose constant cannot be resolved keep their configured name, and are left out without one
+func (a *Analyzer) resolveTopics() []TopicDefinition {
+	topics := make([]TopicDefinition, 0, len(a.config.Topics))
+	for _, topic := range a.config.Topics {
+		if topic.Constant != "" {
+			if ref, err := a.ParseSymbolRef(topic.Constant); err == nil {
+				pkgDir := a.symbolAnalyzer.GetPackageDir(ref.Package)
+				if value, ok := a.symbolAnalyzer.GetStringConstant(pkgDir, ref.Symbol); ok {
+					topic.Topic = value
+				}
+			}
+		}
+		if topic.Topic == "" {
+			a.config.Logger.Debug("skipping topic without a name", "constant", topic.Constant)
+			continue
+		}
+		topics = append(topics, topic)
+	}
+	return topics
+}
@@ -0,0 +1,74 @@
+package analyzer
+
+import "sort"
+
+// OrphanedResource is a resource whose entry package cannot be found
+type OrphanedResource struct {
+	Resource
+	Reason string `json:"reason"`
+}
+
+// UnreachableReport lists potential dead code in the project
+type UnreachableReport struct {
+	// UnreachablePackages are project packages that no resource depends on
+	UnreachablePackages []string `json:"unreachable_packages"`
+	// OrphanedResources are resources whose entry package no longer exists
+	OrphanedResources []OrphanedResource `json:"orphaned_resources"`
+}
+
+// FindUnreachable reports packages no resource depends on and resources whose entry package is missing
+// Packages that depend on a resource package (e.g., cli/cmd and main packages) are wiring, not dead code
+func (a *Analyzer) FindUnreachable() *UnreachableReport {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	report := &UnreachableReport{
+		UnreachablePackages: []string{},
+		OrphanedResources:   []OrphanedResource{},
+	}
+
+	resourcePackages := make(map[string]bool)
+	for _, r := range a.resources {
+		switch {
+		case r.Package == "":
+			report.OrphanedResources = append(report.OrphanedResources, OrphanedResource{
+				Resource: r,
+				Reason:   "entry package could not be determined",
+			})
+		case !a.graph.HasPackage(r.Package):
+			report.OrphanedResources = append(report.OrphanedResources, OrphanedResource{
+				Resource: r,
+				Reason:   "entry package " + r.Package + " does not exist",
+			})
+		default:
+			resourcePackages[r.Package] = true
+		}
+	}
+
+	for _, pkgPath := range a.graph.GetAllPackages() {
+		if len(a.reverseDeps[pkgPath]) > 0 || resourcePackages[pkgPath] {
+			continue
+		}
+
+		// Skip packages that wire resources together
+		isWiring := false
+		for _, dep := range a.graph.GetAllDeps(pkgPath) {
+			if resourcePackages[dep] {
+				isWiring = true
+				break
+			}
+		}
+		if isWiring {
+			continue
+		}
+
+		report.UnreachablePackages = append(report.UnreachablePackages, pkgPath)
+	}
+
+	sort.Strings(report.UnreachablePackages)
+	sort.Slice(report.OrphanedResources, func(i, j int) bool {
+		return report.OrphanedResources[i].Name < report.OrphanedResources[j].Name
+	})
+
+	return report
+}
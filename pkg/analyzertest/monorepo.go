@@ -0,0 +1,340 @@
+// Package analyzertest generates synthetic monorepos to benchmark and test the analyzer without a Go toolchain or git
+package analyzertest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/laut0104/go-impact-analyzer/pkg/analyzer"
+)
+
+// ModulePath is the module path of generated monorepos
+const ModulePath = "example.com/monorepo"
+
+// MonorepoOptions sizes a generated monorepo; zero fields get their default
+type MonorepoOptions struct {
+	// Packages is the number of library packages (default: 100)
+	Packages int `json:"packages"`
+	// Resources is the number of resources, cycling through APIs, jobs and workers (default: 10)
+	Resources int `json:"resources"`
+	// Imports is the number of library packages imported by each library package and resource (default: 3)
+	// Library packages only import packages generated before them, so that lower ones are imported the most
+	Imports int `json:"imports"`
+	// Symbols is the number of exported functions of each library package (default: 5)
+	Symbols int `json:"symbols"`
+}
+
+// withDefaults returns the options with the defaults of zero fields
+func (o MonorepoOptions) withDefaults() MonorepoOptions {
+	if o.Packages <= 0 {
+		o.Packages = 100
+	}
+	if o.Resources <= 0 {
+		o.Resources = 10
+	}
+	if o.Imports <= 0 {
+		o.Imports = 3
+	}
+	if o.Symbols <= 0 {
+		o.Symbols = 5
+	}
+	return o
+}
+
+// String returns the options in benchmark name form (e.g., "packages=100,resources=10,imports=3,symbols=5")
+func (o MonorepoOptions) String() string {
+	o = o.withDefaults()
+	return fmt.Sprintf("packages=%d,resources=%d,imports=%d,symbols=%d", o.Packages, o.Resources, o.Imports, o.Symbols)
+}
+
+// Monorepo is a generated project: library packages under pkg/, resource packages under svc/
+// and their cobra commands in cli/cmd
+type Monorepo struct {
+	// Dir is the project root
+	Dir     string
+	Options MonorepoOptions
+	// Packages is the package listing go list would report
+	Packages []analyzer.PackageInfo
+	// changedLines are the lines reported as changed for each file (relative to Dir):
+	// the body of the first function of the file
+	changedLines map[string][]int
+}
+
+// Generate writes a monorepo into dir
+func Generate(dir string, opts MonorepoOptions) (*Monorepo, error) {
+	opts = opts.withDefaults()
+	m := &Monorepo{Dir: dir, Options: opts, changedLines: make(map[string][]int)}
+
+	if err := m.write("go.mod", "module "+ModulePath+"\n\ngo 1.23\n"); err != nil {
+		return nil, err
+	}
+	for i := 0; i < opts.Packages; i++ {
+		if err := m.writeLibrary(i); err != nil {
+			return nil, err
+		}
+	}
+	for r := 0; r < opts.Resources; r++ {
+		if err := m.writeResource(r); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.writeCommands(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// New generates a monorepo into a temporary directory of the test or benchmark, failing it on errors
+func New(tb testing.TB, opts MonorepoOptions) *Monorepo {
+	tb.Helper()
+	m, err := Generate(tb.TempDir(), opts)
+	if err != nil {
+		tb.Fatalf("failed to generate monorepo: %v", err)
+	}
+	return m
+}
+
+// libraryName returns the package name of a library package
+func libraryName(i int) string {
+	return fmt.Sprintf("lib%04d", i)
+}
+
+// resourceName returns the package name of a resource package
+func resourceName(r int) string {
+	return fmt.Sprintf("svc%04d", r)
+}
+
+// libraryImports returns the library packages imported by library package i (or by a resource, with i = -1 - r)
+func (m *Monorepo) libraryImports(i int) []int {
+	limit := i
+	if i < 0 {
+		limit = m.Options.Packages
+	}
+	if limit == 0 {
+		return nil
+	}
+	seen := make(map[int]bool)
+	var imports []int
+	for k := 1; k <= m.Options.Imports; k++ {
+		j := ((i+limit)*7 + k*13) % limit
+		if j < 0 {
+			j += limit
+		}
+		if !seen[j] {
+			seen[j] = true
+			imports = append(imports, j)
+		}
+	}
+	sort.Ints(imports)
+	return imports
+}
+
+// LibraryFile returns the file of a library package relative to Dir
+func (m *Monorepo) LibraryFile(i int) string {
+	return filepath.Join("pkg", libraryName(i), libraryName(i)+".go")
+}
+
+// UsedLibraryFile returns the file of a library package a resource uses, so that changing it affects the resource
+func (m *Monorepo) UsedLibraryFile(r int) string {
+	return m.LibraryFile(m.libraryImports(-1 - r)[0])
+}
+
+// writeLibrary writes library package i: exported functions calling the imported packages and an unexported helper
+func (m *Monorepo) writeLibrary(i int) error {
+	name := libraryName(i)
+	imports := m.libraryImports(i)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", name)
+	writeImports(&b, imports)
+	var body []int
+	for s := 0; s < m.Options.Symbols; s++ {
+		fmt.Fprintf(&b, "// Func%d is a synthetic function\n", s)
+		fmt.Fprintf(&b, "func Func%d() int {\n", s)
+		if s == 0 {
+			body = append(body, strings.Count(b.String(), "\n")+1)
+		}
+		// FuncN calls FuncN of the imported packages, so that changes propagate along the import chains
+		calls := []string{"helper()"}
+		for _, j := range imports {
+			calls = append(calls, fmt.Sprintf("%s.Func%d()", libraryName(j), s))
+		}
+		fmt.Fprintf(&b, "\treturn %s\n}\n\n", strings.Join(calls, " + "))
+	}
+	fmt.Fprintf(&b, "func helper() int {\n\treturn %d\n}\n", i)
+
+	file := m.LibraryFile(i)
+	m.changedLines[file] = body
+	m.addPackage("pkg/"+name, imports)
+	return m.write(file, b.String())
+}
+
+// writeResource writes the package run by resource r
+func (m *Monorepo) writeResource(r int) error {
+	name := resourceName(r)
+	imports := m.libraryImports(-1 - r)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "package %s\n\n", name)
+	writeImports(&b, imports)
+	b.WriteString("// Run runs the resource\nfunc Run() error {\n")
+	body := []int{strings.Count(b.String(), "\n") + 1}
+	for k, j := range imports {
+		fmt.Fprintf(&b, "\t_ = %s.Func%d()\n", libraryName(j), k%m.Options.Symbols)
+	}
+	b.WriteString("\treturn nil\n}\n")
+
+	file := filepath.Join("svc", name, name+".go")
+	m.changedLines[file] = body
+	m.addPackage("svc/"+name, imports)
+	return m.write(file, b.String())
+}
+
+// resourceFiles are the command files of each resource type, which the extractor maps to resource types
+var resourceFiles = []string{"api.go", "job.go", "worker.go"}
+
+// writeCommands writes a cobra command per resource into the command file of its type
+func (m *Monorepo) writeCommands() error {
+	var cmdImports []string
+	for t, fileName := range resourceFiles {
+		var b strings.Builder
+		var commands strings.Builder
+		b.WriteString("package cmd\n\nimport (\n")
+		for r := t; r < m.Options.Resources; r += len(resourceFiles) {
+			name := resourceName(r)
+			fmt.Fprintf(&b, "\t%q\n", ModulePath+"/svc/"+name)
+			cmdImports = append(cmdImports, ModulePath+"/svc/"+name)
+			fmt.Fprintf(&commands, "\nvar %sCmd = &cobra.Command{\n", name)
+			fmt.Fprintf(&commands, "\tUse:   %q,\n", fmt.Sprintf("svc-%04d", r))
+			fmt.Fprintf(&commands, "\tShort: %q,\n", "Synthetic "+strings.TrimSuffix(fileName, ".go"))
+			fmt.Fprintf(&commands, "\tRunE: func(cmd *cobra.Command, args []string) error {\n\t\treturn %s.Run()\n\t},\n}\n", name)
+		}
+		b.WriteString("\n\t\"github.com/spf13/cobra\"\n)\n")
+		b.WriteString(commands.String())
+		if err := m.write(filepath.Join("cli", "cmd", fileName), b.String()); err != nil {
+			return err
+		}
+	}
+	sort.Strings(cmdImports)
+	m.Packages = append(m.Packages, analyzer.PackageInfo{
+		ImportPath: ModulePath + "/cli/cmd",
+		Dir:        filepath.Join(m.Dir, "cli", "cmd"),
+		Imports:    append(cmdImports, "github.com/spf13/cobra"),
+	})
+	return nil
+}
+
+// writeImports writes the import declaration of library packages
+func writeImports(b *strings.Builder, imports []int) {
+	if len(imports) == 0 {
+		return
+	}
+	b.WriteString("import (\n")
+	for _, j := range imports {
+		fmt.Fprintf(b, "\t%q\n", ModulePath+"/pkg/"+libraryName(j))
+	}
+	b.WriteString(")\n\n")
+}
+
+// addPackage adds a package importing library packages to the listing
+func (m *Monorepo) addPackage(rel string, imports []int) {
+	pkg := analyzer.PackageInfo{
+		ImportPath: ModulePath + "/" + rel,
+		Dir:        filepath.Join(m.Dir, filepath.FromSlash(rel)),
+	}
+	for _, j := range imports {
+		pkg.Imports = append(pkg.Imports, ModulePath+"/pkg/"+libraryName(j))
+	}
+	m.Packages = append(m.Packages, pkg)
+}
+
+// write writes a file relative to Dir
+func (m *Monorepo) write(rel, content string) error {
+	path := filepath.Join(m.Dir, rel)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(content), 0o644)
+}
+
+// Config returns an Analyzer configuration of the monorepo whose GitClient reports the given files
+// (relative to Dir) as changed in the body of their first function
+func (m *Monorepo) Config(changedFiles ...string) analyzer.Config {
+	return analyzer.Config{
+		ModulePath:   ModulePath,
+		ProjectRoot:  m.Dir,
+		BaseBranch:   "main",
+		GoListClient: goListClient{packages: m.Packages},
+		GitClient:    &gitClient{repo: m, changedFiles: changedFiles},
+	}
+}
+
+// goListClient reports the generated package listing
+type goListClient struct {
+	packages []analyzer.PackageInfo
+}
+
+// ListPackages returns the generated packages, or those of the given directories
+func (c goListClient) ListPackages(dir string, patterns ...string) ([]analyzer.PackageInfo, error) {
+	if len(patterns) == 1 && patterns[0] == "./..." {
+		return c.packages, nil
+	}
+	var packages []analyzer.PackageInfo
+	for _, pattern := range patterns {
+		for _, pkg := range c.packages {
+			if pkg.Dir == filepath.Join(dir, filepath.FromSlash(pattern)) {
+				packages = append(packages, pkg)
+			}
+		}
+	}
+	return packages, nil
+}
+
+// gitClient reports changes to the first function of the changed files; the base branch has the same files
+type gitClient struct {
+	repo         *Monorepo
+	changedFiles []string
+}
+
+// rel returns the path of a file relative to the project root
+func (g *gitClient) rel(filePath string) string {
+	if rel, err := filepath.Rel(g.repo.Dir, filePath); err == nil && filepath.IsAbs(filePath) {
+		return rel
+	}
+	return filepath.Clean(filePath)
+}
+
+// GetChangedFiles returns the files given to Config
+func (g *gitClient) GetChangedFiles(baseBranch string) ([]string, error) {
+	return g.changedFiles, nil
+}
+
+// GetChangedLines returns the body of the first function of a file
+func (g *gitClient) GetChangedLines(filePath string) ([]int, error) {
+	return g.repo.changedLines[g.rel(filePath)], nil
+}
+
+// GetChangedLinesWithDeleted reports the body of the first function of a file as rewritten
+func (g *gitClient) GetChangedLinesWithDeleted(filePath string) (*analyzer.DiffResult, error) {
+	lines := g.repo.changedLines[g.rel(filePath)]
+	return &analyzer.DiffResult{AddedLines: lines, DeletedLines: lines}, nil
+}
+
+// GetRootDir returns the project root
+func (g *gitClient) GetRootDir() (string, error) {
+	return g.repo.Dir, nil
+}
+
+// GetFileContentAtBase returns the current content of a file
+func (g *gitClient) GetFileContentAtBase(filePath string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(g.repo.Dir, g.rel(filePath)))
+}
+
+// IsNewFile reports that all files exist on the base branch
+func (g *gitClient) IsNewFile(filePath string) (bool, error) {
+	return false, nil
+}